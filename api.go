@@ -0,0 +1,93 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Registrar 注册服务的稳定接口，*Weave[T] 满足该接口
+// 封装 weave 的代码只依赖该接口时，可通过 ProvideTo 以类型安全的方式注册服务
+type Registrar[T any] interface {
+	Register(name string, placeholder any, builder func(*T) any) error
+	Tag(name string, tags ...string) error
+}
+
+// Builder 构建容器的稳定接口，*Weave[T] 满足该接口
+type Builder interface {
+	Build() error
+	Ready(fn func())
+}
+
+// GraphProvider 获取与输出依赖图谱的稳定接口，*Weave[T] 满足该接口
+type GraphProvider interface {
+	GetDependencyGraph() *DependencyGraph
+	GenerateDOTGraph(opts ...DOTOption) string
+	PrintDependencyGraph(opts ...PrintOption) string
+}
+
+// *Weave[T] 必须满足所有稳定接口，方法签名变化时在此处编译失败
+var (
+	_ Registrar[struct{}] = (*Weave[struct{}])(nil)
+	_ ServiceResolver     = (*Weave[struct{}])(nil)
+	_ Builder             = (*Weave[struct{}])(nil)
+	_ GraphProvider       = (*Weave[struct{}])(nil)
+)
+
+// API 按职责拆分的容器接口，封装方可只保存用到的部分
+type API[T any] struct {
+	Registrar Registrar[T]
+	Resolver  ServiceResolver
+	Builder   Builder
+	Graph     GraphProvider
+}
+
+// NewAPI 创建容器并返回其拆分后的接口
+func NewAPI[T any](ctx *T, opts ...Option) API[T] {
+	di := New[T](opts...)
+	di.SetCtx(ctx)
+	return APIOf(di)
+}
+
+// APIOf 返回已有容器拆分后的接口
+func APIOf[T any](di *Weave[T]) API[T] {
+	return API[T]{Registrar: di, Resolver: di, Builder: di, Graph: di}
+}
+
+// Register 注册服务的非泛型入口，通常通过 ProvideTo 调用
+// placeholder 为服务实例的指针类型的值（可以是 nil 指针，如 (*Config)(nil)），
+// builder 返回的实例类型必须与之一致，否则构建失败并返回 *ErrTypeMismatch
+func (s *Weave[T]) Register(name string, placeholder any, builder func(*T) any) error {
+	typ := reflect.TypeOf(placeholder)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return fmt.Errorf("service [%s] placeholder must be a pointer, got %T", name, placeholder)
+	}
+	if builder == nil {
+		return fmt.Errorf("service [%s] builder is nil", name)
+	}
+	s.assign(name, reflect.New(typ.Elem()).Interface(), typedBuilder(name, typ, builder))
+	return nil
+}
+
+// typedBuilder 包装 any 形式的 builder，返回的实例类型与 typ 不符时以 *ErrTypeMismatch panic
+func typedBuilder[T any](name string, typ reflect.Type, builder func(*T) any) func(*T) any {
+	return func(ctx *T) any {
+		instance := builder(ctx)
+		if instance == nil || isNilPointer(instance) {
+			return nil
+		}
+		if got := reflect.TypeOf(instance); got != typ {
+			panic(&ErrTypeMismatch{Name: name, Want: typ.String(), Got: got.String()})
+		}
+		return instance
+	}
+}
+
+// ProvideTo 通过 Registrar 注册服务，与 Provide 等价
+func ProvideTo[T any, R any](r Registrar[T], name string, builder func(*T) *R) error {
+	return r.Register(name, (*R)(nil), func(ctx *T) any {
+		if instance := builder(ctx); instance != nil {
+			return instance
+		}
+		return nil
+	})
+}
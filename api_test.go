@@ -0,0 +1,76 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// wireThroughAPI 只依赖窄接口的封装代码
+func wireThroughAPI(r Registrar[TestContext], resolver ServiceResolver) error {
+	if err := ProvideTo(r, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: ctx.Config}
+	}); err != nil {
+		return err
+	}
+	if err := r.Tag("serviceA", "core"); err != nil {
+		return err
+	}
+	return ProvideTo(r, "serviceB", func(ctx *TestContext) *ServiceB {
+		cache := ResolveOr(resolver, "cache", &ServiceC{Name: "noop"})
+		return &ServiceB{Name: cache.Name, ServiceA: MustResolve[ServiceA](resolver, "serviceA")}
+	})
+}
+
+func TestDI_API(t *testing.T) {
+	api := NewAPI(&TestContext{Config: "test"})
+	if err := wireThroughAPI(api.Registrar, api.Resolver); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	readied := false
+	api.Builder.Ready(func() { readied = true })
+	if err := api.Builder.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if !readied {
+		t.Error("Ready 回调应该被执行")
+	}
+
+	b := MustResolve[ServiceB](api.Resolver, "serviceB")
+	if b.Name != "noop" || b.ServiceA == nil || b.ServiceA.Name != "test" {
+		t.Errorf("通过接口解析的服务错误: %+v", b)
+	}
+
+	graph := api.Graph.GetDependencyGraph()
+	if !equalSlices(graph.Dependencies["serviceB"], []string{"serviceA"}) {
+		t.Errorf("依赖关系错误: %v", graph.Dependencies["serviceB"])
+	}
+	if present, ok := graph.Optional["serviceB"]["cache"]; !ok || present {
+		t.Errorf("通过 ResolveOr 解析的依赖应该按可选依赖记录: %v", graph.Optional["serviceB"])
+	}
+	if !strings.Contains(api.Graph.GenerateDOTGraph(), "serviceB") {
+		t.Error("DOT 图应该包含服务")
+	}
+}
+
+func TestDI_Register(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	if err := di.Register("bad", ServiceA{}, func(ctx *TestContext) any { return nil }); err == nil {
+		t.Error("非指针的占位符应该返回错误")
+	}
+	if err := di.Register("bad", (*ServiceA)(nil), nil); err == nil {
+		t.Error("builder 为 nil 时应该返回错误")
+	}
+
+	if err := di.Register("serviceA", (*ServiceA)(nil), func(ctx *TestContext) any {
+		return &ServiceB{Name: "wrong"}
+	}); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	var mismatch *ErrTypeMismatch
+	if err := di.Build(); !errors.As(err, &mismatch) {
+		t.Errorf("builder 返回的类型不符时应该返回类型错误，实际为: %v", err)
+	}
+}
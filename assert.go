@@ -0,0 +1,50 @@
+package weave
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AssertionError 构建后断言失败，按注册顺序汇总
+type AssertionError struct {
+	Errors []error
+}
+
+func (e *AssertionError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		parts = append(parts, err.Error())
+	}
+	return fmt.Sprintf("build assertions failed: %s", strings.Join(parts, "; "))
+}
+
+// Assert 注册构建后的断言，用于描述架构约束（如某接口只能有一个实现）
+// 断言在所有服务构建完成后、Ready 回调之前执行，任一断言失败则 Build 返回 *AssertionError
+func (s *Weave[T]) Assert(fn func(*Weave[T]) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assertions = append(s.assertions, fn)
+}
+
+// runAssertions 执行所有断言，调用方需持有锁
+// 执行期间释放锁，断言中可以调用容器的查询方法
+func (s *Weave[T]) runAssertions() error {
+	if len(s.assertions) == 0 {
+		return nil
+	}
+	assertions := s.assertions
+
+	s.mu.Unlock()
+	defer s.mu.Lock()
+
+	var errs []error
+	for _, fn := range assertions {
+		if err := fn(s); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &AssertionError{Errors: errs}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package weave
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type PaymentGateway interface {
+	Pay(amount int) error
+}
+
+type stripeGateway struct{}
+
+func (g *stripeGateway) Pay(amount int) error { return nil }
+
+type paypalGateway struct{}
+
+func (g *paypalGateway) Pay(amount int) error { return nil }
+
+// exactlyOneGateway 要求只有一个服务实现 PaymentGateway
+func exactlyOneGateway(di *Weave[TestContext]) error {
+	var impls []string
+	for name, obj := range di.Extract().ToMap() {
+		if _, ok := obj.(PaymentGateway); ok {
+			impls = append(impls, name)
+		}
+	}
+	if len(impls) != 1 {
+		return fmt.Errorf("expected exactly one PaymentGateway, got %d", len(impls))
+	}
+	return nil
+}
+
+func TestDI_Assert(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "stripe", func(ctx *TestContext) *stripeGateway {
+		return &stripeGateway{}
+	})
+	di.Assert(exactlyOneGateway)
+	di.Assert(func(di *Weave[TestContext]) error {
+		if info, ok := di.Info("stripe"); !ok || !info.Built {
+			return fmt.Errorf("service [stripe] must be built")
+		}
+		return nil
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("断言应该通过: %v", err)
+	}
+}
+
+func TestDI_AssertFailure(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "stripe", func(ctx *TestContext) *stripeGateway {
+		return &stripeGateway{}
+	})
+	Provide(di, "paypal", func(ctx *TestContext) *paypalGateway {
+		return &paypalGateway{}
+	})
+	ready := false
+	di.Ready(func() { ready = true })
+	di.Assert(exactlyOneGateway)
+	di.Assert(func(di *Weave[TestContext]) error {
+		return errors.New("second rule")
+	})
+
+	err := di.Build()
+	var assertErr *AssertionError
+	if !errors.As(err, &assertErr) {
+		t.Fatalf("断言失败时应该返回 *AssertionError，实际为 %v", err)
+	}
+	if len(assertErr.Errors) != 2 {
+		t.Errorf("应该汇总所有失败的断言: %v", assertErr)
+	}
+	if ready {
+		t.Error("断言失败时不应该执行 Ready 回调")
+	}
+	if err := di.Build(); err == nil {
+		t.Error("断言失败后容器不应该被标记为已构建")
+	}
+}
@@ -0,0 +1,121 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// autowireTag 字段注入标签，格式为 `weave:"name"` 或 `weave:"name,optional"`
+const autowireTag = "weave"
+
+// autowireField 需要注入的字段
+type autowireField struct {
+	index    int
+	field    string
+	service  string
+	optional bool
+}
+
+// autowireFields 解析结构体类型上的注入标签
+func autowireFields(t reflect.Type) []autowireField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []autowireField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup(autowireTag)
+		if !ok || tag == "" || tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		af := autowireField{index: i, field: f.Name, service: parts[0]}
+		for _, opt := range parts[1:] {
+			if opt == "optional" {
+				af.optional = true
+			}
+		}
+		fields = append(fields, af)
+	}
+	return fields
+}
+
+// validateAutowire 构建前校验所有服务的注入标签
+func (s *Weave[T]) validateAutowire() error {
+	// 自定义解析函数提供的服务在解析前无法校验
+	if s.resolver != nil {
+		return nil
+	}
+
+	names := s.entries.Keys()
+	sort.Strings(names)
+
+	for _, name := range names {
+		e, _ := s.entries.Get(name)
+		if e.iface != nil {
+			// 接口服务的具体类型在构建前未知
+			continue
+		}
+		st := reflect.TypeOf(e.instance).Elem()
+		for _, af := range autowireFields(st) {
+			f := st.Field(af.index)
+			if f.PkgPath != "" {
+				return fmt.Errorf("service [%s] field %s is unexported and cannot be autowired with [%s]", name, af.field, af.service)
+			}
+
+			// 委托给其他容器的服务在解析前无法获知类型
+			if s.isMounted(af.service) {
+				continue
+			}
+
+			target, ok := s.entries.Get(af.service)
+			if !ok {
+				if newName, renamed := s.renames.Get(af.service); renamed && !s.opts.strictRenames {
+					target, ok = s.entries.Get(newName)
+				}
+			}
+			if !ok {
+				if af.optional {
+					continue
+				}
+				return fmt.Errorf("service [%s] field %s requires service [%s] which is not found", name, af.field, af.service)
+			}
+
+			if tt := target.instanceType(); !tt.AssignableTo(f.Type) {
+				return fmt.Errorf("service [%s] field %s (%s) cannot be assigned service [%s] (%s)", name, af.field, f.Type, af.service, tt)
+			}
+		}
+	}
+	return nil
+}
+
+// autowire 为实例注入带标签的字段
+func (s *Weave[T]) autowire(name string, instance any) error {
+	v := reflect.ValueOf(instance)
+	if v.Kind() != reflect.Ptr {
+		// 接口服务可能返回非指针的值，没有可以注入的字段
+		return nil
+	}
+	v = v.Elem()
+	for _, af := range autowireFields(v.Type()) {
+		obj, err := s.getServiceFunc(af.service)
+		if err != nil {
+			if af.optional {
+				continue
+			}
+			return fmt.Errorf("service [%s] field %s: %w", name, af.field, err)
+		}
+		ov := reflect.ValueOf(obj)
+		if !ov.IsValid() || !ov.Type().AssignableTo(v.Field(af.index).Type()) {
+			return fmt.Errorf("service [%s] field %s (%s) cannot be assigned service [%s] (%T)", name, af.field, v.Field(af.index).Type(), af.service, obj)
+		}
+		v.Field(af.index).Set(ov)
+	}
+	return nil
+}
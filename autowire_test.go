@@ -0,0 +1,119 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+)
+
+type AutowiredService struct {
+	A     *ServiceA `weave:"serviceA"`
+	B     *ServiceB `weave:"serviceB,optional"`
+	Plain string
+}
+
+type MismatchedService struct {
+	A *ServiceB `weave:"serviceA"`
+}
+
+type UnexportedService struct {
+	a *ServiceA `weave:"serviceA"`
+}
+
+type MissingService struct {
+	A *ServiceA `weave:"missing"`
+}
+
+func TestDI_Autowire(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "autowired", func(ctx *TestContext) *AutowiredService {
+		return &AutowiredService{Plain: "plain"}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	svc := MustMake[TestContext, AutowiredService](di, "autowired")
+	if svc.A == nil || svc.A.Name != "ServiceA" {
+		t.Errorf("标签字段应该被注入serviceA，实际为: %v", svc.A)
+	}
+	if svc.B != nil {
+		t.Error("可选且不存在的服务应该保持nil")
+	}
+	if svc.Plain != "plain" {
+		t.Error("未标记的字段不应该被修改")
+	}
+
+	graph := di.GetDependencyGraph()
+	if !equalSlices(graph.Dependencies["autowired"], []string{"serviceA"}) {
+		t.Errorf("字段注入应该记录依赖关系，实际为: %v", graph.Dependencies["autowired"])
+	}
+}
+
+func TestDI_AutowireValidation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		provide  func(di *Weave[TestContext])
+		contains []string
+	}{
+		{
+			name: "类型不匹配",
+			provide: func(di *Weave[TestContext]) {
+				Provide(di, "bad", func(ctx *TestContext) *MismatchedService {
+					return &MismatchedService{}
+				})
+			},
+			contains: []string{"[bad]", "field A", "[serviceA]", "*weave.ServiceB", "*weave.ServiceA"},
+		},
+		{
+			name: "未导出字段",
+			provide: func(di *Weave[TestContext]) {
+				Provide(di, "bad", func(ctx *TestContext) *UnexportedService {
+					return &UnexportedService{}
+				})
+			},
+			contains: []string{"[bad]", "field a", "unexported"},
+		},
+		{
+			name: "服务不存在",
+			provide: func(di *Weave[TestContext]) {
+				Provide(di, "bad", func(ctx *TestContext) *MissingService {
+					return &MissingService{}
+				})
+			},
+			contains: []string{"[bad]", "field A", "[missing]", "not found"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			di := New[TestContext]()
+			di.SetCtx(&TestContext{Config: "test"})
+
+			built := false
+			Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+				built = true
+				return &ServiceA{Name: "ServiceA"}
+			})
+			tc.provide(di)
+
+			err := di.Build()
+			if err == nil {
+				t.Fatal("构建应该失败")
+			}
+			for _, s := range tc.contains {
+				if !strings.Contains(err.Error(), s) {
+					t.Errorf("错误信息应该包含 %q，实际为: %v", s, err)
+				}
+			}
+			if built {
+				t.Error("校验失败时不应该执行任何builder")
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package weave
+
+import (
+	"fmt"
+	"sort"
+)
+
+// bridge 跨容器桥接
+type bridge struct {
+	// source 服务在来源容器中的名称
+	source string
+	// resolve 从来源容器读取实例并校验类型
+	resolve func() (any, error)
+}
+
+// exporter 可记录服务被其他容器引用的容器，Weave 本身即满足该接口
+type exporter interface {
+	recordExport(name, consumer string)
+}
+
+// UseExternal 注册从其他容器读取实例的服务
+// 实例在当前容器 Build 时通过 source.GetService(sourceName) 读取并校验类型为 *R，
+// 两个容器共享同一实例指针；source 为 Weave 时两边的依赖图谱都会将其标记为跨容器服务
+func UseExternal[T any, R any](di *Weave[T], name string, source Resolver, sourceName string) {
+	e := &entry[*T]{
+		instance: (*R)(nil),
+		bridge: &bridge{
+			source: sourceName,
+			resolve: func() (any, error) {
+				obj, err := source.GetService(sourceName)
+				if err != nil {
+					return nil, fmt.Errorf("external service [%s]: %w", sourceName, err)
+				}
+				instance, ok := obj.(*R)
+				if !ok || instance == nil {
+					return nil, &ErrTypeMismatch{Name: name, Want: fmt.Sprintf("%T", instance), Got: fmt.Sprintf("%T", obj)}
+				}
+				return instance, nil
+			},
+		},
+	}
+
+	di.mu.Lock()
+	di.register(name, e)
+	di.built = false
+	di.resetBuilt()
+	di.mu.Unlock()
+
+	if ex, ok := source.(exporter); ok {
+		ex.recordExport(sourceName, name)
+	}
+}
+
+// recordExport 记录服务被其他容器引用
+func (s *Weave[T]) recordExport(name, consumer string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	consumers, _ := s.exports.Get(name)
+	for _, c := range consumers {
+		if c == consumer {
+			return
+		}
+	}
+	consumers = append(consumers, consumer)
+	sort.Strings(consumers)
+	s.exports.Set(name, consumers)
+}
+
+// buildBridge 从来源容器读取桥接服务的实例，直接保存实例指针而不复制
+func (s *Weave[T]) buildBridge(name string, e *entry[*T]) error {
+	if err := s.injectedFailure(name); err != nil {
+		return fmt.Errorf("service [%s] build failed: %w", name, err)
+	}
+	instance, err := e.bridge.resolve()
+	if err != nil {
+		return fmt.Errorf("service [%s] build failed: %w", name, err)
+	}
+	e.instance = instance
+	e.built = true
+	s.buildOrder = append(s.buildOrder, name)
+	s.setBuilt(name, true)
+	return nil
+}
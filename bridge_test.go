@@ -0,0 +1,88 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type adminContext struct {
+	Role string
+}
+
+func TestDI_UseExternal(t *testing.T) {
+	public := New[TestContext]()
+	public.SetCtx(&TestContext{Config: "public"})
+	Provide(public, "database", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "database"}
+	})
+
+	admin := New[adminContext]()
+	admin.SetCtx(&adminContext{Role: "admin"})
+	UseExternal[adminContext, ServiceA](admin, "db", public, "database")
+	Provide(admin, "audit", func(ctx *adminContext) *ServiceB {
+		return &ServiceB{Name: "audit", ServiceA: MustMake[adminContext, ServiceA](admin, "db")}
+	})
+
+	// 来源容器可以晚于引用方构建
+	if err := admin.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if err := public.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	database := MustMake[TestContext, ServiceA](public, "database")
+	if MustMake[adminContext, ServiceA](admin, "db") != database {
+		t.Error("两个容器应该共享同一实例指针")
+	}
+	if MustMake[adminContext, ServiceB](admin, "audit").ServiceA != database {
+		t.Error("依赖桥接服务的服务应该拿到来源容器的实例")
+	}
+	if database.Name != "database" {
+		t.Errorf("来源容器构建后实例应该可用: %s", database.Name)
+	}
+
+	adminGraph := admin.GetDependencyGraph()
+	if adminGraph.Bridged["db"] != "database" {
+		t.Errorf("引用方的图谱应该记录桥接来源: %v", adminGraph.Bridged)
+	}
+	if dot := admin.GenerateDOTGraph(); !strings.Contains(dot, `"db" [fillcolor=white, style="filled,dashed", label="🌐 db <- database"]`) {
+		t.Errorf("桥接服务应该按外部服务显示:\n%s", dot)
+	}
+
+	publicGraph := public.GetDependencyGraph()
+	if strings.Join(publicGraph.Exported["database"], ",") != "db" {
+		t.Errorf("来源容器的图谱应该记录引用方: %v", publicGraph.Exported)
+	}
+	dot := public.GenerateDOTGraph()
+	if !strings.Contains(dot, `"database" -> "external:db" [style=dashed];`) {
+		t.Errorf("来源容器应该显示跨容器引用:\n%s", dot)
+	}
+	if text := public.PrintDependencyGraph(); !strings.Contains(text, "📦 database -> 被外部引用为: db") {
+		t.Errorf("文本图谱应该显示跨容器引用:\n%s", text)
+	}
+}
+
+func TestDI_UseExternalErrors(t *testing.T) {
+	public := New[TestContext]()
+	Provide(public, "database", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "database"}
+	})
+
+	admin := New[adminContext]()
+	UseExternal[adminContext, ServiceB](admin, "db", public, "database")
+	err := admin.Build()
+	var mismatch *ErrTypeMismatch
+	if !errors.As(err, &mismatch) || mismatch.Name != "db" {
+		t.Errorf("类型不符时应该返回 ErrTypeMismatch，实际为 %v", err)
+	}
+
+	missing := New[adminContext]()
+	UseExternal[adminContext, ServiceA](missing, "db", public, "databse")
+	err = missing.Build()
+	var notFound *ErrServiceNotFound
+	if !errors.As(err, &notFound) || notFound.Name != "databse" {
+		t.Errorf("来源容器中不存在时应该返回 ErrServiceNotFound，实际为 %v", err)
+	}
+}
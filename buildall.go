@@ -0,0 +1,19 @@
+package weave
+
+import "fmt"
+
+// BuildAll 一次性完成创建容器、注册、构建、提取和压缩，返回服务注册表
+// 适用于脚本和示例等不需要保留容器的场景
+func BuildAll[T any](ctx *T, register func(di *Weave[T]), opts ...Option) (*Map[string, any], error) {
+	di := New[T](opts...)
+	di.SetCtx(ctx)
+	register(di)
+
+	if err := di.Build(); err != nil {
+		return nil, fmt.Errorf("weave: build failed: %w", err)
+	}
+
+	registry := di.Extract()
+	di.Compact()
+	return registry, nil
+}
@@ -0,0 +1,50 @@
+package weave
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ErrBuildLastDependency 未标记 BuildLast 的服务依赖了标记 BuildLast 的服务
+type ErrBuildLastDependency struct {
+	Name string
+	// Dependency 标记了 BuildLast 的依赖
+	Dependency string
+}
+
+func (e *ErrBuildLastDependency) Error() string {
+	return fmt.Sprintf("service [%s] depends on build-last service [%s]", e.Name, e.Dependency)
+}
+
+// BuildLast 标记服务在所有未标记的服务构建完成后再构建
+// 适合需要看到全部其他服务的服务（如遍历所有 handler 的路由），标记的服务之间仍按依赖关系构建；
+// 未标记的服务依赖标记的服务时构建失败并返回 *ErrBuildLastDependency
+func (s *Weave[T]) BuildLast(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries.Get(name)
+	if !ok {
+		return s.notFound(name)
+	}
+	e.last = true
+	return nil
+}
+
+// sortBuildWaves 按名称排序，有序分组的服务按分组顺序排在前面，BuildLast 标记的服务排在最后
+func (s *Weave[T]) sortBuildWaves(names []string) {
+	last := func(name string) bool {
+		e, ok := s.entries.Get(name)
+		return ok && e.last
+	}
+	wave := s.groupWave()
+	sort.Slice(names, func(i, j int) bool {
+		if li, lj := last(names[i]), last(names[j]); li != lj {
+			return lj
+		}
+		if wi, wj := wave(names[i]), wave(names[j]); wi != wj {
+			return wi < wj
+		}
+		return names[i] < names[j]
+	})
+}
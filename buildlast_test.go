@@ -0,0 +1,73 @@
+package weave
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDI_BuildLast(t *testing.T) {
+	di := New[TestContext](WithBuildTrace(100))
+	di.SetCtx(&TestContext{Config: "test"})
+	provideGraph(di, "router: middleware", "middleware", "users", "orders")
+	_ = di.BuildLast("router")
+	_ = di.BuildLast("middleware")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	order := di.BuildOrder()
+	if len(order) != 4 {
+		t.Fatalf("构建顺序错误: %v", order)
+	}
+	if got := order[2:]; !reflect.DeepEqual(got, []string{"middleware", "router"}) {
+		t.Errorf("BuildLast 标记的服务应该最后按依赖顺序构建，实际为 %v", order)
+	}
+
+	// 构建事件中标记的服务同样排在最后
+	var started []string
+	for _, e := range di.BuildTrace() {
+		if e.Kind == TraceBuildStart {
+			started = append(started, e.Service)
+		}
+	}
+	if len(started) != 4 || !containsString(started[2:], "router") || !containsString(started[2:], "middleware") {
+		t.Errorf("构建事件顺序错误: %v", started)
+	}
+}
+
+func TestDI_BuildLastIncremental(t *testing.T) {
+	di := New[TestContext](WithBuildTrace(100))
+	di.SetCtx(&TestContext{Config: "test"})
+	provideGraph(di, "router: middleware", "middleware", "users", "orders")
+	_ = di.BuildLast("router")
+	_ = di.BuildLast("middleware")
+	if done, err := di.BuildIncremental(time.Hour); !done || err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if got := di.BuildOrder(); !reflect.DeepEqual(got, []string{"orders", "users", "middleware", "router"}) {
+		t.Errorf("分批构建时 BuildLast 标记的服务应该排在最后，实际为 %v", got)
+	}
+}
+
+func TestDI_BuildLastConflict(t *testing.T) {
+	di := New[TestContext](WithBuildTrace(100))
+	di.SetCtx(&TestContext{Config: "test"})
+	provideGraph(di, "router: middleware", "middleware", "users", "orders")
+	_ = di.BuildLast("router")
+	_ = di.BuildLast("middleware")
+	Provide(di, "api", func(ctx *TestContext) *ServiceB {
+		MustMake[TestContext, ServiceA](di, "router")
+		return &ServiceB{Name: "api"}
+	})
+
+	err := di.Build()
+	var conflict *ErrBuildLastDependency
+	if !errors.As(err, &conflict) || conflict.Name != "api" || conflict.Dependency != "router" {
+		t.Fatalf("未标记的服务依赖标记的服务时应该返回 *ErrBuildLastDependency，实际为 %v", err)
+	}
+	if err.Error() != "service [api] build failed: service [api] depends on build-last service [router]" {
+		t.Errorf("错误信息错误: %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package weave
+
+import "fmt"
+
+// ErrBuildOnly 仅构建期服务在 Build 完成后已被释放
+type ErrBuildOnly struct {
+	Name string
+}
+
+func (e *ErrBuildOnly) Error() string {
+	return fmt.Sprintf("service [%s] is build-only and was released after Build", e.Name)
+}
+
+// ProvideBuildOnly 注册仅在构建期间使用的服务
+// Build 成功且 Ready 回调执行完毕后，容器释放其实例和 builder，Extract 不再包含该服务，
+// 之后解析该服务返回 *ErrBuildOnly；依赖图谱中仍保留该服务。
+// 依赖方不应在实例中保存其指针，否则实例无法被回收
+func ProvideBuildOnly[T any, R any](di *Weave[T], name string, builder func(*T) *R) {
+	di.assignEntry(name, &entry[*T]{
+		builder:   pointerBuilder(builder),
+		instance:  new(R),
+		buildOnly: true,
+	})
+}
+
+// releaseBuildOnly 释放已构建的仅构建期服务，只保留实例类型
+func (s *Weave[T]) releaseBuildOnly() {
+	s.entries.Range(func(name string, e *entry[*T]) bool {
+		if e.buildOnly && e.built && !e.released {
+			e.instance = e.unbuiltInstance()
+			e.builder = nil
+			e.released = true
+		}
+		return true
+	})
+}
@@ -0,0 +1,92 @@
+package weave
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDI_ProvideBuildOnly(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	reclaimed := make(chan struct{})
+	ProvideBuildOnly(di, "compiler", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "compiler"}
+	})
+	Provide(di, "repository", func(ctx *TestContext) *ServiceB {
+		compiler := MustMake[TestContext, ServiceA](di, "compiler")
+		runtime.SetFinalizer(compiler, func(*ServiceA) { close(reclaimed) })
+		// 只在构建期间使用，不保存指针
+		return &ServiceB{Name: "repository-" + compiler.Name}
+	})
+
+	ready := false
+	di.Ready(func() {
+		_, ready = TryMake[TestContext, ServiceA](di, "compiler")
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if !ready {
+		t.Error("Ready 回调中仍应可以获取仅构建期服务")
+	}
+	if MustMake[TestContext, ServiceB](di, "repository").Name != "repository-compiler" {
+		t.Error("依赖方应该在构建期间拿到仅构建期服务")
+	}
+
+	_, err := di.GetService("compiler")
+	var buildOnly *ErrBuildOnly
+	if !errors.As(err, &buildOnly) || buildOnly.Name != "compiler" {
+		t.Errorf("构建后获取仅构建期服务应该返回 ErrBuildOnly，实际为 %v", err)
+	}
+	if di.Extract().Contains("compiler") {
+		t.Error("Extract 不应该包含仅构建期服务")
+	}
+	if !di.GetDependencyGraph().BuildOnly["compiler"] {
+		t.Error("依赖图谱中应该保留仅构建期服务")
+	}
+	if dot := di.GenerateDOTGraph(); !strings.Contains(dot, `"compiler" [fillcolor=gray90, fontcolor=gray40, style="filled,dotted", label="🔧 compiler"]`) {
+		t.Errorf("DOT 图中仅构建期服务应该显示为灰色:\n%s", dot)
+	}
+
+	// 容器不再引用实例，实例应该可以被回收
+	deadline := time.After(time.Second)
+	for {
+		runtime.GC()
+		select {
+		case <-reclaimed:
+			return
+		case <-deadline:
+			t.Fatal("仅构建期服务的实例应该可以被回收")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestDI_BuildOnlyAfterRebuild(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	ProvideBuildOnly(di, "compiler", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "compiler"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	// 释放后注册的服务无法再依赖仅构建期服务
+	Provide(di, "late", func(ctx *TestContext) *ServiceB {
+		compiler, ok := TryMake[TestContext, ServiceA](di, "compiler")
+		if !ok {
+			return nil
+		}
+		return &ServiceB{Name: "late", ServiceA: compiler}
+	})
+	err := di.Build()
+	var buildOnly *ErrBuildOnly
+	if !errors.As(err, &buildOnly) {
+		t.Errorf("依赖已释放的仅构建期服务应该返回 ErrBuildOnly，实际为 %v", err)
+	}
+}
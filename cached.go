@@ -0,0 +1,36 @@
+package weave
+
+// ProvideCached 注册可复用已有实例的服务
+// 构建时先调用 cache，返回实例时直接使用该实例指针，不执行 builder，也不记录依赖；
+// cache 返回 false 或 nil 时视为未命中，按普通服务执行 builder。
+// 适合在容器重建时复用上一次构建的连接池等昂贵的单例
+func ProvideCached[T any, R any](di *Weave[T], name string, cache func() (*R, bool), builder func(*T) *R) {
+	di.assignEntry(name, &entry[*T]{
+		builder:  pointerBuilder(builder),
+		instance: new(R),
+		cached:   cacheLookup(cache),
+	})
+}
+
+// cacheLookup 将返回 *R 的 cache 转换为容器内部使用的查询函数，nil 实例视为未命中
+func cacheLookup[R any](cache func() (*R, bool)) func() (any, bool) {
+	return func() (any, bool) {
+		if instance, ok := cache(); ok && instance != nil {
+			return instance, true
+		}
+		return nil, false
+	}
+}
+
+// buildCached 尝试使用缓存的实例完成构建，缓存未命中时返回 false
+func (s *Weave[T]) buildCached(name string, e *entry[*T]) bool {
+	instance, ok := e.cached()
+	if !ok {
+		return false
+	}
+	e.instance = instance
+	e.built = true
+	s.buildOrder = append(s.buildOrder, name)
+	s.setBuilt(name, true)
+	return true
+}
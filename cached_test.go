@@ -0,0 +1,54 @@
+package weave
+
+import "testing"
+
+func TestDI_ProvideCached(t *testing.T) {
+	var previous *ServiceA
+	builds := 0
+	newWeave := func() *Weave[TestContext] {
+		di := New[TestContext]()
+		di.SetCtx(&TestContext{Config: "test"})
+		Provide(di, "config", func(ctx *TestContext) *ServiceC {
+			return &ServiceC{Name: "config"}
+		})
+		ProvideCached(di, "pool", func() (*ServiceA, bool) {
+			return previous, previous != nil
+		}, func(ctx *TestContext) *ServiceA {
+			builds++
+			MustMake[TestContext, ServiceC](di, "config")
+			return &ServiceA{Name: "pool"}
+		})
+		Provide(di, "repository", func(ctx *TestContext) *ServiceB {
+			return &ServiceB{Name: "repository", ServiceA: MustMake[TestContext, ServiceA](di, "pool")}
+		})
+		if err := di.Build(); err != nil {
+			t.Fatalf("构建失败: %v", err)
+		}
+		return di
+	}
+
+	// 缓存未命中，执行 builder
+	first := newWeave()
+	previous = MustMake[TestContext, ServiceA](first, "pool")
+	if builds != 1 {
+		t.Errorf("缓存未命中时应该执行 builder，实际执行 %d 次", builds)
+	}
+	if deps := first.DirectDependencies("pool"); len(deps) != 1 || deps[0] != "config" {
+		t.Errorf("执行 builder 时应该记录依赖: %v", deps)
+	}
+
+	// 重建容器时复用上一次的实例
+	second := newWeave()
+	if builds != 1 {
+		t.Errorf("缓存命中时不应该执行 builder，实际执行 %d 次", builds)
+	}
+	if MustMake[TestContext, ServiceA](second, "pool") != previous {
+		t.Error("缓存命中时应该直接使用缓存的实例指针")
+	}
+	if MustMake[TestContext, ServiceB](second, "repository").ServiceA != previous {
+		t.Error("依赖方应该拿到缓存的实例")
+	}
+	if info, _ := second.Info("pool"); !info.Built || len(info.DependsOn) != 0 {
+		t.Errorf("缓存命中时应该标记为已构建且不记录依赖: %+v", info)
+	}
+}
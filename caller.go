@@ -0,0 +1,61 @@
+package weave
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// callerFrame 返回本包之外（或测试文件中）第一个调用方的栈帧
+func callerFrame() (runtime.Frame, bool) {
+	_, self, _, _ := runtime.Caller(0)
+	dir := filepath.Dir(self)
+
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != dir || strings.HasSuffix(frame.File, "_test.go") {
+			return frame, true
+		}
+		if !more {
+			break
+		}
+	}
+	return runtime.Frame{}, false
+}
+
+// callerLocation 返回调用方位置（file:line）
+func callerLocation() string {
+	frame, ok := callerFrame()
+	if !ok {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+}
+
+// callerPackage 返回调用方所在的包路径
+func callerPackage() string {
+	frame, ok := callerFrame()
+	if !ok {
+		return ""
+	}
+	return packageOf(frame.Function)
+}
+
+// packageOf 从完整函数名中提取包路径
+// 例如 "github.com/a/b.(*T).M"、"github.com/a/b.F[...]"、"gopkg.in/yaml%2ev3.F"
+// vendor 目录下的包只保留 vendor 之后的路径
+func packageOf(function string) string {
+	if i := strings.LastIndex(function, "/vendor/"); i >= 0 {
+		function = function[i+len("/vendor/"):]
+	}
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		function = function[:slash+1+dot]
+	}
+	// 最后一段路径中的 "." 在函数名中被转义为 %2e
+	return strings.ReplaceAll(function, "%2e", ".")
+}
@@ -0,0 +1,56 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPackageOf(t *testing.T) {
+	testCases := []struct {
+		function string
+		expected string
+	}{
+		{"github.com/a/b.F", "github.com/a/b"},
+		{"github.com/a/b.(*T).M", "github.com/a/b"},
+		{"github.com/a/b.F[...]", "github.com/a/b"},
+		{"github.com/a/b.main.func1", "github.com/a/b"},
+		{"gopkg.in/yaml%2ev3.Unmarshal", "gopkg.in/yaml.v3"},
+		{"github.com/app/vendor/github.com/lib/x.New", "github.com/lib/x"},
+		{"main.main", "main"},
+	}
+
+	for _, tc := range testCases {
+		if got := packageOf(tc.function); got != tc.expected {
+			t.Errorf("packageOf(%q) = %q, 期望 %q", tc.function, got, tc.expected)
+		}
+	}
+}
+
+func TestDI_ProvidedBy(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	ProvideValue(di, "serviceB", &ServiceB{Name: "ServiceB"})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	for _, name := range []string{"serviceA", "serviceB"} {
+		info, _ := di.Info(name)
+		if info.ProvidedBy != "github.com/youjianglong/weave" {
+			t.Errorf("服务 %s 的注册包错误: %q", name, info.ProvidedBy)
+		}
+	}
+
+	dot := di.GenerateDOTGraph(DOTClusterByPackage())
+	if !strings.Contains(dot, "subgraph \"cluster_0\"") || !strings.Contains(dot, "label=\"github.com/youjianglong/weave\"") {
+		t.Errorf("DOT图应该按包分簇:\n%s", dot)
+	}
+	if strings.Contains(di.GenerateDOTGraph(), "subgraph") {
+		t.Error("默认不应该分簇")
+	}
+}
@@ -0,0 +1,73 @@
+package weave
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+)
+
+// buildFailure 注入的构建失败
+type buildFailure struct {
+	err         error
+	probability float64
+}
+
+// FailBuild 注入构建失败：Build 时该服务的 builder 不会执行，直接返回 err
+func (s *Weave[T]) FailBuild(name string, err error) {
+	s.FailBuildWithProbability(name, 1, err)
+}
+
+// FailBuildWithProbability 以概率 p 注入构建失败，随机数由 WithChaosSeed 设置的种子生成，结果可复现
+func (s *Weave[T]) FailBuildWithProbability(name string, p float64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		err = fmt.Errorf("injected failure")
+	}
+	s.failures.Set(name, buildFailure{err: err, probability: p})
+}
+
+// ClearFailBuild 清除指定服务的失败注入，不传参数时清除全部
+func (s *Weave[T]) ClearFailBuild(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(names) == 0 {
+		s.failures.Clear()
+		return
+	}
+	for _, name := range names {
+		s.failures.Delete(name)
+	}
+}
+
+// FailBuilds 返回当前注入了构建失败的服务名称
+func (s *Weave[T]) FailBuilds() []string {
+	names := s.failures.Keys()
+	sort.Strings(names)
+	return names
+}
+
+// injectedFailure 判断本次构建是否应当失败
+func (s *Weave[T]) injectedFailure(name string) error {
+	f, ok := s.failures.Get(name)
+	if !ok {
+		return nil
+	}
+	if f.probability < 1 {
+		// 每个服务使用独立的随机序列，结果不受构建顺序影响
+		r, ok := s.chaos.Get(name)
+		if !ok {
+			h := fnv.New64a()
+			h.Write([]byte(name))
+			r = rand.New(rand.NewSource(s.opts.chaosSeed ^ int64(h.Sum64())))
+			s.chaos.Set(name, r)
+		}
+		if r.Float64() >= f.probability {
+			return nil
+		}
+	}
+	return f.err
+}
@@ -0,0 +1,93 @@
+package weave
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDI_FailBuild(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	chaos := errors.New("chaos")
+	called := false
+	Provide(di, "redisCache", func(ctx *TestContext) *ServiceA {
+		called = true
+		return &ServiceA{Name: "Redis"}
+	})
+	Provide(di, "userService", func(ctx *TestContext) *ServiceB {
+		cache, _ := TryMake[TestContext, ServiceA](di, "redisCache")
+		return &ServiceB{Name: "UserService", ServiceA: cache}
+	})
+
+	di.FailBuild("redisCache", chaos)
+	if !equalSlices(di.FailBuilds(), []string{"redisCache"}) {
+		t.Errorf("失败注入列表错误: %v", di.FailBuilds())
+	}
+
+	err := di.Build()
+	if !errors.Is(err, chaos) {
+		t.Fatalf("构建应该返回注入的错误，实际为: %v", err)
+	}
+	if called {
+		t.Error("注入失败的服务不应该执行builder")
+	}
+
+	di.ClearFailBuild("redisCache")
+	if len(di.FailBuilds()) != 0 {
+		t.Errorf("清除后不应该有失败注入: %v", di.FailBuilds())
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("清除失败注入后构建应该成功: %v", err)
+	}
+	if !called {
+		t.Error("清除失败注入后应该执行builder")
+	}
+}
+
+func TestDI_FailBuildWithProbability(t *testing.T) {
+	run := func(seed int64, order []int) []string {
+		di := New[TestContext](WithChaosSeed(seed))
+		for i := 0; i < 20; i++ {
+			di.FailBuildWithProbability(fmt.Sprintf("service%02d", i), 0.5, errors.New("chaos"))
+		}
+
+		// 每个服务的结果只取决于种子和服务名称，与构建顺序无关
+		failed := make(map[string]bool)
+		for _, i := range order {
+			name := fmt.Sprintf("service%02d", i)
+			failed[name] = di.injectedFailure(name) != nil
+		}
+		names := []string{}
+		for i := 0; i < 20; i++ {
+			if name := fmt.Sprintf("service%02d", i); failed[name] {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+
+	forward := make([]int, 20)
+	backward := make([]int, 20)
+	for i := range forward {
+		forward[i] = i
+		backward[i] = 19 - i
+	}
+
+	first := run(42, forward)
+	if !equalSlices(first, run(42, backward)) {
+		t.Error("相同种子下失败注入的结果应该可以复现")
+	}
+	if len(first) == 0 || len(first) == 20 {
+		t.Errorf("概率为0.5时不应该全部成功或全部失败: %v", first)
+	}
+
+	di := New[TestContext]()
+	di.FailBuildWithProbability("a", 1, nil)
+	di.FailBuild("b", nil)
+	di.ClearFailBuild()
+	if len(di.FailBuilds()) != 0 {
+		t.Errorf("不传参数时应该清除全部失败注入: %v", di.FailBuilds())
+	}
+}
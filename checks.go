@@ -0,0 +1,158 @@
+package weave
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BuildCheck 构建检查，在 builder 执行前调用，返回的 post 在 builder 执行后调用
+// post 返回错误表示该服务的 builder 存在问题，如修改了全局状态或泄漏了 goroutine
+type BuildCheck func(name string) (post func() error)
+
+// BuildReport 最近一次构建的检查报告
+type BuildReport struct {
+	// CheckFailures 服务 -> 构建检查发现的问题
+	CheckFailures map[string][]error
+}
+
+func (r *BuildReport) String() string {
+	if len(r.CheckFailures) == 0 {
+		return "all build checks passed"
+	}
+	names := make([]string, 0, len(r.CheckFailures))
+	for name := range r.CheckFailures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		for _, err := range r.CheckFailures[name] {
+			fmt.Fprintf(&b, "service [%s]: %v\n", name, err)
+		}
+	}
+	return b.String()
+}
+
+// BuildReport 返回构建检查的报告，见 WithBuildChecks
+func (s *Weave[T]) BuildReport() *BuildReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &BuildReport{CheckFailures: s.checkFailures.ToMap()}
+}
+
+// startChecks 在 builder 执行前调用所有检查
+func (s *Weave[T]) startChecks(name string) []func() error {
+	if len(s.opts.buildChecks) == 0 {
+		return nil
+	}
+	posts := make([]func() error, 0, len(s.opts.buildChecks))
+	for _, check := range s.opts.buildChecks {
+		if post := check(name); post != nil {
+			posts = append(posts, post)
+		}
+	}
+	return posts
+}
+
+// finishChecks 在 builder 执行后按与检查相反的顺序调用 post 并记录问题
+func (s *Weave[T]) finishChecks(name string, posts []func() error) {
+	var failures []error
+	for i := len(posts) - 1; i >= 0; i-- {
+		if err := posts[i](); err != nil {
+			failures = append(failures, err)
+		}
+	}
+	if len(failures) > 0 {
+		s.checkFailures.Set(name, failures)
+	}
+}
+
+// BuildBudgetCheck 检查单个 builder 的执行时间是否超过 budget
+// 嵌套构建的依赖的时间计入依赖方
+func BuildBudgetCheck(budget time.Duration) BuildCheck {
+	return func(name string) func() error {
+		start := time.Now()
+		return func() error {
+			if elapsed := time.Since(start); elapsed > budget {
+				return fmt.Errorf("builder took %v, budget is %v", elapsed, budget)
+			}
+			return nil
+		}
+	}
+}
+
+// goroutineLeakGrace builder 返回后等待新 goroutine 退出的时间
+const goroutineLeakGrace = 100 * time.Millisecond
+
+// GoroutineLeakCheck 检查 builder 执行后是否留下了新的 goroutine
+// 调用栈中包含 allow 任一子串（如函数名）的 goroutine 不视为泄漏，可用于放行预期的后台任务；
+// builder 返回后最多等待 100ms 让短暂的 goroutine 退出。
+// 构建期间其他代码启动的 goroutine 同样会被计入，不适合与并发执行的测试一起使用
+func GoroutineLeakCheck(allow ...string) BuildCheck {
+	return func(name string) func() error {
+		before := goroutineStacks()
+		return func() error {
+			deadline := time.Now().Add(goroutineLeakGrace)
+			for {
+				leaked := []string{}
+				for id, stack := range goroutineStacks() {
+					if _, ok := before[id]; ok || containsAny(stack, allow) {
+						continue
+					}
+					leaked = append(leaked, id)
+				}
+				if len(leaked) == 0 {
+					return nil
+				}
+				if time.Now().After(deadline) {
+					sort.Strings(leaked)
+					return fmt.Errorf("builder leaked %d goroutines: %s", len(leaked), strings.Join(leaked, ", "))
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+		}
+	}
+}
+
+// goroutineStacks 返回当前所有 goroutine 的编号及调用栈
+func goroutineStacks() map[string]string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+
+	stacks := make(map[string]string)
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		// 每段以 "goroutine 18 [running]:" 开头
+		header := string(stack)
+		if !strings.HasPrefix(header, "goroutine ") {
+			continue
+		}
+		fields := strings.Fields(header)
+		if len(fields) < 2 {
+			continue
+		}
+		stacks["goroutine "+fields[1]] = header
+	}
+	return stacks
+}
+
+// containsAny 判断 s 是否包含任一子串
+func containsAny(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func allowedWorker(stop chan struct{}) { <-stop }
+
+func leakyWorker(stop chan struct{}) { <-stop }
+
+func TestDI_BuildChecks(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	var checked []string
+	record := func(name string) func() error {
+		checked = append(checked, name)
+		return nil
+	}
+	di := New[TestContext](WithBuildChecks(record, GoroutineLeakCheck("allowedWorker"), BuildBudgetCheck(20*time.Millisecond)))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "leaky", func(ctx *TestContext) *ServiceA {
+		go leakyWorker(stop)
+		return &ServiceA{Name: "leaky"}
+	})
+	Provide(di, "allowed", func(ctx *TestContext) *ServiceA {
+		go allowedWorker(stop)
+		return &ServiceA{Name: "allowed"}
+	})
+	Provide(di, "shortLived", func(ctx *TestContext) *ServiceA {
+		done := make(chan struct{})
+		go func() { <-done }()
+		close(done)
+		return &ServiceA{Name: "shortLived"}
+	})
+	Provide(di, "slow", func(ctx *TestContext) *ServiceA {
+		time.Sleep(50 * time.Millisecond)
+		return &ServiceA{Name: "slow"}
+	})
+	if done, err := di.BuildIncremental(time.Hour); !done || err != nil {
+		t.Fatalf("检查不应该使构建失败: %v", err)
+	}
+
+	if strings.Join(checked, ",") != "allowed,leaky,shortLived,slow" {
+		t.Errorf("每个服务都应该执行检查: %v", checked)
+	}
+
+	report := di.BuildReport()
+	if len(report.CheckFailures) != 2 {
+		t.Fatalf("应该报告 2 个服务的问题:\n%s", report)
+	}
+	if errs := report.CheckFailures["leaky"]; len(errs) != 1 || !strings.Contains(errs[0].Error(), "builder leaked 1 goroutines") {
+		t.Errorf("应该报告泄漏的 goroutine: %v", errs)
+	}
+	if errs := report.CheckFailures["slow"]; len(errs) != 1 || !strings.Contains(errs[0].Error(), "budget is 20ms") {
+		t.Errorf("应该报告超时的 builder: %v", errs)
+	}
+	if !strings.Contains(report.String(), "service [slow]: builder took") {
+		t.Errorf("报告格式错误:\n%s", report)
+	}
+}
@@ -0,0 +1,97 @@
+package weave
+
+import "fmt"
+
+// 已释放的数据
+const (
+	compactedBuilders uint8 = 1 << iota
+	compactedGraph
+	compactedCtx
+)
+
+// CompactError 使用已被压缩释放的数据时返回的错误
+type CompactError struct {
+	// Op 尝试执行的操作
+	Op string
+	// Released 被释放的数据
+	Released string
+}
+
+func (e *CompactError) Error() string {
+	return fmt.Sprintf("cannot %s: %s released by compaction", e.Op, e.Released)
+}
+
+// CompactBuilders 释放已构建服务的 builder，保留上下文、Ready 回调和依赖图谱
+// 之后仍可 Provide 新服务并再次 Build；已构建的服务无法再重新构建，
+// 未构建成功的服务（如被跳过的可选服务）再次构建时返回 *CompactError
+func (s *Weave[T]) CompactBuilders() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.built {
+		return fmt.Errorf("cannot compact weave before Build() is called")
+	}
+	s.compactBuilders()
+	return nil
+}
+
+// compactBuilders 释放 builder，调用方需持有写锁
+func (s *Weave[T]) compactBuilders() {
+	s.entries.Range(func(name string, entry *entry[*T]) bool {
+		entry.builder = nil
+		return true
+	})
+	s.compacted |= compactedBuilders
+}
+
+// CompactGraph 释放已记录的依赖关系
+// 之后 GetDependencyGraph、循环检测及各类图谱输出只包含压缩后新构建服务的依赖
+func (s *Weave[T]) CompactGraph() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.built {
+		return fmt.Errorf("cannot compact weave before Build() is called")
+	}
+	s.compactGraph()
+	return nil
+}
+
+// compactGraph 释放依赖关系，调用方需持有写锁
+func (s *Weave[T]) compactGraph() {
+	s.edges.reset()
+	s.compacted |= compactedGraph
+}
+
+// CompactOptions 压缩选项，见 CompactWith
+type CompactOptions struct {
+	// KeepGraph 保留已记录的依赖关系，压缩后 GetDependencyGraph、GenerateDOTGraph 等仍可展示装配关系，
+	// 适合释放 builder 后仍需在管理端点查看依赖图谱的常驻服务。
+	// 依赖关系边表的内存占用与服务数及边数成正比，通常远小于 builder 闭包及其捕获的数据
+	KeepGraph bool
+}
+
+// CompactAll 释放 builder、依赖关系、上下文和 Ready 回调
+// 之后只能获取已构建的服务，注册新服务后 Build 会返回 *CompactError
+func (s *Weave[T]) CompactAll() error {
+	return s.CompactWith(CompactOptions{})
+}
+
+// CompactWith 按 opts 压缩容器，默认与 CompactAll 相同，KeepGraph 时保留依赖关系
+// 所有数据在同一临界区内释放，其他 goroutine 不会观察到只压缩了一部分的容器
+func (s *Weave[T]) CompactWith(opts CompactOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.built {
+		return fmt.Errorf("cannot compact weave before Build() is called")
+	}
+	s.compactBuilders()
+	if !opts.KeepGraph {
+		s.compactGraph()
+	}
+	s.ctx = nil
+	s.ready = nil
+	s.compacted |= compactedCtx
+	return nil
+}
@@ -0,0 +1,146 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDI_CompactBuildersPluginHost(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "host"})
+
+	readyCalls := 0
+	di.Ready(func() { readyCalls++ })
+	Provide(di, "core", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "Core"}
+	})
+
+	if err := di.CompactBuilders(); err == nil {
+		t.Error("Build之前压缩应该返回错误")
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if err := di.CompactBuilders(); err != nil {
+		t.Fatalf("压缩builder失败: %v", err)
+	}
+
+	// 稍后加载插件：上下文和Ready回调仍然保留
+	Provide(di, "plugin", func(ctx *TestContext) *ServiceB {
+		if ctx == nil || ctx.Config != "host" {
+			t.Error("插件构建时应该能拿到上下文")
+		}
+		return &ServiceB{Name: "Plugin", ServiceA: MustMake[TestContext, ServiceA](di, "core")}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("压缩builder后构建新服务失败: %v", err)
+	}
+
+	plugin := MustMake[TestContext, ServiceB](di, "plugin")
+	if plugin.ServiceA != MustMake[TestContext, ServiceA](di, "core") {
+		t.Error("插件应该注入已构建的核心服务")
+	}
+	if readyCalls != 2 {
+		t.Errorf("Ready回调应该在每次构建后执行，实际执行 %d 次", readyCalls)
+	}
+	if deps := di.GetDependencyGraph().Dependencies["plugin"]; !equalSlices(deps, []string{"core"}) {
+		t.Errorf("压缩builder不应该影响依赖图谱，实际为: %v", deps)
+	}
+}
+
+func TestDI_CompactGraphAndAll(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if err := di.CompactGraph(); err != nil {
+		t.Fatalf("压缩依赖图谱失败: %v", err)
+	}
+	if deps := di.GetDependencyGraph().Dependencies["serviceB"]; len(deps) != 0 {
+		t.Errorf("压缩后不应该保留依赖关系，实际为: %v", deps)
+	}
+
+	if err := di.CompactAll(); err != nil {
+		t.Fatalf("全部压缩失败: %v", err)
+	}
+	if _, err := di.GetService("serviceB"); err != nil {
+		t.Errorf("压缩后仍应该可以获取已构建的服务: %v", err)
+	}
+
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+		return &ServiceC{Name: "ServiceC"}
+	})
+	var compactErr *CompactError
+	if err := di.Build(); !errors.As(err, &compactErr) || compactErr.Released != "context" {
+		t.Errorf("释放上下文后构建应该返回*CompactError，实际为: %v", err)
+	}
+}
+
+func TestDI_CompactBuildersRetry(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	ProvideOptional(di, "flaky", func(ctx *TestContext) *ServiceA {
+		return nil
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if err := di.CompactBuilders(); err != nil {
+		t.Fatalf("压缩builder失败: %v", err)
+	}
+
+	// 新服务触发重新构建，被跳过的服务已无法重试
+	Provide(di, "other", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "Other"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	var compactErr *CompactError
+	if err := di.SkippedServices()["flaky"]; !errors.As(err, &compactErr) || compactErr.Released != "builder" {
+		t.Errorf("builder被释放的服务应该返回*CompactError，实际为: %v", err)
+	}
+}
+
+func TestDI_CompactKeepGraph(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	di.Compact(CompactOptions{KeepGraph: true})
+	if deps := di.GetDependencyGraph().Dependencies["serviceB"]; !equalSlices(deps, []string{"serviceA"}) {
+		t.Errorf("KeepGraph 时应该保留依赖关系，实际为: %v", deps)
+	}
+	if !strings.Contains(di.GenerateDOTGraph(), "\"serviceA\" -> \"serviceB\"") {
+		t.Error("KeepGraph 时 DOT 图应该包含依赖关系边")
+	}
+	if _, err := di.GetService("serviceB"); err != nil {
+		t.Errorf("压缩后仍应该可以获取已构建的服务: %v", err)
+	}
+
+	// builder 与上下文仍然被释放
+	var compactErr *CompactError
+	if err := di.ReplaceBuilder("serviceA", func(ctx *TestContext) any { return &ServiceA{} }, false); !errors.As(err, &compactErr) {
+		t.Errorf("释放上下文后重新构建应该返回*CompactError，实际为: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package weave
+
+import "log"
+
+// Logger 日志输出接口，*log.Logger 即满足该接口
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// Contextual 可被容器统一使用的上下文
+type Contextual interface {
+	Logger() Logger
+}
+
+// NewConstrained 创建上下文满足 Contextual 的容器
+// 容器在构建过程中通过 ctx.Logger() 输出日志（弃用事件、被跳过的可选服务），未设置上下文时使用标准库 log
+// 通过 WithDeprecationHandler 指定的处理函数优先于上下文日志
+func NewConstrained[T Contextual](opts ...Option) *Weave[T] {
+	var s *Weave[T]
+	logDeprecated := WithDeprecationHandler(func(e DeprecationEvent) {
+		s.logf("weave: %s", e)
+	})
+	s = New[T](append([]Option{logDeprecated}, opts...)...)
+	s.logf = func(format string, args ...any) {
+		if s.ctx != nil {
+			if l := (*s.ctx).Logger(); l != nil {
+				l.Printf(format, args...)
+				return
+			}
+		}
+		log.Printf(format, args...)
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+package weave
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type recordLogger struct {
+	lines []string
+}
+
+func (l *recordLogger) Printf(format string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+type LoggingContext struct {
+	logger *recordLogger
+}
+
+func (c LoggingContext) Logger() Logger {
+	return c.logger
+}
+
+func TestDI_NewConstrained(t *testing.T) {
+	logger := &recordLogger{}
+	di := NewConstrained[LoggingContext]()
+	di.SetCtx(&LoggingContext{logger: logger})
+
+	Provide(di, "serviceA", func(ctx *LoggingContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	ProvideOptional(di, "metrics", func(ctx *LoggingContext) *ServiceB {
+		return nil
+	})
+	if err := di.Rename("serviceA", "coreService"); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+	Provide(di, "serviceC", func(ctx *LoggingContext) *ServiceC {
+		a := MustMake[LoggingContext, ServiceA](di, "serviceA")
+		return &ServiceC{Name: "ServiceC", ServiceA: a}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	logs := strings.Join(logger.lines, "\n")
+	if !strings.Contains(logs, "service [serviceA] is deprecated") {
+		t.Errorf("弃用事件应该通过上下文日志输出: %q", logs)
+	}
+	if !strings.Contains(logs, "optional service [metrics] skipped") {
+		t.Errorf("被跳过的可选服务应该通过上下文日志输出: %q", logs)
+	}
+}
+
+func TestDI_NewConstrainedCustomHandler(t *testing.T) {
+	logger := &recordLogger{}
+	var events []DeprecationEvent
+	di := NewConstrained[LoggingContext](WithDeprecationHandler(func(e DeprecationEvent) {
+		events = append(events, e)
+	}))
+	di.SetCtx(&LoggingContext{logger: logger})
+
+	Provide(di, "serviceA", func(ctx *LoggingContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	if err := di.Rename("serviceA", "coreService"); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+	if _, err := di.GetService("serviceA"); err != nil {
+		t.Fatalf("通过旧名称解析失败: %v", err)
+	}
+
+	if len(events) != 1 || len(logger.lines) != 0 {
+		t.Errorf("自定义处理函数应该优先于上下文日志: events=%v logs=%v", events, logger.lines)
+	}
+}
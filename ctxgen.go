@@ -0,0 +1,69 @@
+package weave
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// ErrMixedCtx 已构建的服务基于不同的上下文构建
+type ErrMixedCtx struct {
+	// Generations 服务名称 -> 构建时的上下文代数
+	Generations map[string]uint64
+}
+
+func (e *ErrMixedCtx) Error() string {
+	names := make([]string, 0, len(e.Generations))
+	for name := range e.Generations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("[%s] ctx#%d", name, e.Generations[name])
+	}
+	return "services built against different contexts: " + strings.Join(parts, ", ")
+}
+
+// CtxGeneration 返回当前的上下文代数
+// 每次 SetCtx 传入与当前不同的上下文时递增，服务构建时记录当时的代数，见 ServiceInfo.CtxGeneration
+func (s *Weave[T]) CtxGeneration() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ctxGen
+}
+
+// checkCtxGenerations 检查已构建的服务是否基于同一个上下文构建
+// 开启 WithStrictCtx 时返回 *ErrMixedCtx，否则输出警告
+func (s *Weave[T]) checkCtxGenerations() error {
+	generations := make(map[string]uint64)
+	mixed := false
+	var first uint64
+	s.entries.Range(func(name string, e *entry[*T]) bool {
+		if !e.built || e.ctxGen == 0 {
+			return true
+		}
+		if first == 0 {
+			first = e.ctxGen
+		} else if e.ctxGen != first {
+			mixed = true
+		}
+		generations[name] = e.ctxGen
+		return true
+	})
+	if !mixed {
+		return nil
+	}
+
+	err := &ErrMixedCtx{Generations: generations}
+	if s.opts.strictCtx {
+		return err
+	}
+	if s.logf != nil {
+		s.logf("weave: %v", err)
+	} else {
+		log.Printf("weave: %v", err)
+	}
+	return nil
+}
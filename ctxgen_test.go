@@ -0,0 +1,71 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// buildAcrossCtx 构建 a 后切换上下文再构建 b
+func buildAcrossCtx[T any](t *testing.T, di *Weave[T], first, second *T) error {
+	t.Helper()
+	di.SetCtx(first)
+	Provide(di, "a", func(ctx *T) *ServiceA { return &ServiceA{Name: "a"} })
+	Provide(di, "b", func(ctx *T) *ServiceA { return &ServiceA{Name: "b"} })
+	ProvideValue(di, "value", &ServiceB{Name: "value"})
+
+	if done, err := di.BuildIncremental(0); done || err != nil {
+		t.Fatalf("第一批应该只构建部分服务: done=%v err=%v", done, err)
+	}
+	di.SetCtx(second)
+	for {
+		done, err := di.BuildIncremental(0)
+		if done || err != nil {
+			return err
+		}
+	}
+}
+
+func TestDI_CtxGeneration(t *testing.T) {
+	logger := &recordLogger{}
+	di := NewConstrained[LoggingContext]()
+	if err := buildAcrossCtx(t, di, &LoggingContext{logger: logger}, &LoggingContext{logger: logger}); err != nil {
+		t.Fatalf("未开启 WithStrictCtx 时不应该报错: %v", err)
+	}
+
+	if di.CtxGeneration() != 2 {
+		t.Errorf("切换上下文后代数应该为 2，实际为 %d", di.CtxGeneration())
+	}
+	a, _ := di.Info("a")
+	b, _ := di.Info("b")
+	value, _ := di.Info("value")
+	if a.CtxGeneration != 1 || b.CtxGeneration != 2 || value.CtxGeneration != 0 {
+		t.Errorf("服务记录的上下文代数错误: a=%d b=%d value=%d", a.CtxGeneration, b.CtxGeneration, value.CtxGeneration)
+	}
+	if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], "services built against different contexts: [a] ctx#1, [b] ctx#2") {
+		t.Errorf("应该输出混用上下文的警告: %v", logger.lines)
+	}
+
+	// 设置同一个上下文不改变代数
+	ctx := di.ctx
+	di.SetCtx(ctx)
+	if di.CtxGeneration() != 2 {
+		t.Error("设置相同的上下文不应该递增代数")
+	}
+}
+
+func TestDI_StrictCtx(t *testing.T) {
+	di := New[TestContext](WithStrictCtx())
+	err := buildAcrossCtx(t, di, &TestContext{Config: "old"}, &TestContext{Config: "new"})
+
+	var mixed *ErrMixedCtx
+	if !errors.As(err, &mixed) {
+		t.Fatalf("应该返回 *ErrMixedCtx，实际为 %v", err)
+	}
+	if mixed.Generations["a"] != 1 || mixed.Generations["b"] != 2 {
+		t.Errorf("错误中的上下文代数错误: %v", mixed.Generations)
+	}
+	if _, ok := mixed.Generations["value"]; ok {
+		t.Error("ProvideValue 注册的服务不依赖上下文，不应该出现在错误中")
+	}
+}
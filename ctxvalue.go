@@ -0,0 +1,20 @@
+package weave
+
+// CtxPrefix 上下文值服务的名称前缀
+const CtxPrefix = "ctx."
+
+// ProvideFromCtx 注册从上下文派生的服务，与 Provide 相同，用于明确表达服务来源于上下文
+func ProvideFromCtx[T any, R any](di *Weave[T], name string, extract func(*T) *R) {
+	Provide(di, name, extract)
+}
+
+// CtxValue 将上下文中的某个值注册为名为 "ctx.<key>" 的服务
+// 依赖该值的服务通过 MustMake[T, V](di, "ctx.<key>") 获取，依赖关系会出现在图谱中
+func CtxValue[T any, V any](di *Weave[T], key string, extract func(*T) V) string {
+	name := CtxPrefix + key
+	Provide(di, name, func(ctx *T) *V {
+		v := extract(ctx)
+		return &v
+	})
+	return name
+}
@@ -0,0 +1,42 @@
+package weave
+
+import "testing"
+
+type ServerContext struct {
+	HTTPPort int
+	DSN      string
+}
+
+func TestDI_CtxValue(t *testing.T) {
+	di := New[ServerContext]()
+	di.SetCtx(&ServerContext{HTTPPort: 8080, DSN: "postgres://localhost"})
+
+	name := CtxValue(di, "httpPort", func(ctx *ServerContext) int { return ctx.HTTPPort })
+	if name != "ctx.httpPort" {
+		t.Errorf("期望服务名称为 'ctx.httpPort'，实际为 '%s'", name)
+	}
+	ProvideFromCtx(di, "dsn", func(ctx *ServerContext) *string { return &ctx.DSN })
+
+	Provide(di, "server", func(ctx *ServerContext) *ServiceA {
+		port := MustMake[ServerContext, int](di, "ctx.httpPort")
+		if *port != 8080 {
+			t.Errorf("期望端口为 8080，实际为 %d", *port)
+		}
+		return &ServiceA{Name: "server"}
+	})
+	Provide(di, "repo", func(ctx *ServerContext) *ServiceB {
+		dsn := MustMake[ServerContext, string](di, "dsn")
+		return &ServiceB{Name: *dsn}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if err := Assert(di).DependsOn("server", "ctx.httpPort").DependsOn("repo", "dsn").Roots("ctx.httpPort", "dsn").Err(); err != nil {
+		t.Error(err)
+	}
+	if repo := MustMake[ServerContext, ServiceB](di, "repo"); repo.Name != "postgres://localhost" {
+		t.Errorf("期望DSN为 'postgres://localhost'，实际为 '%s'", repo.Name)
+	}
+}
@@ -0,0 +1,93 @@
+package weave
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CycleError 严格模式下构建发现未被允许的循环依赖
+type CycleError struct {
+	Cycles []CycleReport
+}
+
+func (e *CycleError) Error() string {
+	parts := make([]string, 0, len(e.Cycles))
+	for _, c := range e.Cycles {
+		parts = append(parts, fmt.Sprintf("%s -> %s [%s]", strings.Join(c.Services, " -> "), c.Services[0], c.Fingerprint))
+	}
+	return fmt.Sprintf("circular dependencies are not allowed: %s", strings.Join(parts, "; "))
+}
+
+// CycleFingerprint 计算循环的稳定指纹
+// 指纹只与循环中的服务及其顺序有关，与从哪个服务开始无关
+func CycleFingerprint(services []string) string {
+	if len(services) == 0 {
+		return ""
+	}
+	minIdx := 0
+	for i, name := range services {
+		if name < services[minIdx] {
+			minIdx = i
+		}
+	}
+	rotated := make([]string, 0, len(services))
+	rotated = append(rotated, services[minIdx:]...)
+	rotated = append(rotated, services[:minIdx]...)
+
+	sum := sha256.Sum256([]byte(strings.Join(rotated, "->")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// AllowCycle 允许指纹对应的循环依赖，开启 WithStrictCycles 时构建不会因此失败
+func (s *Weave[T]) AllowCycle(fingerprint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.allowedCycles.Set(fingerprint, true)
+}
+
+// AllowedCycles 返回已允许的循环指纹，已排序
+func (s *Weave[T]) AllowedCycles() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fingerprints := s.allowedCycles.Keys()
+	sort.Strings(fingerprints)
+	return fingerprints
+}
+
+// StaleCycleAllowances 返回已允许但当前依赖图中已不存在的循环指纹，已排序
+// 应在构建后调用，用于清理过期的豁免
+func (s *Weave[T]) StaleCycleAllowances() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	present := make(map[string]bool)
+	for _, r := range s.cycleReports(s.dependencyGraph()) {
+		present[r.Fingerprint] = true
+	}
+	stale := []string{}
+	for _, fingerprint := range s.allowedCycles.Keys() {
+		if !present[fingerprint] {
+			stale = append(stale, fingerprint)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// checkCycles 严格模式下检查是否存在未被允许的循环依赖，调用方需持有锁
+func (s *Weave[T]) checkCycles() error {
+	var disallowed []CycleReport
+	for _, r := range s.cycleReports(s.dependencyGraph()) {
+		if !s.allowedCycles.Contains(r.Fingerprint) {
+			disallowed = append(disallowed, r)
+		}
+	}
+	if len(disallowed) > 0 {
+		return &CycleError{Cycles: disallowed}
+	}
+	return nil
+}
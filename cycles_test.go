@@ -0,0 +1,95 @@
+package weave
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// provideCycle 注册 from -> to 的依赖
+func provideCycle(di *Weave[TestContext], from, to string) {
+	Provide(di, from, func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, to)
+		return &ServiceA{Name: from}
+	})
+}
+
+func TestDI_StrictCycles(t *testing.T) {
+	di := New[TestContext](WithStrictCycles())
+	di.SetCtx(&TestContext{Config: "test"})
+
+	provideCycle(di, "a", "b")
+	provideCycle(di, "b", "a")
+	provideCycle(di, "x", "y")
+	provideCycle(di, "y", "x")
+
+	known := CycleFingerprint([]string{"a", "b"})
+	if known != CycleFingerprint([]string{"b", "a"}) {
+		t.Fatal("指纹应该与循环的起点无关")
+	}
+	di.AllowCycle(known)
+	di.AllowCycle(CycleFingerprint([]string{"old", "gone"}))
+
+	err := di.Build()
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("存在未被允许的循环时应该返回 *CycleError，实际为 %v", err)
+	}
+	if len(cycleErr.Cycles) != 1 || fmt.Sprint(cycleErr.Cycles[0].Services) != "[x y]" {
+		t.Errorf("只应该报告未被允许的循环: %v", cycleErr)
+	}
+
+	di.AllowCycle(cycleErr.Cycles[0].Fingerprint)
+	if err := di.Build(); err != nil {
+		t.Fatalf("循环全部被允许后构建应该成功: %v", err)
+	}
+
+	stale := di.StaleCycleAllowances()
+	if len(stale) != 1 || stale[0] != CycleFingerprint([]string{"gone", "old"}) {
+		t.Errorf("应该报告已不存在的循环指纹: %v", stale)
+	}
+	if allowed := di.AllowedCycles(); len(allowed) != 3 {
+		t.Errorf("应该返回所有允许的循环指纹: %v", allowed)
+	}
+}
+
+func TestDI_CyclesCtx(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	provideCycle(di, "a", "b")
+	provideCycle(di, "b", "a")
+	provideCycle(di, "x", "y")
+	provideCycle(di, "y", "x")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	cycles, err := di.CyclesCtx(context.Background())
+	if err != nil || len(cycles) != len(di.GetAllCircularDependencies()) {
+		t.Errorf("未取消时应该返回所有循环: %v %v", cycles, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cycles, err = di.CyclesCtx(ctx)
+	var truncated *ErrTruncated
+	if !errors.As(err, &truncated) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("取消后应该返回 *ErrTruncated，实际为 %v", err)
+	}
+	if len(cycles) != 0 {
+		t.Errorf("取消后应该只返回已找到的循环: %v", cycles)
+	}
+
+	out, err := di.PrintDependencyGraphCtx(ctx)
+	if !errors.As(err, &truncated) {
+		t.Errorf("取消后打印依赖图谱应该返回 *ErrTruncated，实际为 %v", err)
+	}
+	if !strings.Contains(out, "(已截断: result truncated: context canceled)") || !strings.Contains(out, "详细信息:") {
+		t.Errorf("截断时应该标记循环列表并输出其余内容:\n%s", out)
+	}
+	if out, err := di.PrintDependencyGraphCtx(context.Background(), PrintStable()); err != nil || out != di.PrintDependencyGraph(PrintStable()) {
+		t.Errorf("未取消时输出应该与 PrintDependencyGraph 相同: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package weave
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Definition 声明式的服务定义，通过 Apply 批量注册，适合由代码生成的装配表
+type Definition[T any] struct {
+	Name string
+	// Type 服务实例的指针类型，如 reflect.TypeOf((*Config)(nil))，与 Sample 二选一
+	Type reflect.Type
+	// Sample 服务实例类型的样例值，如 (*Config)(nil)，与 Type 二选一
+	Sample any
+	// Builder 返回的实例类型必须与声明的类型一致，否则构建失败并返回 *ErrTypeMismatch
+	Builder func(*T) any
+	// DependsOn 在 Builder 执行前依次解析的依赖，照常记录到依赖图谱中；
+	// 必须是已注册的服务或同一批定义中的服务
+	DependsOn []string
+	// Tags 分组标签，见 Tag
+	Tags []string
+	// Priority 分组内的优先级，见 SetPriority
+	Priority int
+	// Lazy 不预先分配占位符，见 WithLazyPlaceholders
+	Lazy bool
+	// Last 在其余服务之后构建，见 BuildLast
+	Last bool
+}
+
+// DefinitionProblem 某条服务定义的问题
+type DefinitionProblem struct {
+	// Index 定义在切片中的下标
+	Index int
+	Name  string
+	Err   error
+}
+
+func (p DefinitionProblem) Error() string {
+	return fmt.Sprintf("definition #%d [%s]: %v", p.Index, p.Name, p.Err)
+}
+
+// DefinitionError Apply 时发现的所有无效定义，按下标排列
+type DefinitionError struct {
+	Problems []DefinitionProblem
+}
+
+func (e *DefinitionError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = p.Error()
+	}
+	return fmt.Sprintf("invalid service definitions:\n%s", strings.Join(lines, "\n"))
+}
+
+// Apply 按顺序注册 defs 中的所有服务，与同名的已注册服务冲突时覆盖，同 Provide
+// 先校验全部定义：名称为空或在 defs 中重复、Builder 为空、类型未声明或不是指针、
+// DependsOn 引用了既未注册也不在 defs 中的服务；存在任何问题时返回 *DefinitionError 且不注册任何服务
+func (s *Weave[T]) Apply(defs []Definition[T]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	types := make([]reflect.Type, len(defs))
+	if err := s.validateDefinitions(defs, types); err != nil {
+		return err
+	}
+
+	for i, def := range defs {
+		typ := types[i]
+		placeholder := reflect.New(typ.Elem()).Interface()
+		if def.Lazy || s.opts.lazyPlaceholders {
+			placeholder = reflect.Zero(typ).Interface()
+		}
+		builder := typedBuilder(def.Name, typ, def.Builder)
+		if len(def.DependsOn) > 0 {
+			builder = dependingBuilder(s, def.DependsOn, builder)
+		}
+		e := &entry[*T]{
+			builder:  builder,
+			instance: placeholder,
+			last:     def.Last,
+		}
+		s.register(def.Name, e)
+		s.groupMu.Lock()
+		for _, tag := range def.Tags {
+			if !containsString(e.tags, tag) {
+				e.tags = append(e.tags, tag)
+			}
+		}
+		if def.Priority != 0 {
+			e.priority = def.Priority
+		}
+		s.groupMu.Unlock()
+	}
+	if len(defs) > 0 {
+		s.built = false
+		s.resetBuilt()
+	}
+	return nil
+}
+
+// validateDefinitions 校验所有定义并将声明的类型写入 types，调用方需持有锁
+func (s *Weave[T]) validateDefinitions(defs []Definition[T], types []reflect.Type) error {
+	first := make(map[string]int, len(defs))
+	for i, def := range defs {
+		if _, ok := first[def.Name]; !ok {
+			first[def.Name] = i
+		}
+	}
+
+	var problems []DefinitionProblem
+	report := func(i int, err error) {
+		problems = append(problems, DefinitionProblem{Index: i, Name: defs[i].Name, Err: err})
+	}
+	for i, def := range defs {
+		if def.Name == "" {
+			report(i, errors.New("name is empty"))
+		} else if j := first[def.Name]; j != i {
+			report(i, fmt.Errorf("duplicate of definition #%d", j))
+		}
+		if def.Builder == nil {
+			report(i, errors.New("builder is nil"))
+		}
+
+		switch {
+		case def.Type != nil && def.Sample != nil:
+			report(i, errors.New("both Type and Sample are set"))
+		case def.Type != nil:
+			types[i] = def.Type
+		case def.Sample != nil:
+			types[i] = reflect.TypeOf(def.Sample)
+		default:
+			report(i, errors.New("type is not declared, set Type or Sample"))
+		}
+		if types[i] != nil && types[i].Kind() != reflect.Ptr {
+			report(i, fmt.Errorf("type %s is not a pointer", types[i]))
+		}
+
+		for _, dep := range def.DependsOn {
+			if _, ok := first[dep]; ok {
+				continue
+			}
+			if s.entries.Contains(dep) || s.renames.Contains(dep) || s.isMounted(dep) {
+				continue
+			}
+			report(i, fmt.Errorf("depends on unknown service [%s]", dep))
+		}
+	}
+	if len(problems) > 0 {
+		return &DefinitionError{Problems: problems}
+	}
+	return nil
+}
+
+// dependingBuilder 在 builder 执行前依次解析 deps，任一依赖解析失败时以该错误 panic
+func dependingBuilder[T any](di *Weave[T], deps []string, builder func(*T) any) func(*T) any {
+	deps = append([]string(nil), deps...)
+	return func(ctx *T) any {
+		for _, dep := range deps {
+			if _, err := di.GetService(dep); err != nil {
+				panic(err)
+			}
+		}
+		return builder(ctx)
+	}
+}
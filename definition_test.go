@@ -0,0 +1,118 @@
+package weave
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDI_Apply(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "config", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: ctx.Config}
+	})
+
+	err := di.Apply([]Definition[TestContext]{
+		{
+			Name:      "server",
+			Sample:    (*ServiceB)(nil),
+			DependsOn: []string{"repo"},
+			Tags:      []string{"servers"},
+			Builder: func(ctx *TestContext) any {
+				return &ServiceB{Name: "server", ServiceA: MustMake[TestContext, ServiceA](di, "repo")}
+			},
+		},
+		{
+			Name:      "repo",
+			Type:      reflect.TypeOf((*ServiceA)(nil)),
+			DependsOn: []string{"config"},
+			Lazy:      true,
+			Builder: func(ctx *TestContext) any {
+				return &ServiceA{Name: "repo(" + MustMake[TestContext, ServiceA](di, "config").Name + ")"}
+			},
+		},
+		{
+			Name:     "router",
+			Sample:   (*ServiceC)(nil),
+			Tags:     []string{"servers"},
+			Priority: 10,
+			Last:     true,
+			Builder: func(ctx *TestContext) any {
+				return &ServiceC{Name: strings.Join(di.Group("servers", GroupByPriority), ",")}
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if server := MustMake[TestContext, ServiceB](di, "server"); server.ServiceA.Name != "repo(test)" {
+		t.Errorf("依赖解析错误: %s", server.ServiceA.Name)
+	}
+	if router := MustMake[TestContext, ServiceC](di, "router"); router.Name != "router,server" {
+		t.Errorf("标签与优先级错误: %s", router.Name)
+	}
+	if !equalSlices(di.DirectDependencies("repo"), []string{"config"}) || !equalSlices(di.DirectDependencies("server"), []string{"repo"}) {
+		t.Errorf("声明的依赖应该记录到图谱中: %v", di.GraphEdges())
+	}
+	order := di.BuildOrder()
+	if order[len(order)-1] != "router" {
+		t.Errorf("Last 的服务应该最后构建: %v", order)
+	}
+}
+
+func TestDI_ApplyValidation(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	build := func(ctx *TestContext) any { return &ServiceA{} }
+
+	err := di.Apply([]Definition[TestContext]{
+		{Name: "ok", Sample: (*ServiceA)(nil), Builder: build},
+		{Name: "ok", Sample: (*ServiceA)(nil), Builder: build},
+		{Name: "", Sample: (*ServiceA)(nil), Builder: build},
+		{Name: "noBuilder", Sample: (*ServiceA)(nil)},
+		{Name: "noType", Builder: build},
+		{Name: "value", Sample: ServiceA{}, Builder: build},
+		{Name: "both", Type: reflect.TypeOf((*ServiceA)(nil)), Sample: (*ServiceA)(nil), Builder: build},
+		{Name: "orphan", Sample: (*ServiceA)(nil), Builder: build, DependsOn: []string{"ok", "missing"}},
+	})
+	var defErr *DefinitionError
+	if !errors.As(err, &defErr) {
+		t.Fatalf("无效定义应该返回 *DefinitionError，实际为: %v", err)
+	}
+	got := make([]string, len(defErr.Problems))
+	for i, p := range defErr.Problems {
+		got[i] = p.Error()
+	}
+	want := []string{
+		"definition #1 [ok]: duplicate of definition #0",
+		"definition #2 []: name is empty",
+		"definition #3 [noBuilder]: builder is nil",
+		"definition #4 [noType]: type is not declared, set Type or Sample",
+		"definition #5 [value]: type weave.ServiceA is not a pointer",
+		"definition #6 [both]: both Type and Sample are set",
+		"definition #7 [orphan]: depends on unknown service [missing]",
+	}
+	if !equalSlices(got, want) {
+		t.Errorf("校验结果错误:\n%s\n期望:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if len(di.ServiceNames()) != 0 {
+		t.Errorf("存在无效定义时不应该注册任何服务: %v", di.ServiceNames())
+	}
+
+	// 类型不符在构建时报告
+	if err := di.Apply([]Definition[TestContext]{
+		{Name: "wrong", Sample: (*ServiceB)(nil), Builder: build},
+	}); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	var mismatch *ErrTypeMismatch
+	if err := di.Build(); !errors.As(err, &mismatch) {
+		t.Errorf("builder 返回的类型不符时应该返回类型错误，实际为: %v", err)
+	}
+}
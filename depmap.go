@@ -0,0 +1,30 @@
+package weave
+
+import "errors"
+
+// ProvideMap 注册服务，builder 通过 map 接收 deps 中声明的所有依赖
+// 适合依赖数量不固定的服务（如按名称分发的调度器）；依赖照常记录到依赖图谱中，
+// 任一依赖不存在时构建失败并返回 *ErrMissingDependencies，列出所有不存在的依赖
+func ProvideMap[T any, R any](di *Weave[T], name string, deps []string, builder func(ctx *T, deps map[string]any) *R) {
+	deps = append([]string(nil), deps...)
+	Provide(di, name, func(ctx *T) *R {
+		resolved := make(map[string]any, len(deps))
+		var missing []string
+		for _, dep := range deps {
+			instance, err := di.GetService(dep)
+			var notFound *ErrServiceNotFound
+			if errors.As(err, &notFound) && notFound.Name == dep {
+				missing = append(missing, dep)
+				continue
+			}
+			if err != nil {
+				panic(err)
+			}
+			resolved[dep] = instance
+		}
+		if len(missing) > 0 {
+			panic(&ErrMissingDependencies{Name: name, Missing: missing})
+		}
+		return builder(ctx, resolved)
+	})
+}
@@ -0,0 +1,65 @@
+package weave
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestDI_ProvideMap(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	for _, name := range []string{"email", "sms", "push"} {
+		ProvideValue(di, name, &ServiceA{Name: name})
+	}
+	ProvideMap(di, "dispatcher", []string{"email", "sms", "push"}, func(ctx *TestContext, deps map[string]any) *ServiceB {
+		names := []string{}
+		for name, dep := range deps {
+			if dep.(*ServiceA).Name != name {
+				t.Errorf("依赖 %s 对应的实例错误", name)
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return &ServiceB{Name: names[0] + "," + names[1] + "," + names[2]}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if got := MustMake[TestContext, ServiceB](di, "dispatcher").Name; got != "email,push,sms" {
+		t.Errorf("builder 应该收到所有依赖，实际为 %s", got)
+	}
+	if deps := di.DirectDependencies("dispatcher"); !reflect.DeepEqual(deps, []string{"email", "push", "sms"}) {
+		t.Errorf("依赖应该被记录，实际为 %v", deps)
+	}
+}
+
+func TestDI_ProvideMapMissing(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	ProvideValue(di, "email", &ServiceA{Name: "email"})
+	called := false
+	ProvideMap(di, "dispatcher", []string{"sms", "email", "push"}, func(ctx *TestContext, deps map[string]any) *ServiceB {
+		called = true
+		return &ServiceB{}
+	})
+
+	err := di.Build()
+	var missing *ErrMissingDependencies
+	if !errors.As(err, &missing) {
+		t.Fatalf("依赖不存在时应该返回 *ErrMissingDependencies，实际为 %v", err)
+	}
+	if missing.Name != "dispatcher" || !reflect.DeepEqual(missing.Missing, []string{"sms", "push"}) {
+		t.Errorf("应该列出所有不存在的依赖: %v", missing)
+	}
+	if err.Error() != "service [dispatcher] build failed: service [dispatcher] missing dependencies [sms], [push]" {
+		t.Errorf("错误信息错误: %v", err)
+	}
+	if called {
+		t.Error("依赖不存在时不应该调用 builder")
+	}
+}
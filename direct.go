@@ -0,0 +1,13 @@
+package weave
+
+// ProvideDirect 注册服务，构建后直接保存 builder 返回的指针，而不是将其值复制到容器分配的占位符中
+// 适合返回共享单例（如从注册表中取出的实例）等依赖指针身份的服务。
+// 服务在构建完成前被引用（循环依赖或构建前解析）时仍会分配占位符并复制构建结果；
+// 注意 Swap/SwapAll 会修改该指针指向的值
+func ProvideDirect[T any, R any](di *Weave[T], name string, builder func(*T) *R) {
+	di.assignEntry(name, &entry[*T]{
+		builder:  pointerBuilder(builder),
+		instance: (*R)(nil),
+		direct:   true,
+	})
+}
@@ -0,0 +1,50 @@
+package weave
+
+import "testing"
+
+func TestDI_ProvideDirect(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	shared := &ServiceA{Name: "shared"}
+	ProvideDirect(di, "direct", func(ctx *TestContext) *ServiceA {
+		return shared
+	})
+	Provide(di, "copied", func(ctx *TestContext) *ServiceA {
+		return shared
+	})
+	Provide(di, "consumer", func(ctx *TestContext) *ServiceB {
+		if MustMake[TestContext, ServiceA](di, "direct") != shared {
+			t.Error("依赖方应该拿到 builder 返回的指针")
+		}
+		return &ServiceB{Name: "consumer"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if MustMake[TestContext, ServiceA](di, "direct") != shared {
+		t.Error("ProvideDirect 注册的服务应该保存 builder 返回的指针")
+	}
+	if MustMake[TestContext, ServiceA](di, "copied") == shared {
+		t.Error("Provide 注册的服务应该保存复制后的占位符")
+	}
+}
+
+func TestDI_ProvideDirectEarlyReference(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	shared := &ServiceA{Name: "shared"}
+	ProvideDirect(di, "direct", func(ctx *TestContext) *ServiceA {
+		return shared
+	})
+	// 构建前拿到的占位符在构建后仍然有效
+	early := MustMake[TestContext, ServiceA](di, "direct")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if early.Name != "shared" || MustMake[TestContext, ServiceA](di, "direct") != early {
+		t.Error("构建前被引用的服务应该回退为复制到占位符")
+	}
+}
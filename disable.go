@@ -0,0 +1,47 @@
+package weave
+
+import "fmt"
+
+// ErrServiceDisabled 解析被禁用的服务时返回的错误
+type ErrServiceDisabled struct {
+	Name string
+}
+
+func (e *ErrServiceDisabled) Error() string {
+	return fmt.Sprintf("service [%s] is disabled", e.Name)
+}
+
+// Disable 禁用服务，保留其注册信息
+// 之后解析该服务返回 *ErrServiceDisabled，构建时跳过该服务，依赖它的服务构建失败
+func (s *Weave[T]) Disable(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.entries.Contains(name) {
+		return s.notFound(name)
+	}
+	s.disabled.Set(name, true)
+	return nil
+}
+
+// Enable 重新启用被禁用的服务，服务尚未构建时需要再次 Build
+func (s *Weave[T]) Enable(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries.Get(name)
+	if !ok {
+		return s.notFound(name)
+	}
+	s.disabled.Delete(name)
+	if !e.built {
+		s.built = false
+		s.resetBuilt()
+	}
+	return nil
+}
+
+// Disabled 判断服务是否被禁用
+func (s *Weave[T]) Disabled(name string) bool {
+	return s.disabled.Contains(name)
+}
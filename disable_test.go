@@ -0,0 +1,59 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDI_Disable(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	calls := 0
+	Provide(di, "metrics", func(ctx *TestContext) *ServiceA {
+		calls++
+		return &ServiceA{Name: "metrics"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		metrics, ok := TryMake[TestContext, ServiceA](di, "metrics")
+		if !ok {
+			return nil
+		}
+		return &ServiceB{Name: "ServiceB", ServiceA: metrics}
+	})
+
+	if err := di.Disable("metrics"); err != nil {
+		t.Fatalf("禁用失败: %v", err)
+	}
+	if !di.Disabled("metrics") {
+		t.Error("服务应该处于禁用状态")
+	}
+
+	err := di.Build()
+	if err == nil || !strings.Contains(err.Error(), "service [serviceB] build failed: service [metrics] is disabled") {
+		t.Fatalf("依赖被禁用服务的服务应该构建失败: %v", err)
+	}
+	if calls != 0 {
+		t.Error("被禁用的服务不应该被构建")
+	}
+
+	var disabled *ErrServiceDisabled
+	if _, err := di.GetService("metrics"); !errors.As(err, &disabled) || disabled.Name != "metrics" {
+		t.Errorf("解析被禁用的服务应该返回 *ErrServiceDisabled: %v", err)
+	}
+
+	if err := di.Enable("metrics"); err != nil {
+		t.Fatalf("启用失败: %v", err)
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("重新启用后构建失败: %v", err)
+	}
+	if b := MustMake[TestContext, ServiceB](di, "serviceB"); b.ServiceA == nil || b.ServiceA.Name != "metrics" {
+		t.Error("重新启用后依赖应该可用")
+	}
+
+	if err := di.Disable("missing"); err == nil {
+		t.Error("禁用不存在的服务应该报错")
+	}
+}
@@ -0,0 +1,93 @@
+package weave
+
+import "sort"
+
+// DOTOption DOT 图生成选项
+type DOTOption func(*dotOptions)
+
+type dotOptions struct {
+	// 按注册服务的包分簇
+	clusterByPackage bool
+	// 稳定输出模式
+	stable bool
+	// 同一拓扑层级的节点排在同一行
+	rankByLevel bool
+	// 显示 OrderGroups 的顺序约束
+	groupOrder bool
+	// 节点提示中显示实例标识
+	instanceIDs bool
+}
+
+// DOTClusterByPackage 按注册服务的包将节点分组为子图
+func DOTClusterByPackage() DOTOption {
+	return func(o *dotOptions) {
+		o.clusterByPackage = true
+	}
+}
+
+// DOTRankByLevel 按 Levels 将同一拓扑层级的节点放在同一行（rank=same），大型图谱按层排列更易阅读
+// 与 DOTClusterByPackage 同时使用时，Graphviz 可能无法同时满足分簇与分层
+func DOTRankByLevel() DOTOption {
+	return func(o *dotOptions) {
+		o.rankByLevel = true
+	}
+}
+
+// DOTGroupOrder 以蓝色点线显示 OrderGroups 指定的顺序约束，每个服务连向后一个有序分组的所有服务
+func DOTGroupOrder() DOTOption {
+	return func(o *dotOptions) {
+		o.groupOrder = true
+	}
+}
+
+// DOTInstanceIDs 在节点的 tooltip 中显示 InstanceID，tooltip 相同的节点是同一个对象
+// 实例地址每次运行都不同，不要与 DOTStable 一起用于 golden 文件比较
+func DOTInstanceIDs() DOTOption {
+	return func(o *dotOptions) {
+		o.instanceIDs = true
+	}
+}
+
+// DOTStable 稳定输出模式，适合与 golden 文件比较
+// 各部分按固定顺序输出：节点、拓扑层级、外部服务、依赖关系边、分组顺序、未注册的可选依赖、跨容器引用、图例；
+// 拓扑层级与分组顺序只在开启 DOTRankByLevel、DOTGroupOrder 时输出，已注册的可选依赖边与普通边一起输出；
+// 边按字典序排序，循环取自 CycleReports，图例始终输出
+func DOTStable() DOTOption {
+	return func(o *dotOptions) {
+		o.stable = true
+	}
+}
+
+// PrintOption 文本依赖图谱生成选项
+type PrintOption func(*printOptions)
+
+type printOptions struct {
+	// 稳定输出模式
+	stable bool
+}
+
+// PrintStable 稳定输出模式，适合与 golden 文件比较
+// 各部分按固定顺序始终输出（循环、根服务、叶服务、孤立服务、外部服务、仅构建期服务、跨容器服务、可选依赖、中间服务、详细信息），
+// 没有内容的部分输出 (无)，所有列表按字典序排序，循环取自 CycleReports
+func PrintStable() PrintOption {
+	return func(o *printOptions) {
+		o.stable = true
+	}
+}
+
+// stableCycles 返回确定顺序的循环路径，每个循环从名称最小的服务开始并回到该服务
+func (s *Weave[T]) stableCycles(graph *DependencyGraph) [][]string {
+	reports := s.cycleReports(graph)
+	cycles := make([][]string, len(reports))
+	for i, r := range reports {
+		cycles[i] = append(append([]string{}, r.Services...), r.Services[0])
+	}
+	return cycles
+}
+
+// sortedCopy 返回排好序的副本
+func sortedCopy(names []string) []string {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,290 @@
+package weave
+
+import (
+	"sort"
+	"sync"
+)
+
+// edge 依赖关系边：from 依赖 to
+type edge struct {
+	from, to int32
+	phase    Phase
+}
+
+// edgeTable 依赖关系边表
+// 服务名称驻留在名称表中，边只保存名称下标，大型容器下显著减少内存占用
+// 构建阶段的边只保存在按名称下标索引的邻接表中，按记录顺序保存对端下标，单个服务的查询无需扫描边表；
+// 其他阶段的边数量很少，保存在 edges 中
+type edgeTable struct {
+	names   []string
+	index   map[string]int32
+	out, in [][]int32
+	edges   []edge
+
+	// 构建阶段的边或节点每次变化时递增，用于判断缓存的分析结果是否过期
+	version uint64
+
+	// 并发执行的 Ready 回调会同时记录边，读取方也需持有读锁
+	mu sync.RWMutex
+}
+
+func newEdgeTable() *edgeTable {
+	return &edgeTable{index: make(map[string]int32)}
+}
+
+// intern 驻留名称并返回其下标
+func (t *edgeTable) intern(name string) int32 {
+	if i, ok := t.index[name]; ok {
+		return i
+	}
+	i := int32(len(t.names))
+	t.names = append(t.names, name)
+	t.index[name] = i
+	t.out = append(t.out, nil)
+	t.in = append(t.in, nil)
+	return i
+}
+
+// add 记录构建阶段 from 依赖 to
+func (t *edgeTable) add(from, to string) {
+	t.addPhase(from, to, PhaseBuilding)
+}
+
+// addPhase 记录指定阶段 from 依赖 to
+func (t *edgeTable) addPhase(from, to string, phase Phase) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	i, j := t.intern(from), t.intern(to)
+	if phase != PhaseBuilding {
+		t.edges = append(t.edges, edge{from: i, to: j, phase: phase})
+		return
+	}
+	t.out[i] = append(t.out[i], j)
+	t.in[j] = append(t.in[j], i)
+	t.version++
+}
+
+// touch 标记图谱已变化
+func (t *edgeTable) touch() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.version++
+}
+
+// currentVersion 返回当前的图谱版本
+func (t *edgeTable) currentVersion() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.version
+}
+
+// dependsOn 按记录顺序返回服务在构建阶段的依赖
+func (t *edgeTable) dependsOn(name string) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	i, ok := t.index[name]
+	if !ok {
+		return []string{}
+	}
+	return t.namesOf(t.out[i])
+}
+
+// dependents 按记录顺序返回在构建阶段依赖该服务的服务
+func (t *edgeTable) dependents(name string) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	i, ok := t.index[name]
+	if !ok {
+		return []string{}
+	}
+	return t.namesOf(t.in[i])
+}
+
+// namesOf 将下标列表转换为名称列表
+func (t *edgeTable) namesOf(ids []int32) []string {
+	names := make([]string, len(ids))
+	for k, id := range ids {
+		names[k] = t.names[id]
+	}
+	return names
+}
+
+// removeFrom 删除服务自身记录的所有依赖（服务被重新注册时使用）
+func (t *edgeTable) removeFrom(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	i, ok := t.index[name]
+	if !ok {
+		return
+	}
+	kept := t.edges[:0]
+	for _, e := range t.edges {
+		if e.from != i {
+			kept = append(kept, e)
+		}
+	}
+	t.edges = kept
+	for _, j := range t.out[i] {
+		t.in[j] = removeID(t.in[j], i)
+	}
+	t.out[i] = nil
+	t.version++
+}
+
+// removeID 原地删除列表中所有的 id
+func removeID(ids []int32, id int32) []int32 {
+	kept := ids[:0]
+	for _, v := range ids {
+		if v != id {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// rename 将名称表中的旧名称替换为新名称，所有相关的边随之更新
+func (t *edgeTable) rename(oldName, newName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	i, ok := t.index[oldName]
+	if !ok {
+		return
+	}
+	delete(t.index, oldName)
+	t.version++
+
+	j, exists := t.index[newName]
+	if !exists {
+		t.names[i] = newName
+		t.index[newName] = i
+		return
+	}
+
+	// 新名称已存在于名称表中，合并下标
+	for k := range t.edges {
+		if t.edges[k].from == i {
+			t.edges[k].from = j
+		}
+		if t.edges[k].to == i {
+			t.edges[k].to = j
+		}
+	}
+	for _, k := range t.out[i] {
+		replaceID(t.in[k], i, j)
+	}
+	for _, k := range t.in[i] {
+		replaceID(t.out[k], i, j)
+	}
+	t.out[j] = append(t.out[j], t.out[i]...)
+	t.in[j] = append(t.in[j], t.in[i]...)
+	replaceID(t.out[j], i, j)
+	replaceID(t.in[j], i, j)
+	t.out[i], t.in[i] = nil, nil
+}
+
+// replaceID 将列表中所有的 from 替换为 to
+func replaceID(ids []int32, from, to int32) {
+	for k, v := range ids {
+		if v == from {
+			ids[k] = to
+		}
+	}
+}
+
+// reset 清空边表
+func (t *edgeTable) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.names = nil
+	t.index = make(map[string]int32)
+	t.edges = nil
+	t.out, t.in = nil, nil
+	t.version++
+}
+
+// materialize 生成构建阶段的依赖与被依赖映射，nodes 中的服务即使没有边也会出现在依赖映射中
+// 所有列表共享两块底层数组，每个列表都已排序
+func (t *edgeTable) materialize(nodes []string) (dependencies, dependents map[string][]string) {
+	return t.materializePhases(nodes, PhaseBuilding)
+}
+
+// materializePhases 生成指定阶段的依赖与被依赖映射
+// 其他阶段的边的发起方（如 Ready 回调）也会出现在依赖映射中
+func (t *edgeTable) materializePhases(nodes []string, phases ...Phase) (dependencies, dependents map[string][]string) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var mask uint8
+	for _, p := range phases {
+		mask |= 1 << p
+	}
+	// each 依次访问所选阶段的边，构建阶段的边直接从邻接表展开
+	each := func(fn func(from, to int32)) {
+		if mask&(1<<PhaseBuilding) != 0 {
+			for from, ids := range t.out {
+				for _, to := range ids {
+					fn(int32(from), to)
+				}
+			}
+		}
+		for _, e := range t.edges {
+			if mask&(1<<e.phase) != 0 {
+				fn(e.from, e.to)
+			}
+		}
+	}
+	for _, e := range t.edges {
+		if mask&(1<<e.phase) != 0 {
+			nodes = append(nodes, t.names[e.from])
+		}
+	}
+
+	outDegree := make([]int32, len(t.names))
+	inDegree := make([]int32, len(t.names))
+	total := 0
+	each(func(from, to int32) {
+		outDegree[from]++
+		inDegree[to]++
+		total++
+	})
+
+	// 计算每个下标在底层数组中的起始位置
+	outStart := make([]int32, len(t.names)+1)
+	inStart := make([]int32, len(t.names)+1)
+	for i := range t.names {
+		outStart[i+1] = outStart[i] + outDegree[i]
+		inStart[i+1] = inStart[i] + inDegree[i]
+	}
+
+	depBacking := make([]string, total)
+	dependentBacking := make([]string, total)
+	outFill := make([]int32, len(t.names))
+	inFill := make([]int32, len(t.names))
+	each(func(from, to int32) {
+		depBacking[outStart[from]+outFill[from]] = t.names[to]
+		outFill[from]++
+		dependentBacking[inStart[to]+inFill[to]] = t.names[from]
+		inFill[to]++
+	})
+
+	dependencies = make(map[string][]string, len(nodes))
+	dependents = make(map[string][]string, len(nodes))
+	empty := []string{}
+	for _, name := range nodes {
+		dependencies[name] = empty[:0:0]
+		dependents[name] = empty[:0:0]
+	}
+
+	for i, name := range t.names {
+		if _, ok := dependencies[name]; ok {
+			deps := depBacking[outStart[i]:outStart[i+1]:outStart[i+1]]
+			sort.Strings(deps)
+			dependencies[name] = deps
+		}
+		if inDegree[i] > 0 {
+			ds := dependentBacking[inStart[i]:inStart[i+1]:inStart[i+1]]
+			sort.Strings(ds)
+			dependents[name] = ds
+		}
+	}
+	return dependencies, dependents
+}
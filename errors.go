@@ -0,0 +1,122 @@
+package weave
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrServiceNotFound 服务不存在
+type ErrServiceNotFound struct {
+	Name string
+	// Suggestions 编辑距离最小的已注册服务名称，最多3个
+	Suggestions []string
+}
+
+func (e *ErrServiceNotFound) Error() string {
+	if len(e.Suggestions) > 0 {
+		quoted := make([]string, len(e.Suggestions))
+		for i, name := range e.Suggestions {
+			quoted[i] = "[" + name + "]"
+		}
+		return fmt.Sprintf("service [%s] not found, did you mean %s?", e.Name, strings.Join(quoted, ", "))
+	}
+	return fmt.Sprintf("service [%s] not found", e.Name)
+}
+
+// ErrTypeMismatch 服务实例的类型与期望的类型不符
+type ErrTypeMismatch struct {
+	Name string
+	// Want 期望的类型
+	Want string
+	// Got 实际的类型
+	Got string
+}
+
+func (e *ErrTypeMismatch) Error() string {
+	return fmt.Sprintf("service [%s] is %s, not %s", e.Name, e.Got, e.Want)
+}
+
+// ErrNotBuilt 服务尚未构建完成
+type ErrNotBuilt struct {
+	Name string
+}
+
+func (e *ErrNotBuilt) Error() string {
+	return fmt.Sprintf("service [%s] is not built yet", e.Name)
+}
+
+// ErrNoServices 构建时容器中没有注册任何服务，通常是注册代码被遗漏，见 WithAllowEmpty
+type ErrNoServices struct{}
+
+func (e *ErrNoServices) Error() string {
+	return "no services provided, use WithAllowEmpty() to build an empty weave"
+}
+
+// ErrMissingDependencies 通过 ProvideMap 声明的依赖不存在
+type ErrMissingDependencies struct {
+	Name string
+	// Missing 不存在的依赖，按声明顺序
+	Missing []string
+}
+
+func (e *ErrMissingDependencies) Error() string {
+	quoted := make([]string, len(e.Missing))
+	for i, name := range e.Missing {
+		quoted[i] = "[" + name + "]"
+	}
+	return fmt.Sprintf("service [%s] missing dependencies %s", e.Name, strings.Join(quoted, ", "))
+}
+
+// ErrTruncated 操作因 ctx 取消或超时而提前结束，结果不完整
+type ErrTruncated struct {
+	Cause error
+}
+
+func (e *ErrTruncated) Error() string {
+	return fmt.Sprintf("result truncated: %v", e.Cause)
+}
+
+func (e *ErrTruncated) Unwrap() error {
+	return e.Cause
+}
+
+// isWeaveError 判断错误链中是否包含 weave 自身的错误类型
+func isWeaveError(err error) bool {
+	var (
+		notFound   *ErrServiceNotFound
+		mismatch   *ErrTypeMismatch
+		notBuilt   *ErrNotBuilt
+		inProgress *ErrResolutionInProgress
+		disabled   *ErrServiceDisabled
+		buildOnly  *ErrBuildOnly
+		missing    *ErrMissingDependencies
+		last       *ErrBuildLastDependency
+		foreign    *ErrForeignResolve
+	)
+	return errors.As(err, &notFound) || errors.As(err, &mismatch) || errors.As(err, &notBuilt) ||
+		errors.As(err, &inProgress) || errors.As(err, &disabled) || errors.As(err, &buildOnly) ||
+		errors.As(err, &missing) || errors.As(err, &last) || errors.As(err, &foreign)
+}
+
+// callBuilder 执行 builder，将 weave 自身错误引发的 panic（如 builder 内 MustMake 失败）转换为错误
+// 其他 panic 在开启 WithPanicRecovery 时转换为错误，否则继续向上抛出
+func (s *Weave[T]) callBuilder(name string, entry *entry[*T]) (instance any, err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if e, ok := r.(error); ok && isWeaveError(e) {
+			err = fmt.Errorf("service [%s] build failed: %w", name, e)
+			return
+		}
+		if s.opts.recoverPanics {
+			err = fmt.Errorf("service [%s] builder panicked: %v", name, r)
+			return
+		}
+		entry.built = false
+		panic(r)
+	}()
+	return entry.builder(s.ctx), nil
+}
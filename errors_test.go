@@ -0,0 +1,109 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDI_BuilderWeavePanic(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "missing")
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+
+	err := di.Build()
+	var notFound *ErrServiceNotFound
+	if !errors.As(err, &notFound) || notFound.Name != "missing" {
+		t.Fatalf("builder 中 MustMake 失败应该转换为错误: %v", err)
+	}
+	// 依赖链体现在错误信息中
+	if !strings.Contains(err.Error(), "service [serviceA] build failed: service [missing] not found") {
+		t.Errorf("错误应该标明出错的服务: %v", err)
+	}
+	if info, _ := di.Info("serviceA"); info.Built {
+		t.Error("失败的服务不应该被标记为已构建")
+	}
+}
+
+func TestDI_BuilderTypeMismatchPanic(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		MustMake[TestContext, ServiceC](di, "serviceA")
+		return &ServiceB{Name: "ServiceB"}
+	})
+
+	err := di.Build()
+	var mismatch *ErrTypeMismatch
+	if !errors.As(err, &mismatch) || mismatch.Got != "*weave.ServiceA" || mismatch.Want != "*weave.ServiceC" {
+		t.Errorf("类型不符应该转换为 *ErrTypeMismatch: %v", err)
+	}
+}
+
+func TestDI_BuilderForeignPanic(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		panic("user bug")
+	})
+
+	func() {
+		defer func() {
+			if r := recover(); r != "user bug" {
+				t.Errorf("其他 panic 应该原样抛出，实际为 %v", r)
+			}
+		}()
+		di.Build()
+	}()
+
+	// 锁应该已经释放，且 builder 状态已恢复
+	if info, _ := di.Info("serviceA"); info.Built {
+		t.Error("panic 的服务不应该被标记为已构建")
+	}
+
+	recovering := New[TestContext](WithPanicRecovery())
+	recovering.SetCtx(&TestContext{Config: "test"})
+	Provide(recovering, "serviceA", func(ctx *TestContext) *ServiceA {
+		panic("user bug")
+	})
+	if err := recovering.Build(); err == nil || !strings.Contains(err.Error(), "service [serviceA] builder panicked: user bug") {
+		t.Errorf("开启 WithPanicRecovery 时应该转换为错误: %v", err)
+	}
+}
+
+func TestDI_BuildEmpty(t *testing.T) {
+	di := New[TestContext]()
+	err := di.Build()
+	var noServices *ErrNoServices
+	if !errors.As(err, &noServices) {
+		t.Errorf("空容器构建应该返回 ErrNoServices，实际为 %v", err)
+	}
+	if _, err := di.BuildIncremental(time.Hour); !errors.As(err, &noServices) {
+		t.Errorf("空容器分批构建应该返回 ErrNoServices，实际为 %v", err)
+	}
+
+	empty := New[TestContext](WithAllowEmpty())
+	if err := empty.Build(); err != nil {
+		t.Fatalf("WithAllowEmpty 时空容器应该可以构建: %v", err)
+	}
+	if registry := empty.Extract(); registry.Len() != 0 {
+		t.Errorf("空容器应该提取到空的注册表: %v", registry.Keys())
+	}
+	if err := empty.CompactAll(); err != nil {
+		t.Errorf("空容器应该可以压缩: %v", err)
+	}
+	empty.Compact()
+}
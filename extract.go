@@ -0,0 +1,61 @@
+package weave
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ExtractClosure 提取指定服务及其传递依赖的实例
+// 服务不存在或依赖闭包中包含未构建的服务时返回错误
+func (s *Weave[T]) ExtractClosure(names ...string) (*Map[string, any], error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, name := range names {
+		if !s.entries.Contains(name) {
+			return nil, s.notFound(name)
+		}
+	}
+
+	closure := s.dependencyClosure(names)
+	registry := NewMap[string, any]()
+	for _, name := range closure {
+		e, ok := s.entries.Get(name)
+		if !ok {
+			if e, ok = s.externals.Get(name); !ok {
+				return nil, fmt.Errorf("service [%s] in closure is not found", name)
+			}
+		}
+		if !e.built {
+			return nil, fmt.Errorf("service [%s] in closure is not built", name)
+		}
+		if e.released {
+			// 仅构建期服务已被释放
+			continue
+		}
+		registry.Set(name, e.instance)
+	}
+	return registry, nil
+}
+
+// dependencyClosure 返回服务及其传递依赖的名称，已排序
+func (s *Weave[T]) dependencyClosure(names []string) []string {
+	visited := make(map[string]bool)
+	queue := append([]string{}, names...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if visited[name] {
+			continue
+		}
+		visited[name] = true
+		queue = append(queue, s.edges.dependsOn(name)...)
+	}
+
+	closure := make([]string, 0, len(visited))
+	for name := range visited {
+		closure = append(closure, name)
+	}
+	sort.Strings(closure)
+	return closure
+}
@@ -0,0 +1,146 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDI_ExtractClosure(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+		return &ServiceC{Name: "ServiceC", ServiceB: MustMake[TestContext, ServiceB](di, "serviceB")}
+	})
+	Provide(di, "unrelated", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "unrelated"}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	registry, err := di.ExtractClosure("serviceC")
+	if err != nil {
+		t.Fatalf("提取失败: %v", err)
+	}
+	keys := registry.Keys()
+	if len(keys) != 3 || registry.Contains("unrelated") {
+		t.Errorf("闭包应该只包含 serviceC 及其传递依赖: %v", keys)
+	}
+	if c := MustGetFromRegistry[ServiceC](registry, "serviceC"); c != MustMake[TestContext, ServiceC](di, "serviceC") {
+		t.Error("提取的实例应该与容器中的实例相同")
+	}
+
+	if _, err := di.ExtractClosure("serviceX"); err == nil || !strings.Contains(err.Error(), "serviceX") {
+		t.Errorf("未知服务应该报错: %v", err)
+	}
+}
+
+func TestDI_ExtractClosureUnbuilt(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	if _, err := di.ExtractClosure("serviceA"); err == nil || !strings.Contains(err.Error(), "not built") {
+		t.Errorf("未构建的服务应该报错: %v", err)
+	}
+}
+
+func newExtractedRegistry() *Map[string, any] {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	di.MustBuild()
+	return di.Extract()
+}
+
+func TestDI_Getter(t *testing.T) {
+	registry := newExtractedRegistry()
+	registry.Freeze()
+
+	get := Getter[ServiceA](registry, "serviceA")
+	if a := get(); a == nil || a != MustGetFromRegistry[ServiceA](registry, "serviceA") {
+		t.Error("Getter 应该返回注册表中的实例")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("服务不存在时 Getter 应该立即 panic")
+		}
+	}()
+	Getter[ServiceA](registry, "missing")
+}
+
+func BenchmarkRegistry_MustGet(b *testing.B) {
+	registry := newExtractedRegistry()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MustGetFromRegistry[ServiceA](registry, "serviceA")
+	}
+}
+
+func BenchmarkRegistry_MustGetFrozen(b *testing.B) {
+	registry := newExtractedRegistry()
+	registry.Freeze()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MustGetFromRegistry[ServiceA](registry, "serviceA")
+	}
+}
+
+func BenchmarkRegistry_Getter(b *testing.B) {
+	registry := newExtractedRegistry()
+	registry.Freeze()
+	get := Getter[ServiceA](registry, "serviceA")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		get()
+	}
+}
+
+func TestDI_ExtractNamespaced(t *testing.T) {
+	newModule := func(config string) *Weave[TestContext] {
+		di := New[TestContext]()
+		di.SetCtx(&TestContext{Config: config})
+		Provide(di, "db", func(ctx *TestContext) *ServiceA {
+			return &ServiceA{Name: ctx.Config + "-db"}
+		})
+		di.MustBuild()
+		return di
+	}
+	billing, users := newModule("billing"), newModule("users")
+
+	registry := billing.ExtractNamespaced("billing.")
+	if overwritten := registry.Merge(users.ExtractNamespaced("users.")); len(overwritten) != 0 {
+		t.Errorf("不同前缀的注册表合并时不应该覆盖: %v", overwritten)
+	}
+	if registry.Len() != 2 ||
+		MustGetFromRegistry[ServiceA](registry, "billing.db").Name != "billing-db" ||
+		MustGetFromRegistry[ServiceA](registry, "users.db").Name != "users-db" {
+		t.Errorf("合并后的注册表错误: %v", registry.Keys())
+	}
+
+	// 与 Mount 的名称一致
+	app := New[TestContext]()
+	if err := app.Mount("billing.", billing); err != nil {
+		t.Fatalf("挂载失败: %v", err)
+	}
+	if obj, err := app.GetService("billing.db"); err != nil || obj != MustGetFromRegistry[ServiceA](registry, "billing.db") {
+		t.Errorf("挂载解析的名称应该与命名空间注册表的键一致: %v", err)
+	}
+
+	if overwritten := registry.Merge(billing.ExtractNamespaced("billing.")); len(overwritten) != 1 || overwritten[0] != "billing.db" {
+		t.Errorf("Merge 应该返回被覆盖的键: %v", overwritten)
+	}
+}
@@ -0,0 +1,127 @@
+package weave
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// containerSeq 容器编号的分配计数
+var containerSeq uint64
+
+// builderFrame 开启 WithForeignResolveCheck 的容器正在执行的 builder
+type builderFrame struct {
+	container uint64
+	service   string
+	// 通过所属容器解析依赖的嵌套层数，期间对其他容器的访问属于 Mount/UseExternal 委托
+	delegating int
+}
+
+// builderStack 同一个 goroutine 上正在执行的 builder，最内层在末尾
+// 只由所属 goroutine 访问，无需加锁
+type builderStack struct {
+	frames []*builderFrame
+}
+
+// activeBuilders goroutine 编号 -> 该 goroutine 上开启检测的容器正在执行的 builder
+// count 为所有 goroutine 上的帧数，为 0 时 GetService 不做任何检查
+var activeBuilders struct {
+	stacks sync.Map
+	count  int32
+}
+
+// goroutineID 从栈信息中解析当前 goroutine 的编号，只在开启检测的容器构建期间调用
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	// 格式为 "goroutine 123 [running]:..."
+	fields := bytes.Fields(buf[:n])
+	if len(fields) < 2 {
+		return 0
+	}
+	id, _ := strconv.ParseUint(string(fields[1]), 10, 64)
+	return id
+}
+
+// ErrForeignResolve builder 通过另一个容器解析了服务，通常是复制装配代码时闭包捕获了错误的容器
+type ErrForeignResolve struct {
+	// Service 正在构建的服务
+	Service string
+	// Builder 执行 builder 的容器编号
+	Builder uint64
+	// Name 被解析的服务
+	Name string
+	// Resolver 实际处理解析的容器编号
+	Resolver uint64
+}
+
+func (e *ErrForeignResolve) Error() string {
+	return fmt.Sprintf("service [%s] in weave #%d resolved [%s] through weave #%d", e.Service, e.Builder, e.Name, e.Resolver)
+}
+
+// WithForeignResolveCheck 检测 builder 通过其他容器解析服务，此时该解析返回 *ErrForeignResolve 并输出日志
+// 通过 Mount 与 UseExternal 的委托解析不受影响；执行栈按 goroutine 记录，其他 goroutine 上的解析不受影响，
+// builder 另起 goroutine 发起的解析也不会被检测。没有开启检测的容器在构建时，GetService 只多一次原子读取
+func WithForeignResolveCheck() Option {
+	return func(o *options) {
+		o.foreignCheck = true
+	}
+}
+
+// ID 返回容器的编号，在进程内唯一，用于区分日志和错误中的容器
+func (s *Weave[T]) ID() uint64 {
+	return s.id
+}
+
+// enterBuilder 开启检测时在当前 goroutine 的执行栈上记录 builder 开始执行，返回的函数在执行结束时调用
+func (s *Weave[T]) enterBuilder(name string) (*builderFrame, func()) {
+	if !s.opts.foreignCheck {
+		return nil, func() {}
+	}
+	gid := goroutineID()
+	v, _ := activeBuilders.stacks.LoadOrStore(gid, &builderStack{})
+	stack := v.(*builderStack)
+	frame := &builderFrame{container: s.id, service: name}
+	stack.frames = append(stack.frames, frame)
+	atomic.AddInt32(&activeBuilders.count, 1)
+	return frame, func() {
+		stack.frames = stack.frames[:len(stack.frames)-1]
+		if len(stack.frames) == 0 {
+			activeBuilders.stacks.Delete(gid)
+		}
+		atomic.AddInt32(&activeBuilders.count, -1)
+	}
+}
+
+// checkForeignResolve 判断本次解析是否由当前 goroutine 上其他容器的 builder 直接发起
+func (s *Weave[T]) checkForeignResolve(name string) error {
+	if atomic.LoadInt32(&activeBuilders.count) == 0 {
+		return nil
+	}
+	v, ok := activeBuilders.stacks.Load(goroutineID())
+	if !ok {
+		return nil
+	}
+	frames := v.(*builderStack).frames
+	if len(frames) == 0 {
+		return nil
+	}
+	top := frames[len(frames)-1]
+	if top.container == s.id || top.delegating > 0 {
+		return nil
+	}
+	err := &ErrForeignResolve{Service: top.service, Builder: top.container, Name: name, Resolver: s.id}
+	log.Printf("weave: %v", err)
+	return err
+}
+
+// delegate 标记 builder 正在通过所属容器解析依赖
+func (f *builderFrame) delegate(delta int) {
+	if f != nil {
+		f.delegating += delta
+	}
+}
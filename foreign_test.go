@@ -0,0 +1,119 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDI_WithForeignResolveCheck(t *testing.T) {
+	di1 := New[TestContext]()
+	di1.SetCtx(&TestContext{Config: "test"})
+	Provide(di1, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "A1"}
+	})
+	if err := di1.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	di2 := New[TestContext](WithForeignResolveCheck())
+	di2.SetCtx(&TestContext{Config: "test"})
+	Provide(di2, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "A2"}
+	})
+	// 复制装配代码时误用了 di1
+	Provide(di2, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "B", ServiceA: MustMake[TestContext, ServiceA](di1, "serviceA")}
+	})
+
+	err := di2.Build()
+	var foreign *ErrForeignResolve
+	if !errors.As(err, &foreign) {
+		t.Fatalf("应该检测到跨容器解析，实际为: %v", err)
+	}
+	if foreign.Service != "serviceB" || foreign.Name != "serviceA" || foreign.Builder != di2.ID() || foreign.Resolver != di1.ID() {
+		t.Errorf("错误信息不正确: %+v", foreign)
+	}
+	if !strings.Contains(err.Error(), "serviceB") {
+		t.Errorf("错误中应该包含出错的服务: %v", err)
+	}
+	if di1.ID() == di2.ID() {
+		t.Error("容器编号应该唯一")
+	}
+
+	// 构建结束后直接访问其他容器不受影响
+	if _, err := di1.GetService("serviceA"); err != nil {
+		t.Errorf("构建之外的解析不应该被拦截: %v", err)
+	}
+}
+
+func TestDI_WithForeignResolveCheckAllowsDelegation(t *testing.T) {
+	shared := New[TestContext]()
+	shared.SetCtx(&TestContext{Config: "test"})
+	Provide(shared, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "shared"}
+	})
+	if err := shared.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	di := New[TestContext](WithForeignResolveCheck())
+	di.SetCtx(&TestContext{Config: "test"})
+	if err := di.Mount("shared.", shared); err != nil {
+		t.Fatalf("挂载失败: %v", err)
+	}
+	UseExternal[TestContext, ServiceA](di, "bridged", shared, "serviceA")
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		MustMake[TestContext, ServiceA](di, "bridged")
+		return &ServiceB{Name: "B", ServiceA: MustMake[TestContext, ServiceA](di, "shared.serviceA")}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("通过 Mount 与 UseExternal 委托不应该被视为跨容器解析: %v", err)
+	}
+
+	// 未开启检测时保持原有行为
+	plain := New[TestContext]()
+	plain.SetCtx(&TestContext{Config: "test"})
+	Provide(plain, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "B", ServiceA: MustMake[TestContext, ServiceA](shared, "serviceA")}
+	})
+	if err := plain.Build(); err != nil {
+		t.Fatalf("未开启检测时不应该报错: %v", err)
+	}
+}
+
+func TestDI_WithForeignResolveCheckOtherGoroutine(t *testing.T) {
+	other := New[TestContext]()
+	other.SetCtx(&TestContext{Config: "test"})
+	Provide(other, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "other"}
+	})
+	if err := other.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	di := New[TestContext](WithForeignResolveCheck())
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		close(entered)
+		<-release
+		return &ServiceA{Name: "A"}
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- di.Build()
+	}()
+
+	// di 的 builder 正在执行时，其他 goroutine 访问无关的容器不应该被拦截
+	<-entered
+	_, err := other.GetService("serviceA")
+	close(release)
+	if err != nil {
+		t.Errorf("其他 goroutine 上的解析不应该被视为跨容器解析: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+}
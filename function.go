@@ -0,0 +1,38 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ProvideFunc 注册函数类型的服务（handler、工厂函数等）
+// 函数值保存在 *F 占位符中，因此依赖图谱、Extract 与普通服务行为一致，Extract 中的值为 *F
+func ProvideFunc[T any, F any](di *Weave[T], name string, builder func(*T) F) {
+	if kind := reflect.TypeOf((*F)(nil)).Elem().Kind(); kind != reflect.Func {
+		panic(fmt.Sprintf("service [%s] type %s is not a func", name, kind))
+	}
+	di.assign(name, new(F), func(ctx *T) any {
+		fn := builder(ctx)
+		if reflect.ValueOf(&fn).Elem().IsNil() {
+			return nil
+		}
+		return &fn
+	})
+}
+
+// MakeFunc 解析函数类型的服务
+func MakeFunc[T any, F any](di *Weave[T], name string) (F, error) {
+	var zero F
+	obj, err := di.GetService(name)
+	if err != nil {
+		return zero, err
+	}
+	fn, ok := obj.(*F)
+	if !ok {
+		return zero, &ErrTypeMismatch{Name: name, Want: fmt.Sprintf("%T", fn), Got: fmt.Sprintf("%T", obj)}
+	}
+	if reflect.ValueOf(fn).Elem().IsNil() {
+		return zero, &ErrNotBuilt{Name: name}
+	}
+	return *fn, nil
+}
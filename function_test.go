@@ -0,0 +1,87 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+)
+
+type checkoutHandler func(item string) (string, error)
+
+type checkoutService struct {
+	prefix string
+}
+
+func (c *checkoutService) Handle(item string) (string, error) {
+	return c.prefix + item, nil
+}
+
+func TestDI_ProvideFunc(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	// 普通函数
+	ProvideFunc(di, "upper", func(ctx *TestContext) func(string) string {
+		return strings.ToUpper
+	})
+	// 方法值
+	Provide(di, "checkoutService", func(ctx *TestContext) *checkoutService {
+		return &checkoutService{prefix: "checkout:"}
+	})
+	ProvideFunc(di, "handleCheckout", func(ctx *TestContext) checkoutHandler {
+		return MustMake[TestContext, checkoutService](di, "checkoutService").Handle
+	})
+	// 闭包
+	ProvideFunc(di, "greet", func(ctx *TestContext) func(string) string {
+		upper, err := MakeFunc[TestContext, func(string) string](di, "upper")
+		if err != nil {
+			panic(err)
+		}
+		return func(name string) string {
+			return ctx.Config + ":" + upper(name)
+		}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	handle, err := MakeFunc[TestContext, checkoutHandler](di, "handleCheckout")
+	if err != nil {
+		t.Fatalf("解析函数服务失败: %v", err)
+	}
+	if out, _ := handle("book"); out != "checkout:book" {
+		t.Errorf("方法值服务调用结果错误: %s", out)
+	}
+
+	greet, err := MakeFunc[TestContext, func(string) string](di, "greet")
+	if err != nil {
+		t.Fatalf("解析函数服务失败: %v", err)
+	}
+	if out := greet("weave"); out != "test:WEAVE" {
+		t.Errorf("闭包服务调用结果错误: %s", out)
+	}
+
+	if _, err := MakeFunc[TestContext, func() error](di, "greet"); err == nil {
+		t.Error("函数类型不匹配时应该返回错误")
+	}
+
+	if err := Assert(di).DependsOn("greet", "upper").Err(); err != nil {
+		t.Errorf("函数服务的依赖记录错误: %v", err)
+	}
+	registry := di.Extract()
+	if fn := MustGetFromRegistry[func(string) string](registry, "upper"); (*fn)("a") != "A" {
+		t.Error("Extract 中的函数服务错误")
+	}
+}
+
+func TestDI_ProvideFuncNil(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	ProvideFunc(di, "handler", func(ctx *TestContext) func() {
+		return nil
+	})
+	if err := di.Build(); err == nil {
+		t.Error("返回 nil 函数时应该构建失败")
+	}
+}
@@ -0,0 +1,62 @@
+package weave
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Golden 将容器的稳定输出（PrintStable 文本图谱与 DOTStable DOT 图）与 testdata/<测试名>.golden 比较
+// 设置环境变量 WEAVE_UPDATE_GOLDEN=1，或测试包定义的 -update 标志为 true 时写入 golden 文件而不比较
+func Golden[T any](t testing.TB, di *Weave[T]) {
+	t.Helper()
+
+	got := di.PrintDependencyGraph(PrintStable()) + "\n" + di.GenerateDOTGraph(DOTStable())
+	path := filepath.Join("testdata", strings.ReplaceAll(t.Name(), "/", "_")+".golden")
+
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("write golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file %s: %v (set WEAVE_UPDATE_GOLDEN=1 to create it)", path, err)
+	}
+	if string(want) == got {
+		return
+	}
+	wantLines, gotLines := strings.Split(string(want), "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			t.Errorf("output differs from golden file %s at line %d:\n  want: %s\n  got:  %s\n(set WEAVE_UPDATE_GOLDEN=1 to update it)", path, i+1, w, g)
+			return
+		}
+	}
+}
+
+// updateGolden 判断是否需要更新 golden 文件
+// 不注册 -update 标志，避免与测试包自行定义的同名标志冲突
+func updateGolden() bool {
+	if os.Getenv("WEAVE_UPDATE_GOLDEN") == "1" {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		return f.Value.String() == "true"
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGolden(t *testing.T) {
+	// 构建顺序随机，稳定输出模式下结果不受影响
+	for i := 0; i < 5; i++ {
+		di := New[TestContext]()
+		di.SetCtx(&TestContext{Config: "test"})
+		// app -> cache -> config，app -> database -> config，database <-> pool
+		provideGraph(di, "config", "cache: config", "database: config pool", "pool: database", "app: database cache")
+		if err := di.Build(); err != nil {
+			t.Fatalf("构建失败: %v", err)
+		}
+		Golden(t, di)
+	}
+}
+
+func TestDI_StableOutput(t *testing.T) {
+	di := New[TestContext]()
+	Provide(di, "config", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "config"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if dot := di.GenerateDOTGraph(); strings.Contains(dot, "legend") {
+		t.Error("默认模式下没有循环时不应该输出图例")
+	}
+	if dot := di.GenerateDOTGraph(DOTStable()); !strings.Contains(dot, "legend") {
+		t.Errorf("稳定输出模式下应该始终输出图例:\n%s", dot)
+	}
+
+	text := di.PrintDependencyGraph(PrintStable())
+	for _, section := range []string{"🌱 根服务 (无依赖):\n  (无)", "🍃 叶服务 (无被依赖):\n  (无)", "🔗 中间服务:\n  (无)"} {
+		if !strings.Contains(text, section) {
+			t.Errorf("稳定输出模式下应该输出空的部分 %q:\n%s", section, text)
+		}
+	}
+}
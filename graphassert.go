@@ -0,0 +1,130 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// GraphAssertionError 依赖图谱断言失败，按断言顺序汇总
+type GraphAssertionError struct {
+	Errors []error
+}
+
+func (e *GraphAssertionError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		parts = append(parts, err.Error())
+	}
+	return fmt.Sprintf("graph assertions failed: %s", strings.Join(parts, "; "))
+}
+
+// GraphAssertion 依赖图谱的链式断言，用于在测试中描述装配关系
+// 每个断言失败时记录实际值并继续，最后由 Err 汇总返回
+type GraphAssertion struct {
+	graph *DependencyGraph
+	errs  []error
+}
+
+// Assert 对容器当前的依赖图谱进行断言
+func Assert[T any](di *Weave[T]) *GraphAssertion {
+	return AssertGraph(di.GetDependencyGraph())
+}
+
+// AssertGraph 对依赖图谱进行断言
+func AssertGraph(graph *DependencyGraph) *GraphAssertion {
+	return &GraphAssertion{graph: graph}
+}
+
+// AssertInventory 对服务清单描述的依赖图谱进行断言，清单可由其他程序通过 ExportInventory 导出后用 ReadInventory 读取
+func AssertInventory(inventory *Inventory) *GraphAssertion {
+	return AssertGraph(inventory.Graph())
+}
+
+func (a *GraphAssertion) fail(format string, args ...any) *GraphAssertion {
+	a.errs = append(a.errs, fmt.Errorf(format, args...))
+	return a
+}
+
+// DependsOn 断言服务直接依赖 deps 中的每个服务
+func (a *GraphAssertion) DependsOn(service string, deps ...string) *GraphAssertion {
+	actual, ok := a.graph.Dependencies[service]
+	if !ok {
+		return a.fail("service [%s] not found in graph", service)
+	}
+	missing := []string{}
+	for _, dep := range deps {
+		if !containsString(actual, dep) {
+			missing = append(missing, dep)
+		}
+	}
+	if len(missing) > 0 {
+		return a.fail("service [%s] does not depend on %v, dependencies are %v", service, missing, sortedCopy(actual))
+	}
+	return a
+}
+
+// NotDependsOn 断言服务不直接依赖 deps 中的任何服务
+func (a *GraphAssertion) NotDependsOn(service string, deps ...string) *GraphAssertion {
+	actual, ok := a.graph.Dependencies[service]
+	if !ok {
+		return a.fail("service [%s] not found in graph", service)
+	}
+	unexpected := []string{}
+	for _, dep := range deps {
+		if containsString(actual, dep) {
+			unexpected = append(unexpected, dep)
+		}
+	}
+	if len(unexpected) > 0 {
+		return a.fail("service [%s] should not depend on %v, dependencies are %v", service, unexpected, sortedCopy(actual))
+	}
+	return a
+}
+
+// NoCycles 断言图谱中没有循环依赖
+func (a *GraphAssertion) NoCycles() *GraphAssertion {
+	cycles := []string{}
+	for _, component := range stronglyConnectedComponents(a.graph.Dependencies) {
+		if len(component) > 1 || containsString(a.graph.Dependencies[component[0]], component[0]) {
+			cycles = append(cycles, fmt.Sprint(component))
+		}
+	}
+	if len(cycles) > 0 {
+		return a.fail("graph has circular dependencies: %s", strings.Join(cycles, ", "))
+	}
+	return a
+}
+
+// Roots 断言根服务（无依赖但被依赖）恰好为 names
+func (a *GraphAssertion) Roots(names ...string) *GraphAssertion {
+	return a.category(CategoryRoot, "roots", names)
+}
+
+// Leaves 断言叶服务（有依赖但不被依赖）恰好为 names
+func (a *GraphAssertion) Leaves(names ...string) *GraphAssertion {
+	return a.category(CategoryLeaf, "leaves", names)
+}
+
+func (a *GraphAssertion) category(category Category, label string, names []string) *GraphAssertion {
+	actual := []string{}
+	for service := range a.graph.Dependencies {
+		if a.graph.Category(service) == category {
+			actual = append(actual, service)
+		}
+	}
+	sort.Strings(actual)
+	if want := sortedCopy(names); !reflect.DeepEqual(actual, want) {
+		return a.fail("%s are %v, want %v", label, actual, want)
+	}
+	return a
+}
+
+// Err 返回所有失败的断言，全部通过时返回 nil
+func (a *GraphAssertion) Err() error {
+	if len(a.errs) == 0 {
+		return nil
+	}
+	return &GraphAssertionError{Errors: append([]error(nil), a.errs...)}
+}
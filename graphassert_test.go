@@ -0,0 +1,124 @@
+package weave
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAssert(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	provideGraph(di, "config", "db: config", "api: db config")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	err := Assert(di).DependsOn("api", "db", "config").NotDependsOn("config", "api").NoCycles().Roots("config").Leaves("api").Err()
+	if err != nil {
+		t.Fatalf("断言应该全部通过: %v", err)
+	}
+
+	err = Assert(di).
+		DependsOn("db", "api").
+		NotDependsOn("api", "db").
+		Roots("db").
+		Leaves("api").
+		DependsOn("missing").
+		Err()
+	var assertErr *GraphAssertionError
+	if !errors.As(err, &assertErr) || len(assertErr.Errors) != 4 {
+		t.Fatalf("应该汇总 4 个失败的断言，实际为 %v", err)
+	}
+	for _, want := range []string{
+		"service [db] does not depend on [api], dependencies are [config]",
+		"service [api] should not depend on [db], dependencies are [config db]",
+		"roots are [config], want [db]",
+		"service [missing] not found in graph",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("错误信息应包含 %q: %v", want, err)
+		}
+	}
+}
+
+func TestAssert_NoCycles(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "a", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "b")
+		return &ServiceA{Name: "a"}
+	})
+	Provide(di, "b", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "a")
+		return &ServiceA{Name: "b"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	err := Assert(di).NoCycles().Err()
+	if err == nil || !strings.Contains(err.Error(), "circular dependencies: [a b]") {
+		t.Errorf("应该报告循环依赖，实际为 %v", err)
+	}
+}
+
+func TestAssert_TypedServices(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+		return &ServiceC{
+			Name:     "ServiceC",
+			ServiceA: MustMake[TestContext, ServiceA](di, "serviceA"),
+			ServiceB: MustMake[TestContext, ServiceB](di, "serviceB"),
+		}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	err := Assert(di).
+		DependsOn("serviceB", "serviceA").
+		DependsOn("serviceC", "serviceA", "serviceB").
+		NotDependsOn("serviceA", "serviceB", "serviceC").
+		NoCycles().
+		Roots("serviceA").
+		Leaves("serviceC").
+		Err()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAssertInventory(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	provideGraph(di, "config", "db: config", "api: db config")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := di.ExportInventory(&buf); err != nil {
+		t.Fatalf("导出清单失败: %v", err)
+	}
+
+	inventory, err := ReadInventory(&buf)
+	if err != nil {
+		t.Fatalf("读取清单失败: %v", err)
+	}
+	err = AssertInventory(inventory).DependsOn("api", "db", "config").NoCycles().Roots("config").Leaves("api").Err()
+	if err != nil {
+		t.Errorf("清单中的依赖图谱应与容器一致: %v", err)
+	}
+
+	if _, err := ReadInventory(strings.NewReader(`{"schemaVersion": 99, "services": []}`)); err == nil {
+		t.Error("格式版本过高时应该返回错误")
+	}
+}
@@ -0,0 +1,211 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// GroupOrder 分组内服务的排列顺序
+type GroupOrder int
+
+const (
+	// GroupByRegistration 按注册顺序
+	GroupByRegistration GroupOrder = iota
+	// GroupByPriority 按优先级从高到低，优先级相同时按注册顺序
+	GroupByPriority
+	// GroupByName 按服务名称字母顺序
+	GroupByName
+)
+
+// Tag 为服务添加分组标签
+func (s *Weave[T]) Tag(name string, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries.Get(name)
+	if !ok {
+		return s.notFound(name)
+	}
+	s.groupMu.Lock()
+	defer s.groupMu.Unlock()
+	for _, tag := range tags {
+		if !containsString(e.tags, tag) {
+			e.tags = append(e.tags, tag)
+		}
+	}
+	return nil
+}
+
+// SetPriority 设置服务在分组内的优先级，越大越靠前
+func (s *Weave[T]) SetPriority(name string, priority int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries.Get(name)
+	if !ok {
+		return s.notFound(name)
+	}
+	s.groupMu.Lock()
+	e.priority = priority
+	s.groupMu.Unlock()
+	return nil
+}
+
+// Group 按指定顺序返回带有该标签的服务名称
+// 不持有容器锁，可在 builder 中调用；与 Tag、SetPriority 并发调用是安全的
+func (s *Weave[T]) Group(tag string, order GroupOrder) []string {
+	type member struct {
+		name     string
+		priority int
+		seq      uint64
+	}
+	members := []member{}
+	s.groupMu.RLock()
+	s.entries.Range(func(name string, e *entry[*T]) bool {
+		if containsString(e.tags, tag) {
+			members = append(members, member{name: name, priority: e.priority, seq: e.seq})
+		}
+		return true
+	})
+	s.groupMu.RUnlock()
+
+	sort.Slice(members, func(i, j int) bool {
+		switch order {
+		case GroupByPriority:
+			if members[i].priority != members[j].priority {
+				return members[i].priority > members[j].priority
+			}
+			return members[i].seq < members[j].seq
+		case GroupByName:
+			return members[i].name < members[j].name
+		default:
+			return members[i].seq < members[j].seq
+		}
+	})
+
+	names := make([]string, len(members))
+	for i, m := range members {
+		names[i] = m.name
+	}
+	return names
+}
+
+// GroupLen 返回带有该标签的服务数量，不分配名称列表
+func (s *Weave[T]) GroupLen(tag string) int {
+	s.groupMu.RLock()
+	defer s.groupMu.RUnlock()
+
+	return s.entries.CountFunc(func(_ string, e *entry[*T]) bool {
+		return containsString(e.tags, tag)
+	})
+}
+
+// MakeGroup 按指定顺序获取带有该标签的所有服务
+// 被跳过的可选服务会被忽略，其余服务获取失败或类型不符时 panic
+func MakeGroup[T any, R any](di *Weave[T], tag string, order GroupOrder) []*R {
+	names := di.Group(tag, order)
+	result := make([]*R, 0, len(names))
+	for _, name := range names {
+		obj, err := di.GetService(name)
+		if err != nil {
+			if _, skipped := di.skipped.Get(name); skipped {
+				continue
+			}
+			panic(err)
+		}
+		r, ok := obj.(*R)
+		if !ok {
+			panic(&ErrTypeMismatch{Name: name, Want: fmt.Sprintf("%T", r), Got: fmt.Sprintf("%T", obj)})
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// MakeGroupInterface 按指定顺序以接口 I 获取带有该标签的所有服务
+// 只解析并校验类型，不登记构建要求；需要 Build 前校验组内所有服务时使用 RequireGroupInterface。
+// 被跳过的可选服务会被忽略，其余服务获取失败或未实现 I 时 panic，在 builder 中调用时转换为构建错误
+func MakeGroupInterface[T any, I any](di *Weave[T], tag string, order GroupOrder) []I {
+	iface := interfaceType[I]()
+	names := di.Group(tag, order)
+	result := make([]I, 0, len(names))
+	for _, name := range names {
+		obj, err := di.GetService(name)
+		if err != nil {
+			if _, skipped := di.skipped.Get(name); skipped {
+				continue
+			}
+			panic(err)
+		}
+		i, ok := obj.(I)
+		if !ok {
+			panic(&ErrTypeMismatch{Name: name, Want: iface.String(), Got: fmt.Sprintf("%T", obj)})
+		}
+		result = append(result, i)
+	}
+	return result
+}
+
+// RequireGroupInterface 登记带有该标签的服务必须实现接口 I，Build 前校验，未实现的服务导致构建失败
+// 已构建的容器在下次 Build 时重新校验；不能在 builder 中调用
+func RequireGroupInterface[T any, I any](di *Weave[T], tag string) {
+	iface := interfaceType[I]()
+
+	di.mu.Lock()
+	defer di.mu.Unlock()
+
+	if di.requireGroupInterface(tag, iface) {
+		di.built = false
+		di.resetBuilt()
+	}
+}
+
+// interfaceType 返回接口类型 I，I 不是接口时 panic
+func interfaceType[I any]() reflect.Type {
+	iface := reflect.TypeOf((*I)(nil)).Elem()
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Errorf("%s is not an interface type", iface))
+	}
+	return iface
+}
+
+// requireGroupInterface 登记分组必须实现的接口，返回是否为新的要求，调用方需持有容器锁
+func (s *Weave[T]) requireGroupInterface(tag string, iface reflect.Type) bool {
+	ifaces, _ := s.groupInterfaces.Get(tag)
+	for _, t := range ifaces {
+		if t == iface {
+			return false
+		}
+	}
+	s.groupInterfaces.Set(tag, append(ifaces, iface))
+	return true
+}
+
+// validateGroupInterfaces 构建前校验分组内的服务实现了登记的接口
+func (s *Weave[T]) validateGroupInterfaces() error {
+	tags := s.groupInterfaces.Keys()
+	sort.Strings(tags)
+	for _, tag := range tags {
+		ifaces, _ := s.groupInterfaces.Get(tag)
+		for _, name := range s.Group(tag, GroupByName) {
+			e, _ := s.entries.Get(name)
+			for _, iface := range ifaces {
+				if got := e.instanceType(); !got.Implements(iface) {
+					return fmt.Errorf("service [%s] in group [%s] is %s, does not implement %s", name, tag, got, iface)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// containsString 判断切片中是否包含指定字符串
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package weave
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type Middleware struct {
+	Name string
+}
+
+func TestDI_MakeGroup(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	for _, name := range []string{"logging", "auth", "recovery", "metrics"} {
+		name := name
+		Provide(di, name, func(ctx *TestContext) *Middleware {
+			return &Middleware{Name: name}
+		})
+		if err := di.Tag(name, "middleware"); err != nil {
+			t.Fatalf("添加标签失败: %v", err)
+		}
+	}
+	di.SetPriority("auth", 100)
+	di.SetPriority("recovery", 200)
+
+	var chain []*Middleware
+	Provide(di, "server", func(ctx *TestContext) *ServiceA {
+		chain = MakeGroup[TestContext, Middleware](di, "middleware", GroupByPriority)
+		return &ServiceA{Name: "server"}
+	})
+
+	if n := di.GroupLen("middleware"); n != 4 {
+		t.Errorf("分组计数错误: %d", n)
+	}
+	if n := di.GroupLen("missing"); n != 0 {
+		t.Errorf("不存在的分组计数应该为 0: %d", n)
+	}
+
+	if err := di.Tag("missing", "middleware"); err == nil {
+		t.Error("为不存在的服务添加标签应该返回错误")
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	names := func(ms []*Middleware) []string {
+		result := []string{}
+		for _, m := range ms {
+			result = append(result, m.Name)
+		}
+		return result
+	}
+
+	if got := names(chain); !equalSlices(got, []string{"recovery", "auth", "logging", "metrics"}) {
+		t.Errorf("按优先级排序错误: %v", got)
+	}
+	if got := names(MakeGroup[TestContext, Middleware](di, "middleware", GroupByRegistration)); !equalSlices(got, []string{"logging", "auth", "recovery", "metrics"}) {
+		t.Errorf("按注册顺序排序错误: %v", got)
+	}
+	if got := names(MakeGroup[TestContext, Middleware](di, "middleware", GroupByName)); !equalSlices(got, []string{"auth", "logging", "metrics", "recovery"}) {
+		t.Errorf("按名称排序错误: %v", got)
+	}
+
+	// 获取分组会记录依赖关系
+	if err := Assert(di).DependsOn("server", "auth", "logging", "metrics", "recovery").Err(); err != nil {
+		t.Errorf("server应该依赖分组内的4个服务: %v", err)
+	}
+}
+
+type plugin interface {
+	PluginName() string
+}
+
+type authPlugin struct{}
+
+func (p *authPlugin) PluginName() string { return "auth" }
+
+type cachePlugin struct{}
+
+func (p *cachePlugin) PluginName() string { return "cache" }
+
+func TestDI_MakeGroupInterface(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "auth", func(ctx *TestContext) *authPlugin { return &authPlugin{} })
+	Provide(di, "cache", func(ctx *TestContext) *cachePlugin { return &cachePlugin{} })
+	di.Tag("auth", "plugin")
+	di.Tag("cache", "plugin")
+
+	var plugins []plugin
+	Provide(di, "host", func(ctx *TestContext) *ServiceA {
+		plugins = MakeGroupInterface[TestContext, plugin](di, "plugin", GroupByName)
+		return &ServiceA{Name: "host"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if len(plugins) != 2 || plugins[0].PluginName() != "auth" || plugins[1].PluginName() != "cache" {
+		t.Errorf("应该按名称顺序获取所有插件: %v", plugins)
+	}
+
+	// 解析分组不登记构建要求，未实现接口的服务加入分组后构建不受影响
+	Provide(di, "broken", func(ctx *TestContext) *Middleware { return &Middleware{Name: "broken"} })
+	di.Tag("broken", "plugin")
+	if err := di.Build(); err != nil {
+		t.Fatalf("MakeGroupInterface 不应该登记构建要求: %v", err)
+	}
+	func() {
+		defer func() {
+			if _, ok := recover().(*ErrTypeMismatch); !ok {
+				t.Error("构建后解析到未实现接口的服务应该 panic")
+			}
+		}()
+		MakeGroupInterface[TestContext, plugin](di, "plugin", GroupByName)
+	}()
+
+	// 显式登记后，重新构建应该失败并指出该服务
+	RequireGroupInterface[TestContext, plugin](di, "plugin")
+	err := di.Build()
+	if err == nil || !strings.Contains(err.Error(), "service [broken] in group [plugin] is *weave.Middleware, does not implement weave.plugin") {
+		t.Errorf("未实现接口的服务应该导致构建失败: %v", err)
+	}
+}
+
+func TestDI_MakeGroupInterfaceInBuilder(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "auth", func(ctx *TestContext) *authPlugin { return &authPlugin{} })
+	Provide(di, "broken", func(ctx *TestContext) *Middleware { return &Middleware{Name: "broken"} })
+	di.Tag("auth", "plugin")
+	di.Tag("broken", "plugin")
+	Provide(di, "host", func(ctx *TestContext) *ServiceA {
+		MakeGroupInterface[TestContext, plugin](di, "plugin", GroupByName)
+		return &ServiceA{Name: "host"}
+	})
+
+	// 首次构建时在 builder 中发现未实现接口的服务
+	err := di.Build()
+	var mismatch *ErrTypeMismatch
+	if !errors.As(err, &mismatch) || mismatch.Name != "broken" || mismatch.Want != "weave.plugin" {
+		t.Errorf("应该返回指出未实现接口服务的构建错误，实际为 %v", err)
+	}
+}
+
+func TestDI_RequireGroupInterface(t *testing.T) {
+	di := New[TestContext]()
+	Provide(di, "broken", func(ctx *TestContext) *Middleware { return &Middleware{Name: "broken"} })
+	di.Tag("broken", "plugin")
+	RequireGroupInterface[TestContext, plugin](di, "plugin")
+
+	if err := di.Build(); err == nil || !strings.Contains(err.Error(), "service [broken]") {
+		t.Errorf("构建前应该校验分组内的服务: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("非接口类型应该 panic")
+		}
+	}()
+	RequireGroupInterface[TestContext, Middleware](di, "plugin")
+}
+
+func TestDI_GroupConcurrentTag(t *testing.T) {
+	di := New[TestContext]()
+	for i := 0; i < 20; i++ {
+		Provide(di, fmt.Sprintf("plugin%d", i), func(ctx *TestContext) *ServiceA { return &ServiceA{} })
+	}
+
+	// 配合 -race 运行，读取分组及其大小不应该与 Tag、SetPriority 产生数据竞争
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			name := fmt.Sprintf("plugin%d", i)
+			_ = di.Tag(name, "plugins")
+			_ = di.SetPriority(name, i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			di.Group("plugins", GroupByPriority)
+			di.GroupLen("plugins")
+		}
+	}()
+	wg.Wait()
+
+	if group := di.Group("plugins", GroupByPriority); len(group) != 20 || group[0] != "plugin19" {
+		t.Errorf("分组顺序错误: %v", group)
+	}
+}
@@ -0,0 +1,151 @@
+package weave
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ErrGroupOrderConflict 服务的依赖关系与 OrderGroups 指定的分组顺序相反
+type ErrGroupOrderConflict struct {
+	// Name 靠前分组中的服务
+	Name  string
+	Group string
+	// Dependency Name 直接或间接依赖的靠后分组中的服务，与 Name 相同时表示服务同时属于两个有序分组
+	Dependency      string
+	DependencyGroup string
+}
+
+func (e *ErrGroupOrderConflict) Error() string {
+	if e.Name == e.Dependency {
+		return fmt.Sprintf("service [%s] is in both ordered groups [%s] and [%s]", e.Name, e.Group, e.DependencyGroup)
+	}
+	return fmt.Sprintf("service [%s] in group [%s] depends on [%s] in later group [%s]", e.Name, e.Group, e.Dependency, e.DependencyGroup)
+}
+
+// OrderGroups 指定分组的构建与启动顺序：前一个分组的所有服务先于后一个分组的任何服务构建，
+// BuildOrder 与 ForEachInOrder 按该顺序排列，无需在组员之间声明依赖；再次调用替换之前的顺序。
+// 顺序约束不会出现在依赖图谱中，DOTGroupOrder 可在 DOT 图中显示；
+// Build 时靠前分组的服务直接或间接依赖靠后分组的服务，或服务同时属于两个有序分组，返回 *ErrGroupOrderConflict
+func (s *Weave[T]) OrderGroups(tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if seen[tag] {
+			return fmt.Errorf("group [%s] is ordered twice", tag)
+		}
+		seen[tag] = true
+	}
+	s.groupOrder = append([]string{}, tags...)
+	return nil
+}
+
+// groupWaves 返回服务 -> 所属有序分组的下标，未属于任何有序分组的服务不出现
+// 同时属于多个有序分组时取最靠前的分组
+func (s *Weave[T]) groupWaves() map[string]int {
+	if len(s.groupOrder) == 0 {
+		return nil
+	}
+	index := make(map[string]int, len(s.groupOrder))
+	for i, tag := range s.groupOrder {
+		index[tag] = i
+	}
+	waves := make(map[string]int)
+	s.entries.Range(func(name string, e *entry[*T]) bool {
+		for _, tag := range e.tags {
+			if i, ok := index[tag]; ok {
+				if w, exists := waves[name]; !exists || i < w {
+					waves[name] = i
+				}
+			}
+		}
+		return true
+	})
+	return waves
+}
+
+// groupWave 返回按有序分组排序用的函数，不属于任何有序分组的服务排在所有分组之后
+func (s *Weave[T]) groupWave() func(name string) int {
+	waves := s.groupWaves()
+	return func(name string) int {
+		if w, ok := waves[name]; ok {
+			return w
+		}
+		return len(s.groupOrder)
+	}
+}
+
+// sortByGroupWave 稳定地将有序分组的服务按分组顺序排在前面，其余服务保持原顺序排在最后
+func (s *Weave[T]) sortByGroupWave(names []string) {
+	if len(s.groupOrder) == 0 {
+		return
+	}
+	wave := s.groupWave()
+	sort.SliceStable(names, func(i, j int) bool {
+		return wave(names[i]) < wave(names[j])
+	})
+}
+
+// addGroupOrderEdges 为拓扑排序加入分组顺序约束：每个服务依赖前一个有序分组的所有已构建服务
+// dependencies 中的依赖列表会被复制，不影响原数据
+func (s *Weave[T]) addGroupOrderEdges(dependencies map[string][]string) {
+	waves := s.groupWaves()
+	if len(waves) == 0 {
+		return
+	}
+	members := make([][]string, len(s.groupOrder))
+	for name, w := range waves {
+		if _, ok := dependencies[name]; ok {
+			members[w] = append(members[w], name)
+		}
+	}
+	for w := 1; w < len(members); w++ {
+		for _, name := range members[w] {
+			dependencies[name] = append(append([]string{}, dependencies[name]...), members[w-1]...)
+		}
+	}
+}
+
+// checkGroupOrder 检查依赖关系是否与分组顺序相反
+func (s *Weave[T]) checkGroupOrder() error {
+	if len(s.groupOrder) == 0 {
+		return nil
+	}
+	waves := s.groupWaves()
+	names := make([]string, 0, len(waves))
+	for name := range waves {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	dependencies, _ := s.edges.materialize(s.entries.Keys())
+
+	for _, name := range names {
+		e, _ := s.entries.Get(name)
+		for _, tag := range e.tags {
+			for i := waves[name] + 1; i < len(s.groupOrder); i++ {
+				if s.groupOrder[i] == tag {
+					return &ErrGroupOrderConflict{Name: name, Group: s.groupOrder[waves[name]], Dependency: name, DependencyGroup: tag}
+				}
+			}
+		}
+
+		seen := map[string]bool{name: true}
+		queue := []string{name}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, dep := range dependencies[current] {
+				if seen[dep] {
+					continue
+				}
+				seen[dep] = true
+				if w, ok := waves[dep]; ok && w > waves[name] {
+					return &ErrGroupOrderConflict{Name: name, Group: s.groupOrder[waves[name]], Dependency: dep, DependencyGroup: s.groupOrder[w]}
+				}
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return nil
+}
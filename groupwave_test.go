@@ -0,0 +1,117 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// assertWaves 检查 names 中每个分组的服务都排在下一个分组的服务之前
+func assertWaves(t *testing.T, names []string, waves ...[]string) {
+	t.Helper()
+	position := make(map[string]int, len(names))
+	for i, name := range names {
+		position[name] = i
+	}
+	for w := 1; w < len(waves); w++ {
+		for _, before := range waves[w-1] {
+			for _, after := range waves[w] {
+				if position[before] > position[after] {
+					t.Errorf("[%s] 应该排在 [%s] 之前: %v", before, after, names)
+				}
+			}
+		}
+	}
+}
+
+func TestDI_OrderGroups(t *testing.T) {
+	di := New[TestContext](WithShuffledBuild(7))
+	di.SetCtx(&TestContext{Config: "test"})
+	provideGraph(di, "httpServer", "grpcServer", "userCache", "orderCache", "schema", "seed", "logger")
+	for _, name := range []string{"httpServer", "grpcServer"} {
+		_ = di.Tag(name, "servers")
+	}
+	for _, name := range []string{"userCache", "orderCache"} {
+		_ = di.Tag(name, "caches")
+	}
+	for _, name := range []string{"schema", "seed"} {
+		_ = di.Tag(name, "migrations")
+	}
+	if err := di.OrderGroups("migrations", "caches", "servers"); err != nil {
+		t.Fatalf("设置分组顺序失败: %v", err)
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	waves := [][]string{{"schema", "seed"}, {"userCache", "orderCache"}, {"httpServer", "grpcServer"}}
+	assertWaves(t, di.BuildOrder(), waves...)
+
+	started := []string{}
+	di.ForEachInOrder(func(name string, _ any) error {
+		started = append(started, name)
+		return nil
+	})
+	assertWaves(t, started, waves...)
+
+	// 顺序约束不作为真实依赖
+	if deps := di.GetDependencyGraph().Dependencies["userCache"]; len(deps) != 0 {
+		t.Errorf("顺序约束不应该出现在依赖图谱中: %v", deps)
+	}
+	if dot := di.GenerateDOTGraph(); strings.Contains(dot, "label=\"order\"") {
+		t.Error("默认不应该显示顺序约束")
+	}
+	if dot := di.GenerateDOTGraph(DOTGroupOrder()); !strings.Contains(dot, "\"schema\" -> \"userCache\" [style=dotted, color=blue, label=\"order\"]") {
+		t.Errorf("DOTGroupOrder 应该显示顺序约束:\n%s", dot)
+	}
+
+	if err := di.OrderGroups("caches", "caches"); err == nil {
+		t.Error("重复的分组应该返回错误")
+	}
+}
+
+func TestDI_OrderGroupsConflict(t *testing.T) {
+	di := New[TestContext](WithShuffledBuild(7))
+	di.SetCtx(&TestContext{Config: "test"})
+	provideGraph(di, "httpServer", "grpcServer", "userCache", "orderCache", "schema", "seed", "logger")
+	for _, name := range []string{"httpServer", "grpcServer"} {
+		_ = di.Tag(name, "servers")
+	}
+	for _, name := range []string{"userCache", "orderCache"} {
+		_ = di.Tag(name, "caches")
+	}
+	for _, name := range []string{"schema", "seed"} {
+		_ = di.Tag(name, "migrations")
+	}
+	Provide(di, "userCache", func(ctx *TestContext) *ServiceA {
+		// 缓存依赖了靠后分组中的服务
+		return &ServiceA{Name: "userCache(" + MustMake[TestContext, ServiceA](di, "httpServer").Name + ")"}
+	})
+	di.OrderGroups("migrations", "caches", "servers")
+
+	var conflict *ErrGroupOrderConflict
+	if err := di.Build(); !errors.As(err, &conflict) {
+		t.Fatalf("依赖与分组顺序相反时应该返回冲突错误，实际为: %v", err)
+	}
+	if conflict.Name != "userCache" || conflict.Dependency != "httpServer" || conflict.DependencyGroup != "servers" {
+		t.Errorf("冲突信息错误: %+v", conflict)
+	}
+
+	di = New[TestContext](WithShuffledBuild(7))
+	di.SetCtx(&TestContext{Config: "test"})
+	provideGraph(di, "httpServer", "grpcServer", "userCache", "orderCache", "schema", "seed", "logger")
+	for _, name := range []string{"httpServer", "grpcServer"} {
+		_ = di.Tag(name, "servers")
+	}
+	for _, name := range []string{"userCache", "orderCache"} {
+		_ = di.Tag(name, "caches")
+	}
+	for _, name := range []string{"schema", "seed"} {
+		_ = di.Tag(name, "migrations")
+	}
+	di.Tag("schema", "servers")
+	di.OrderGroups("migrations", "caches", "servers")
+	if err := di.Build(); !errors.As(err, &conflict) || conflict.Name != "schema" || conflict.Dependency != "schema" {
+		t.Errorf("同时属于两个有序分组时应该返回冲突错误: %v", err)
+	}
+}
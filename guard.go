@@ -0,0 +1,69 @@
+package weave
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Guard 为服务关联一个由容器管理的读写锁，之后可通过 WithRead、WithWrite 在锁内访问实例
+// 适合运行时会被修改的单例，避免每个调用方各自记得加锁；需在首次 WithRead、WithWrite 之前调用
+func (s *Weave[T]) Guard(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries.Get(name)
+	if !ok {
+		return s.notFound(name)
+	}
+	if e.guard == nil {
+		e.guard = new(sync.RWMutex)
+	}
+	return nil
+}
+
+// WithRead 获取服务并在其读锁内调用 fn
+// 锁不可重入：在 fn 中对同一服务调用 WithWrite 会死锁，嵌套 WithRead 在有写入等待时同样会死锁
+func WithRead[T any, R any](di *Weave[T], name string, fn func(*R)) error {
+	instance, guard, err := guarded[T, R](di, name)
+	if err != nil {
+		return err
+	}
+	guard.RLock()
+	defer guard.RUnlock()
+	fn(instance)
+	return nil
+}
+
+// WithWrite 获取服务并在其写锁内调用 fn，锁不可重入，见 WithRead
+func WithWrite[T any, R any](di *Weave[T], name string, fn func(*R)) error {
+	instance, guard, err := guarded[T, R](di, name)
+	if err != nil {
+		return err
+	}
+	guard.Lock()
+	defer guard.Unlock()
+	fn(instance)
+	return nil
+}
+
+// guarded 获取服务实例及其读写锁
+// 与 GetService 一样不持有容器锁，可在 builder 中调用；entries 与 renames 自身是并发安全的
+func guarded[T any, R any](di *Weave[T], name string) (*R, *sync.RWMutex, error) {
+	obj, err := di.GetService(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	instance, ok := obj.(*R)
+	if !ok {
+		return nil, nil, &ErrTypeMismatch{Name: name, Want: fmt.Sprintf("%T", instance), Got: fmt.Sprintf("%T", obj)}
+	}
+
+	_, e, err := di.lookup(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if e.guard == nil {
+		return nil, nil, fmt.Errorf("service [%s] is not guarded", name)
+	}
+	return instance, e.guard, nil
+}
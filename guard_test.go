@@ -0,0 +1,133 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDI_Guard(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "counter", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "0"}
+	})
+	Provide(di, "plain", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "plain"}
+	})
+	if err := di.Guard("counter"); err != nil {
+		t.Fatalf("Guard 失败: %v", err)
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	// 并发写入应该被串行化
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := WithWrite(di, "counter", func(a *ServiceA) {
+				a.Name += "+"
+			})
+			if err != nil {
+				t.Errorf("WithWrite 失败: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var name string
+	if err := WithRead(di, "counter", func(a *ServiceA) { name = a.Name }); err != nil {
+		t.Fatalf("WithRead 失败: %v", err)
+	}
+	if name != "0"+strings.Repeat("+", 100) {
+		t.Errorf("并发写入结果错误: %s", name)
+	}
+
+	if err := WithRead(di, "plain", func(*ServiceB) {}); err == nil || !strings.Contains(err.Error(), "is not guarded") {
+		t.Errorf("未启用 Guard 的服务应该报错，实际为 %v", err)
+	}
+	var mismatch *ErrTypeMismatch
+	if err := WithRead(di, "counter", func(*ServiceB) {}); !errors.As(err, &mismatch) {
+		t.Errorf("类型不匹配应该返回 ErrTypeMismatch，实际为 %v", err)
+	}
+	var notFound *ErrServiceNotFound
+	if err := di.Guard("missing"); !errors.As(err, &notFound) {
+		t.Errorf("不存在的服务应该返回 ErrServiceNotFound，实际为 %v", err)
+	}
+}
+
+func TestDI_GuardNotReentrant(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "counter", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "counter"}
+	})
+	_ = di.Guard("counter")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	// 读锁内发起的写入会等待读锁释放，锁不可重入
+	written := make(chan struct{})
+	err := WithRead(di, "counter", func(a *ServiceA) {
+		go func() {
+			_ = WithWrite(di, "counter", func(a *ServiceA) { a.Name = "written" })
+			close(written)
+		}()
+		select {
+		case <-written:
+			t.Error("持有读锁时写入不应该完成")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+	if err != nil {
+		t.Fatalf("WithRead 失败: %v", err)
+	}
+
+	select {
+	case <-written:
+	case <-time.After(time.Second):
+		t.Fatal("读锁释放后写入应该完成")
+	}
+	if MustMake[TestContext, ServiceA](di, "counter").Name != "written" {
+		t.Error("写入应该生效")
+	}
+}
+
+func TestDI_GuardInBuilder(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "counter", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "counter"}
+	})
+	Provide(di, "reader", func(ctx *TestContext) *ServiceB {
+		b := &ServiceB{Name: "reader"}
+		err := WithRead(di, "counter", func(a *ServiceA) { b.ServiceA = a })
+		if err != nil {
+			panic(err)
+		}
+		return b
+	})
+	_ = di.Guard("counter")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- di.Build()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("构建失败: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("在 builder 中调用 WithRead 不应该死锁")
+	}
+	if !containsString(di.GetDependencyGraph().Dependencies["reader"], "counter") {
+		t.Error("WithRead 应该记录依赖关系")
+	}
+}
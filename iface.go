@@ -0,0 +1,71 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ProvideInterface 注册接口类型的服务，builder 返回接口值而不是具体类型的指针
+// 容器直接保存接口中的值，不分配 *I 占位符：Extract 中的值可以直接断言为 I，
+// 值类型实现的接口没有实例指针，InstanceID 返回 false，Swap 替换保存的值而不原地复制；
+// 接口服务无法以占位符交给循环依赖，构建期间被循环引用时返回 *ErrResolutionInProgress
+func ProvideInterface[T any, I any](di *Weave[T], name string, builder func(*T) I) {
+	iface := reflect.TypeOf((*I)(nil)).Elem()
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("service [%s] type %s is not an interface", name, iface.Kind()))
+	}
+	di.assignEntry(name, &entry[*T]{
+		builder: func(ctx *T) any {
+			// nil 接口转换后仍为 nil，带类型的 nil 指针同样视为构建失败
+			value := any(builder(ctx))
+			if value == nil || isNilPointer(value) {
+				return nil
+			}
+			return value
+		},
+		instance: (*I)(nil),
+		direct:   true,
+		iface:    iface,
+	})
+}
+
+// MakeInterface 解析接口类型的服务
+func MakeInterface[T any, I any](di *Weave[T], name string) (I, error) {
+	var zero I
+	obj, err := di.GetService(name)
+	if err != nil {
+		return zero, err
+	}
+	if unbuilt, ok := obj.(*I); ok && unbuilt == nil {
+		return zero, &ErrNotBuilt{Name: name}
+	}
+	value, ok := obj.(I)
+	if !ok {
+		return zero, &ErrTypeMismatch{Name: name, Want: reflect.TypeOf((*I)(nil)).Elem().String(), Got: fmt.Sprintf("%T", obj)}
+	}
+	return value, nil
+}
+
+// instanceType 返回服务实例的类型，接口服务返回注册的接口类型
+func (e *entry[T]) instanceType() reflect.Type {
+	if e.iface != nil {
+		return e.iface
+	}
+	return reflect.TypeOf(e.instance)
+}
+
+// accepts 判断值能否作为服务的实例，接口服务接受实现了该接口的任意值
+func (e *entry[T]) accepts(value any) bool {
+	if e.iface != nil {
+		return reflect.TypeOf(value).Implements(e.iface)
+	}
+	return reflect.TypeOf(value) == reflect.TypeOf(e.instance)
+}
+
+// unbuiltInstance 返回服务未构建时保存的带类型 nil 指针
+func (e *entry[T]) unbuiltInstance() any {
+	if e.iface != nil {
+		return reflect.Zero(reflect.PtrTo(e.iface)).Interface()
+	}
+	return reflect.Zero(reflect.TypeOf(e.instance)).Interface()
+}
@@ -0,0 +1,128 @@
+package weave
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type englishGreeter struct{ name string }
+
+func (g englishGreeter) Greet() string { return "hello " + g.name }
+
+func TestDI_ProvideInterface(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	ProvideInterface(di, "greeter", func(ctx *TestContext) greeter {
+		// 值类型实现接口，无法通过指针占位符复制
+		return englishGreeter{name: ctx.Config}
+	})
+	Provide(di, "consumer", func(ctx *TestContext) *ServiceA {
+		g, err := MakeInterface[TestContext, greeter](di, "greeter")
+		if err != nil {
+			t.Fatalf("解析接口服务失败: %v", err)
+		}
+		return &ServiceA{Name: g.Greet()}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if MustMake[TestContext, ServiceA](di, "consumer").Name != "hello test" {
+		t.Error("依赖方应该拿到接口值")
+	}
+	if deps := di.DirectDependencies("consumer"); len(deps) != 1 || deps[0] != "greeter" {
+		t.Errorf("接口服务的依赖应该被记录: %v", deps)
+	}
+	if obj, _ := di.Extract().Get("greeter"); obj.(greeter).Greet() != "hello test" {
+		t.Error("Extract 中应该直接保存接口值")
+	}
+
+	var mismatch *ErrTypeMismatch
+	if _, err := MakeInterface[TestContext, error](di, "greeter"); !errors.As(err, &mismatch) {
+		t.Errorf("类型不符时应该返回 ErrTypeMismatch，实际为 %v", err)
+	}
+}
+
+func TestDI_ProvideInterfaceNil(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	ProvideInterface(di, "greeter", func(ctx *TestContext) greeter { return nil })
+	if err := di.Build(); err == nil {
+		t.Error("返回 nil 接口时应该构建失败")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("非接口类型应该 panic")
+		}
+	}()
+	ProvideInterface(di, "value", func(ctx *TestContext) ServiceA { return ServiceA{} })
+}
+
+func TestDI_ProvideInterfaceReplace(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	ProvideInterface(di, "greeter", func(ctx *TestContext) greeter {
+		return englishGreeter{name: ctx.Config}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if _, ok := di.InstanceID("greeter"); ok {
+		t.Error("值类型实现的接口服务没有实例指针")
+	}
+
+	if _, err := di.Swap("greeter", englishGreeter{name: "swap"}); err != nil {
+		t.Fatalf("替换接口服务失败: %v", err)
+	}
+	if g, _ := MakeInterface[TestContext, greeter](di, "greeter"); g.Greet() != "hello swap" {
+		t.Errorf("Swap 后应该解析到新值: %v", g.Greet())
+	}
+	if _, err := di.Swap("greeter", &ServiceA{}); err == nil {
+		t.Error("未实现接口的替换值应该被拒绝")
+	}
+
+	replacement := &englishGreeter{name: "pointer"}
+	if err := di.Replace("greeter", replacement); err != nil {
+		t.Fatalf("以指针实现替换接口服务失败: %v", err)
+	}
+	if id, ok := di.InstanceID("greeter"); !ok || uintptr(id) != reflect.ValueOf(replacement).Pointer() {
+		t.Error("指针实现的接口服务应该有实例指针")
+	}
+
+	if err := di.ReplaceBuilder("greeter", func(ctx *TestContext) any {
+		return englishGreeter{name: "rebuilt"}
+	}, false); err != nil {
+		t.Fatalf("重新构建接口服务失败: %v", err)
+	}
+	if g, _ := MakeInterface[TestContext, greeter](di, "greeter"); g.Greet() != "hello rebuilt" {
+		t.Errorf("重新构建后应该解析到新值: %v", g.Greet())
+	}
+}
+
+func TestDI_ProvideInterfaceCycle(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	var cycleErr error
+	ProvideInterface(di, "greeter", func(ctx *TestContext) greeter {
+		_, cycleErr = MakeInterface[TestContext, greeter](di, "greeter")
+		return englishGreeter{}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	var inProgress *ErrResolutionInProgress
+	if !errors.As(cycleErr, &inProgress) || inProgress.Name != "greeter" {
+		t.Errorf("接口服务没有占位符，循环引用时应该返回 ErrResolutionInProgress，实际为 %v", cycleErr)
+	}
+}
@@ -0,0 +1,104 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ImportPolicy 导入容器时服务名称冲突的处理策略
+type ImportPolicy int
+
+const (
+	// ImportReject 名称冲突时返回错误，不导入任何服务
+	ImportReject ImportPolicy = iota
+	// ImportOverwrite 导入的服务覆盖已有的同名服务
+	ImportOverwrite
+	// ImportKeepExisting 保留已有的同名服务，跳过导入
+	ImportKeepExisting
+)
+
+// Import 将另一个容器的服务注册（而非已构建的实例）合并到当前容器，名称冲突时返回错误
+// 见 ImportWith
+func (s *Weave[T]) Import(other *Weave[T]) error {
+	return s.ImportWith(other, ImportReject)
+}
+
+// ImportWith 按指定的冲突策略将另一个容器的服务注册合并到当前容器
+// 服务的 builder、标签、优先级、可选等属性以及已记录的依赖关系随之导入，Ready 回调和断言也一并导入；
+// ProvideValue 注册的服务共享同一实例。导入后 other 的服务解析转发给当前容器，
+// 使导入的 builder 中对 other 的 MustMake 调用解析到合并后的服务，other 不应再单独构建
+func (s *Weave[T]) ImportWith(other *Weave[T], policy ImportPolicy) error {
+	if other == s {
+		return fmt.Errorf("cannot import a weave into itself")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	other.mu.Lock()
+	defer other.mu.Unlock()
+
+	names := other.entries.Keys()
+	sort.Strings(names)
+	if policy == ImportReject {
+		for _, name := range names {
+			if s.entries.Contains(name) {
+				return fmt.Errorf("service [%s] already exists, cannot import", name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if policy == ImportKeepExisting && s.entries.Contains(name) {
+			continue
+		}
+		src, _ := other.entries.Get(name)
+		e := &entry[*T]{
+			builder:   src.builder,
+			instance:  src.instance,
+			built:     src.built && src.builder == nil && src.bridge == nil,
+			optional:  src.optional,
+			equal:     src.equal,
+			bridge:    src.bridge,
+			buildOnly: src.buildOnly,
+			cached:    src.cached,
+			direct:    src.direct,
+			iface:     src.iface,
+			last:      src.last,
+		}
+		if !e.built {
+			// 只导入注册，实例在当前容器中重新构建
+			if src.bridge != nil || src.direct || s.opts.lazyPlaceholders {
+				e.instance = src.unbuiltInstance()
+			} else {
+				e.instance = reflect.New(reflect.TypeOf(src.instance).Elem()).Interface()
+			}
+		}
+		s.register(name, e)
+		s.groupMu.Lock()
+		e.tags = append([]string(nil), src.tags...)
+		e.priority = src.priority
+		s.groupMu.Unlock()
+		e.providedBy = src.providedBy
+		e.owner, e.description = src.owner, src.description
+		if addr := instanceAddr(e.instance); e.built && addr != 0 {
+			addrNames, _ := s.values.Get(addr)
+			s.values.Set(addr, append(addrNames, name))
+		}
+
+		// 导入已记录的依赖关系，重新构建时以新记录的为准
+		for _, dep := range other.edges.dependsOn(name) {
+			s.edges.add(name, dep)
+		}
+		e.importedEdges = !e.built
+	}
+
+	s.ready = append(s.ready, other.ready...)
+	s.assertions = append(s.assertions, other.assertions...)
+
+	other.resolver = func(name string) (any, error) {
+		return s.GetService(name)
+	}
+	s.built = false
+	s.resetBuilt()
+	return nil
+}
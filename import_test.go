@@ -0,0 +1,105 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDI_Import(t *testing.T) {
+	storage := New[TestContext]()
+	Provide(storage, "database", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "database-" + ctx.Config}
+	})
+	Provide(storage, "repository", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "repository", ServiceA: MustMake[TestContext, ServiceA](storage, "database")}
+	})
+	storage.Tag("repository", "repositories")
+	storage.SetPriority("repository", 10)
+	ProvideValue(storage, "schema", &ServiceA{Name: "schema"})
+	// 单独构建过的模块，导入时带上已记录的依赖关系
+	storage.SetCtx(&TestContext{Config: "storage"})
+	if err := storage.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	storageRepository := MustMake[TestContext, ServiceB](storage, "repository")
+	schema := MustMake[TestContext, ServiceA](storage, "schema")
+
+	web := New[TestContext]()
+	// 依赖另一个模块中的服务
+	Provide(web, "handler", func(ctx *TestContext) *ServiceC {
+		return &ServiceC{Name: "handler", ServiceB: MustMake[TestContext, ServiceB](web, "repository")}
+	})
+
+	app := New[TestContext]()
+	app.SetCtx(&TestContext{Config: "app"})
+	if err := app.Import(storage); err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if err := app.Import(web); err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if deps := app.DirectDependencies("repository"); strings.Join(deps, ",") != "database" {
+		t.Errorf("已记录的依赖关系应该随之导入: %v", deps)
+	}
+	if got := app.Group("repositories", GroupByPriority); strings.Join(got, ",") != "repository" {
+		t.Errorf("标签应该随之导入: %v", got)
+	}
+	if err := app.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	handler := MustMake[TestContext, ServiceC](app, "handler")
+	repository := MustMake[TestContext, ServiceB](app, "repository")
+	if handler.ServiceB != repository {
+		t.Error("跨模块的依赖应该解析到合并后的服务")
+	}
+	if repository.ServiceA.Name != "database-app" {
+		t.Errorf("导入的服务应该在当前容器中重新构建: %s", repository.ServiceA.Name)
+	}
+	if repository == storageRepository {
+		t.Error("不应该导入已构建的实例")
+	}
+	if MustMake[TestContext, ServiceA](app, "schema") != schema {
+		t.Error("ProvideValue 注册的服务应该共享同一实例")
+	}
+	if MustMake[TestContext, ServiceB](storage, "repository") != repository {
+		t.Error("导入后原容器的解析应该转发给当前容器")
+	}
+	if deps := app.DirectDependencies("handler"); strings.Join(deps, ",") != "repository" {
+		t.Errorf("应该在当前容器中记录依赖关系: %v", deps)
+	}
+	if deps := app.DirectDependencies("repository"); strings.Join(deps, ",") != "database" {
+		t.Errorf("重新构建后的依赖关系不应该重复: %v", deps)
+	}
+}
+
+func TestDI_ImportConflict(t *testing.T) {
+	newModule := func(name string) *Weave[TestContext] {
+		di := New[TestContext]()
+		Provide(di, "logger", func(ctx *TestContext) *ServiceA {
+			return &ServiceA{Name: name}
+		})
+		return di
+	}
+
+	app := newModule("app")
+	if err := app.Import(newModule("module")); err == nil || !strings.Contains(err.Error(), "service [logger] already exists") {
+		t.Errorf("名称冲突时应该返回错误: %v", err)
+	}
+	if err := app.Import(app); err == nil {
+		t.Error("不应该导入自身")
+	}
+
+	for policy, want := range map[ImportPolicy]string{ImportKeepExisting: "app", ImportOverwrite: "module"} {
+		app := newModule("app")
+		if err := app.ImportWith(newModule("module"), policy); err != nil {
+			t.Fatalf("导入失败: %v", err)
+		}
+		if err := app.Build(); err != nil {
+			t.Fatalf("构建失败: %v", err)
+		}
+		if got := MustMake[TestContext, ServiceA](app, "logger").Name; got != want {
+			t.Errorf("冲突策略 %d 应该得到 %s，实际为 %s", policy, want, got)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package weave
+
+import "time"
+
+// incrementalBuild 分批构建的进度
+type incrementalBuild struct {
+	// 尚未处理的服务，按名称排序
+	pending []string
+	// WithCollectErrors 时汇总的错误
+	errs map[string]error
+}
+
+// BuildIncremental 在时间预算内尽可能多地构建服务，返回是否已全部构建完成
+// 重复调用会在上次的进度上继续，全部完成后与 Build 一样执行 Ready 回调
+// 预算只在每个服务构建完成后检查，单个服务的构建不会被打断
+func (s *Weave[T]) BuildIncremental(budget time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.startAttempt()
+	done, err := s.runIncremental(budget)
+	if done || err != nil {
+		s.finishAttempt(err)
+	}
+	return done, err
+}
+
+// runIncremental 执行一批构建
+func (s *Weave[T]) runIncremental(budget time.Duration) (bool, error) {
+	if s.built {
+		return true, nil
+	}
+	if s.compacted&compactedCtx != 0 {
+		return false, &CompactError{Op: "build", Released: "context"}
+	}
+
+	deadline := time.Now().Add(budget)
+	if s.incremental == nil {
+		if err := s.prepareBuild(); err != nil {
+			return false, err
+		}
+		names := s.entries.Keys()
+		s.sortBuildWaves(names)
+		s.incremental = &incrementalBuild{pending: names, errs: make(map[string]error)}
+	}
+
+	inc := s.incremental
+	for len(inc.pending) > 0 {
+		name := inc.pending[0]
+		e, ok := s.entries.Get(name)
+		if ok {
+			if err := s.build(name, e); err != nil && !e.optional {
+				if !s.opts.collectErrors {
+					return false, err
+				}
+				inc.errs[name] = err
+			}
+		}
+		inc.pending = inc.pending[1:]
+
+		if len(inc.pending) > 0 && !time.Now().Before(deadline) {
+			return false, nil
+		}
+	}
+
+	// 分批期间新注册的服务
+	for _, name := range s.entries.Keys() {
+		if e, _ := s.entries.Get(name); !e.built && !s.skipped.Contains(name) && !s.disabled.Contains(name) && inc.errs[name] == nil {
+			inc.pending = append(inc.pending, name)
+		}
+	}
+	if len(inc.pending) > 0 {
+		s.sortBuildWaves(inc.pending)
+		return false, nil
+	}
+
+	if err := s.finishBuild(inc.errs); err != nil {
+		return false, err
+	}
+	return true, nil
+}
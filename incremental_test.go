@@ -0,0 +1,99 @@
+package weave
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDI_BuildIncremental(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	built := 0
+	for i := 0; i < 10; i++ {
+		Provide(di, fmt.Sprintf("service%d", i), func(ctx *TestContext) *ServiceA {
+			time.Sleep(5 * time.Millisecond)
+			built++
+			return &ServiceA{Name: "ServiceA"}
+		})
+	}
+	ready := false
+	di.Ready(func() { ready = true })
+
+	calls := 0
+	for {
+		calls++
+		done, err := di.BuildIncremental(12 * time.Millisecond)
+		if err != nil {
+			t.Fatalf("分批构建失败: %v", err)
+		}
+		if done {
+			break
+		}
+		if ready {
+			t.Fatal("未完成前不应该执行 Ready 回调")
+		}
+		if calls > 10 {
+			t.Fatal("分批构建没有进展")
+		}
+	}
+
+	if calls < 2 {
+		t.Errorf("时间预算不足时应该分多次完成，实际调用 %d 次", calls)
+	}
+	if built != 10 {
+		t.Errorf("每个服务应该只构建一次，实际构建 %d 次", built)
+	}
+	if !ready {
+		t.Error("全部完成后应该执行 Ready 回调")
+	}
+	if done, err := di.BuildIncremental(time.Millisecond); !done || err != nil {
+		t.Errorf("已构建的容器应该直接返回完成: %v %v", done, err)
+	}
+}
+
+func TestDI_BuildIncrementalThenBuild(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		a := MustMake[TestContext, ServiceA](di, "serviceA")
+		return &ServiceB{Name: "ServiceB", ServiceA: a}
+	})
+
+	if done, err := di.BuildIncremental(0); done || err != nil {
+		t.Fatalf("零预算时应该只构建一个服务: %v %v", done, err)
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("Build 应该在分批进度上继续: %v", err)
+	}
+	b := MustMake[TestContext, ServiceB](di, "serviceB")
+	if b.ServiceA == nil || b.ServiceA.Name != "ServiceA" {
+		t.Error("依赖注入失败")
+	}
+}
+
+func TestDI_BuildIncrementalError(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	fail := true
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		if fail {
+			return nil
+		}
+		return &ServiceA{Name: "ServiceA"}
+	})
+
+	if _, err := di.BuildIncremental(time.Second); err == nil {
+		t.Fatal("构建失败时应该返回错误")
+	}
+	fail = false
+	if done, err := di.BuildIncremental(time.Second); !done || err != nil {
+		t.Errorf("失败的服务应该在下次调用时重试: %v %v", done, err)
+	}
+}
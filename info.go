@@ -0,0 +1,103 @@
+package weave
+
+import "sort"
+
+// ServiceInfo 服务的运行时信息
+type ServiceInfo struct {
+	// Name 服务名称
+	Name string
+	// Built 是否已构建
+	Built bool
+	// DependsOn 构建时记录的依赖
+	DependsOn []string
+	// SharedWith 与该服务共享同一实例指针的其他服务
+	SharedWith []string
+	// InstanceID 当前实例指针的标识，见 Weave.InstanceID；尚未分配实例时为 0
+	InstanceID InstanceID
+	// ProvidedBy 注册该服务的包路径
+	ProvidedBy string
+	// Warm 预热状态，未预热时为 nil
+	Warm *WarmState
+	// CtxGeneration 构建时的上下文代数，见 CtxGeneration；未通过 builder 构建时为 0
+	CtxGeneration uint64
+}
+
+// Info 获取服务的运行时信息
+func (s *Weave[T]) Info(name string) (*ServiceInfo, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries.Get(name)
+	if !ok {
+		return nil, false
+	}
+
+	info := &ServiceInfo{
+		Name:       name,
+		Built:      entry.built,
+		DependsOn:  s.edges.dependsOn(name),
+		SharedWith: s.sharedWith(name, entry.instance),
+		ProvidedBy: entry.providedBy,
+
+		CtxGeneration: entry.ctxGen,
+	}
+	info.InstanceID, _ = instanceID(entry.instance)
+	if state, ok := s.warmStates.Get(name); ok {
+		info.Warm = &state
+	}
+	return info, true
+}
+
+// DirectDependencies 返回服务直接依赖的服务，已排序
+// 只读取该服务的邻接表，无需扫描边表或生成完整的依赖图谱
+func (s *Weave[T]) DirectDependencies(name string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deps := uniqueStrings(s.edges.dependsOn(name))
+	sort.Strings(deps)
+	return deps
+}
+
+// DirectDependents 返回直接依赖该服务的服务，已排序
+func (s *Weave[T]) DirectDependents(name string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ds := uniqueStrings(s.edges.dependents(name))
+	sort.Strings(ds)
+	return ds
+}
+
+// BuiltServices 返回当前已构建的服务，已排序
+func (s *Weave[T]) BuiltServices() []string {
+	return s.servicesByBuilt(true)
+}
+
+// UnbuiltServices 返回当前尚未构建的服务，已排序
+func (s *Weave[T]) UnbuiltServices() []string {
+	return s.servicesByBuilt(false)
+}
+
+func (s *Weave[T]) servicesByBuilt(built bool) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := []string{}
+	s.entries.Range(func(name string, e *entry[*T]) bool {
+		if e.built == built {
+			names = append(names, name)
+		}
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+// MaxResolutionDepth 返回最近一次构建中 builder 嵌套解析依赖达到的最大深度
+// 顶层服务的深度为 1，每在 builder 中构建一个尚未构建的依赖加 1；没有构建任何服务时为 0
+func (s *Weave[T]) MaxResolutionDepth() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.maxDepth
+}
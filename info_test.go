@@ -0,0 +1,83 @@
+package weave
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDI_DirectDependents(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+		return &ServiceC{
+			Name:     "ServiceC",
+			ServiceA: MustMake[TestContext, ServiceA](di, "serviceA"),
+			ServiceB: MustMake[TestContext, ServiceB](di, "serviceB"),
+		}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	graph := di.GetDependencyGraph()
+	for _, name := range []string{"serviceA", "serviceB", "serviceC"} {
+		if got, want := strings.Join(di.DirectDependents(name), ","), strings.Join(graph.Dependents[name], ","); got != want {
+			t.Errorf("%s 的被依赖列表应为 %s，实际为 %s", name, want, got)
+		}
+		if got, want := strings.Join(di.DirectDependencies(name), ","), strings.Join(graph.Dependencies[name], ","); got != want {
+			t.Errorf("%s 的依赖列表应为 %s，实际为 %s", name, want, got)
+		}
+	}
+	if ds := di.DirectDependents("unknown"); len(ds) != 0 {
+		t.Errorf("未知服务不应有被依赖列表: %v", ds)
+	}
+
+	// 重新注册后旧的边应该从两侧的邻接表中移除
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if got := strings.Join(di.DirectDependents("serviceA"), ","); got != "serviceC" {
+		t.Errorf("重新注册后 serviceA 的被依赖列表应为 serviceC，实际为 %s", got)
+	}
+	if deps := di.DirectDependencies("serviceB"); len(deps) != 0 {
+		t.Errorf("重新注册后 serviceB 不应有依赖: %v", deps)
+	}
+}
+
+func TestDI_MaxResolutionDepth(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	// chain0 -> chain1 -> ... -> chain9
+	for i := 0; i < 10; i++ {
+		name, next := fmt.Sprintf("chain%d", i), fmt.Sprintf("chain%d", i+1)
+		last := i == 9
+		Provide(di, name, func(ctx *TestContext) *ServiceA {
+			if !last {
+				MustMake[TestContext, ServiceA](di, next)
+			}
+			return &ServiceA{Name: name}
+		})
+	}
+	if di.MaxResolutionDepth() != 0 {
+		t.Error("构建前深度应该为 0")
+	}
+	if done, err := di.BuildIncremental(time.Hour); !done || err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if got := di.MaxResolutionDepth(); got != 10 {
+		t.Errorf("最大解析深度应该为 10，实际为 %d", got)
+	}
+}
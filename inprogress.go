@@ -0,0 +1,32 @@
+package weave
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrResolutionInProgress 解析的服务正在构建中（循环依赖），仅在 WithCycleErrors 时返回
+type ErrResolutionInProgress struct {
+	// Name 正在构建的服务
+	Name string
+	// Path 从最外层到发起解析的服务的构建栈，末尾为 Name
+	Path []string
+}
+
+func (e *ErrResolutionInProgress) Error() string {
+	return fmt.Sprintf("service [%s] is being constructed (%s)", e.Name, strings.Join(e.Path, " -> "))
+}
+
+// inProgress 根据当前构建栈生成循环解析错误
+func (s *Weave[T]) inProgress(name string) error {
+	path := make([]string, 0, len(s.resolving)+1)
+	path = append(path, s.resolving...)
+	path = append(path, name)
+	return &ErrResolutionInProgress{Name: name, Path: path}
+}
+
+// ResolutionDepth 返回当前嵌套构建的深度，没有服务在构建时为 0
+// 仅应在 builder 内调用
+func (s *Weave[T]) ResolutionDepth() int {
+	return len(s.resolving)
+}
@@ -0,0 +1,68 @@
+package weave
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDI_CycleErrors(t *testing.T) {
+	di := New[TestContext](WithCycleErrors())
+	di.SetCtx(&TestContext{Config: "test"})
+
+	var errs []*ErrResolutionInProgress
+	maxDepth := 0
+	resolve := func(name string) {
+		if d := di.ResolutionDepth(); d > maxDepth {
+			maxDepth = d
+		}
+		var inProgress *ErrResolutionInProgress
+		if _, err := di.GetService(name); errors.As(err, &inProgress) {
+			// builder 自行决定不持有正在构建的服务
+			errs = append(errs, inProgress)
+		} else if err != nil {
+			t.Errorf("获取服务失败: %v", err)
+		}
+	}
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		resolve("serviceB")
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		resolve("serviceA")
+		return &ServiceB{Name: "ServiceB"}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("应该返回 1 个循环解析错误，实际为 %v", errs)
+	}
+	e := errs[0]
+	if len(e.Path) != 3 || e.Path[0] != e.Name || e.Path[2] != e.Name {
+		t.Errorf("构建栈错误: %v", e.Path)
+	}
+	if maxDepth != 2 {
+		t.Errorf("最大嵌套构建深度应为 2，实际为 %d", maxDepth)
+	}
+	if d := di.ResolutionDepth(); d != 0 {
+		t.Errorf("构建结束后深度应为 0，实际为 %d", d)
+	}
+}
+
+func TestDI_CycleErrorsDisabled(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceB](di, "serviceB")
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+	if err := di.Build(); err != nil {
+		t.Errorf("默认情况下循环依赖应该返回占位符: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package weave
+
+import (
+	"fmt"
+	"sort"
+)
+
+// InstanceID 服务实例指针的标识，两个服务的 InstanceID 相同说明它们是同一个对象
+// 仅在当前进程内有意义，不应持久化
+type InstanceID uintptr
+
+func (id InstanceID) String() string {
+	return fmt.Sprintf("0x%x", uintptr(id))
+}
+
+// instanceID 返回实例指针的标识，实例不是指针、为 nil 或指向零大小类型时返回 false
+// 零大小类型的不同实例可能共用同一地址，地址无法说明它们是同一个对象
+func instanceID(instance any) (InstanceID, bool) {
+	addr := instanceAddr(instance)
+	if addr == 0 {
+		return 0, false
+	}
+	return InstanceID(addr), true
+}
+
+// InstanceID 返回服务当前实例指针的标识，旧名称按重命名转发
+// 服务不存在、延迟分配的占位符尚未分配、仅构建期服务已释放或实例为零大小类型时返回 false
+func (s *Weave[T]) InstanceID(name string) (InstanceID, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.entries.Get(name)
+	if !ok {
+		newName, renamed := s.renames.Get(name)
+		if !renamed {
+			return 0, false
+		}
+		if e, ok = s.entries.Get(newName); !ok {
+			return 0, false
+		}
+	}
+	return instanceID(e.instance)
+}
+
+// InstanceID 返回键对应的实例指针的标识，与提取时 Weave.InstanceID 的结果一致
+// 键不存在、值不是非 nil 指针或指向零大小类型时返回 false
+func (m *Map[K, V]) InstanceID(key K) (InstanceID, bool) {
+	value, ok := m.Get(key)
+	if !ok {
+		return 0, false
+	}
+	return instanceID(value)
+}
+
+// SharedInstance 被多个名称引用的同一个实例
+type SharedInstance struct {
+	ID InstanceID
+	// Services 共享该实例的服务，如通过 ProvideValue 以多个名称注册，已排序
+	Services []string
+	// Renamed 仍转发到这些服务的旧名称，已排序
+	Renamed []string
+}
+
+// SharedInstances 返回被多个名称引用的实例，用于排查“修改一处、另一处也变了”的问题
+// 包括以多个名称注册的同一实例指针，以及通过 Rename 留下的旧名称；按首个服务名称排序
+// 零大小类型的实例没有可区分的地址，不出现在报告中
+func (s *Weave[T]) SharedInstances() []SharedInstance {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	groups := make(map[InstanceID]*SharedInstance)
+	s.entries.Range(func(name string, e *entry[*T]) bool {
+		id, ok := instanceID(e.instance)
+		if !ok {
+			return true
+		}
+		g, exists := groups[id]
+		if !exists {
+			g = &SharedInstance{ID: id, Services: []string{}, Renamed: []string{}}
+			groups[id] = g
+		}
+		g.Services = append(g.Services, name)
+		return true
+	})
+	s.renames.Range(func(from, to string) bool {
+		if e, ok := s.entries.Get(to); ok {
+			if id, ok := instanceID(e.instance); ok {
+				groups[id].Renamed = append(groups[id].Renamed, from)
+			}
+		}
+		return true
+	})
+
+	result := []SharedInstance{}
+	for _, g := range groups {
+		if len(g.Services) < 2 && len(g.Renamed) == 0 {
+			continue
+		}
+		sort.Strings(g.Services)
+		sort.Strings(g.Renamed)
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Services[0] < result[j].Services[0]
+	})
+	return result
+}
@@ -0,0 +1,98 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDI_InstanceID(t *testing.T) {
+	di := New[TestContext](WithAliasPolicy(AliasRecord))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	shared := &ServiceA{Name: "shared"}
+	ProvideInstance(di, "primary", shared)
+	ProvideInstance(di, "replica", shared)
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "B", ServiceA: MustMake[TestContext, ServiceA](di, "primary")}
+	})
+	Provide(di, "legacyCache", func(ctx *TestContext) *ServiceC {
+		return &ServiceC{Name: "cache"}
+	})
+	if err := di.Rename("legacyCache", "cache"); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	primary, ok := di.InstanceID("primary")
+	if !ok || uintptr(primary) != reflect.ValueOf(shared).Pointer() {
+		t.Fatalf("应该返回实例标识: %v %v", primary, ok)
+	}
+	if replica, _ := di.InstanceID("replica"); replica != primary {
+		t.Error("共享同一实例的服务标识应该相同")
+	}
+	if b, _ := di.InstanceID("serviceB"); b == primary {
+		t.Error("不同实例的标识应该不同")
+	}
+	if old, _ := di.InstanceID("legacyCache"); old == 0 {
+		t.Error("旧名称应该转发到新名称的实例")
+	}
+	if _, ok := di.InstanceID("missing"); ok {
+		t.Error("不存在的服务不应该返回标识")
+	}
+	if info, _ := di.Info("primary"); info.InstanceID != primary {
+		t.Errorf("Info 中的标识错误: %v", info.InstanceID)
+	}
+	if id, ok := di.Extract().InstanceID("primary"); !ok || id != primary {
+		t.Errorf("注册表中的标识应该与容器一致: %v", id)
+	}
+	if !strings.HasPrefix(primary.String(), "0x") {
+		t.Errorf("标识格式错误: %s", primary)
+	}
+
+	report := di.SharedInstances()
+	if len(report) != 2 {
+		t.Fatalf("共享实例报告错误: %+v", report)
+	}
+	if !equalSlices(report[0].Services, []string{"cache"}) || !equalSlices(report[0].Renamed, []string{"legacyCache"}) {
+		t.Errorf("重命名转发报告错误: %+v", report[0])
+	}
+	if report[1].ID != primary || !equalSlices(report[1].Services, []string{"primary", "replica"}) {
+		t.Errorf("共享指针报告错误: %+v", report[1])
+	}
+
+	dot := di.GenerateDOTGraph(DOTInstanceIDs())
+	if !strings.Contains(dot, fmt.Sprintf("tooltip=\"instance %s\"", primary)) {
+		t.Errorf("DOT 图应该在 tooltip 中显示实例标识:\n%s", dot)
+	}
+	if strings.Contains(di.GenerateDOTGraph(), "tooltip") {
+		t.Error("默认不应该显示实例标识")
+	}
+}
+
+func TestDI_InstanceIDZeroSize(t *testing.T) {
+	type emptyA struct{}
+	type emptyB struct{}
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "a", func(ctx *TestContext) *emptyA { return &emptyA{} })
+	Provide(di, "b", func(ctx *TestContext) *emptyB { return &emptyB{} })
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	// 零大小类型的不同实例可能共用同一地址，不应该被报告为同一个对象
+	if _, ok := di.InstanceID("a"); ok {
+		t.Error("零大小类型的实例不应该有标识")
+	}
+	if report := di.SharedInstances(); len(report) != 0 {
+		t.Errorf("不同的零大小实例不应该被报告为共享: %+v", report)
+	}
+	if strings.Contains(di.GenerateDOTGraph(DOTInstanceIDs()), "tooltip") {
+		t.Error("零大小类型的实例不应该显示标识")
+	}
+}
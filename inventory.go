@@ -0,0 +1,116 @@
+package weave
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// InventorySchemaVersion 服务清单的结构版本，JSON 结构发生不兼容变化时递增
+const InventorySchemaVersion = 1
+
+// Inventory 供外部服务目录导入的服务清单
+type Inventory struct {
+	SchemaVersion int                `json:"schemaVersion"`
+	Services      []InventoryService `json:"services"`
+}
+
+// InventoryService 服务清单中的单个服务
+type InventoryService struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Owner        string   `json:"owner"`
+	Description  string   `json:"description"`
+	Tags         []string `json:"tags"`
+	Dependencies []string `json:"dependencies"`
+	// EntryPoint 没有被其他服务依赖的服务
+	EntryPoint bool `json:"entryPoint"`
+}
+
+// Describe 设置服务的负责人与描述，用于 ExportInventory
+func (s *Weave[T]) Describe(name, owner, description string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries.Get(name)
+	if !ok {
+		return s.notFound(name)
+	}
+	e.owner = owner
+	e.description = description
+	return nil
+}
+
+// Inventory 生成服务清单，服务按名称排序
+func (s *Weave[T]) Inventory() *Inventory {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := s.entries.Keys()
+	sort.Strings(names)
+	dependencies, dependents := s.edges.materialize(names)
+
+	inventory := &Inventory{SchemaVersion: InventorySchemaVersion, Services: []InventoryService{}}
+	for _, name := range names {
+		e, _ := s.entries.Get(name)
+		tags := append([]string{}, e.tags...)
+		sort.Strings(tags)
+		inventory.Services = append(inventory.Services, InventoryService{
+			Name:         name,
+			Type:         e.instanceType().String(),
+			Owner:        e.owner,
+			Description:  e.description,
+			Tags:         tags,
+			Dependencies: uniqueStrings(append([]string{}, dependencies[name]...)),
+			EntryPoint:   len(dependents[name]) == 0,
+		})
+	}
+	return inventory
+}
+
+// ExportInventory 以 JSON 格式输出服务清单
+func (s *Weave[T]) ExportInventory(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s.Inventory())
+}
+
+// ReadInventory 读取 ExportInventory 输出的服务清单，清单的格式版本高于当前版本时返回错误
+func ReadInventory(r io.Reader) (*Inventory, error) {
+	inventory := &Inventory{}
+	if err := json.NewDecoder(r).Decode(inventory); err != nil {
+		return nil, err
+	}
+	if inventory.SchemaVersion > InventorySchemaVersion {
+		return nil, fmt.Errorf("inventory schema version %d is newer than supported version %d", inventory.SchemaVersion, InventorySchemaVersion)
+	}
+	return inventory, nil
+}
+
+// Graph 根据清单中的依赖关系生成依赖图谱，清单中没有的依赖按外部服务处理
+func (inv *Inventory) Graph() *DependencyGraph {
+	graph := &DependencyGraph{
+		Dependencies: make(map[string][]string, len(inv.Services)),
+		Dependents:   make(map[string][]string),
+		Renamed:      make(map[string][]string),
+		External:     make(map[string]bool),
+		Bridged:      make(map[string]string),
+		Exported:     make(map[string][]string),
+		BuildOnly:    make(map[string]bool),
+		Optional:     make(map[string]map[string]bool),
+		ProvidedBy:   make(map[string]string),
+	}
+	for _, service := range inv.Services {
+		graph.Dependencies[service.Name] = append([]string{}, service.Dependencies...)
+	}
+	for _, service := range inv.Services {
+		for _, dep := range service.Dependencies {
+			graph.Dependents[dep] = append(graph.Dependents[dep], service.Name)
+			if _, ok := graph.Dependencies[dep]; !ok {
+				graph.External[dep] = true
+			}
+		}
+	}
+	return graph
+}
@@ -0,0 +1,113 @@
+package weave
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestDI_ExportInventory(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	provideGraph(di, "config", "cache: config", "database: config pool", "pool: database", "app: database cache")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if err := di.Describe("database", "storage-team", "主数据库连接"); err != nil {
+		t.Fatalf("Describe 失败: %v", err)
+	}
+	_ = di.Tag("database", "infra", "db")
+
+	var buf bytes.Buffer
+	if err := di.ExportInventory(&buf); err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+
+	var inventory Inventory
+	if err := json.Unmarshal(buf.Bytes(), &inventory); err != nil {
+		t.Fatalf("解析清单失败: %v", err)
+	}
+	if !reflect.DeepEqual(&inventory, di.Inventory()) {
+		t.Errorf("清单往返后应该保持一致:\n%s", buf.String())
+	}
+	if inventory.SchemaVersion != InventorySchemaVersion {
+		t.Errorf("schemaVersion 错误: %d", inventory.SchemaVersion)
+	}
+
+	services := make(map[string]InventoryService)
+	for _, svc := range inventory.Services {
+		services[svc.Name] = svc
+	}
+	database := services["database"]
+	want := InventoryService{
+		Name:         "database",
+		Type:         "*weave.ServiceA",
+		Owner:        "storage-team",
+		Description:  "主数据库连接",
+		Tags:         []string{"db", "infra"},
+		Dependencies: []string{"config", "pool"},
+	}
+	if !reflect.DeepEqual(database, want) {
+		t.Errorf("database 的清单信息错误: %+v", database)
+	}
+	if !services["app"].EntryPoint || services["config"].EntryPoint {
+		t.Error("只有没有被依赖的服务才是入口")
+	}
+
+	// 输出应该是确定的
+	var again bytes.Buffer
+	_ = di.ExportInventory(&again)
+	if again.String() != buf.String() {
+		t.Error("多次导出的结果应该一致")
+	}
+}
+
+// 清单的 JSON 结构被外部系统依赖，字段变化时需要同步递增 InventorySchemaVersion
+func TestDI_InventorySchema(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	provideGraph(di, "config", "cache: config", "database: config pool", "pool: database", "app: database cache")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := di.ExportInventory(&buf); err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("解析清单失败: %v", err)
+	}
+	if got := sortedKeys(doc); !reflect.DeepEqual(got, []string{"schemaVersion", "services"}) {
+		t.Errorf("顶层字段发生变化: %v", got)
+	}
+	if string(doc["schemaVersion"]) != "1" {
+		t.Errorf("schemaVersion 应该为 1，实际为 %s", doc["schemaVersion"])
+	}
+
+	var services []map[string]json.RawMessage
+	if err := json.Unmarshal(doc["services"], &services); err != nil {
+		t.Fatalf("解析服务列表失败: %v", err)
+	}
+	want := []string{"dependencies", "description", "entryPoint", "name", "owner", "tags", "type"}
+	for _, svc := range services {
+		if got := sortedKeys(svc); !reflect.DeepEqual(got, want) {
+			t.Errorf("服务字段发生变化: %v", got)
+		}
+		if string(svc["tags"]) == "null" || string(svc["dependencies"]) == "null" {
+			t.Errorf("列表字段不应该为 null: %s", svc["name"])
+		}
+	}
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,169 @@
+package weave
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity 检查结果的严重程度
+type Severity int
+
+const (
+	// SeverityInfo 仅供参考
+	SeverityInfo Severity = iota
+	// SeverityWarning 值得关注，不影响构建
+	SeverityWarning
+	// SeverityError 开启 WithGraphLint 时导致构建失败
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// LintOptions 依赖图谱检查的阈值，为 0 的阈值不检查
+type LintOptions struct {
+	// MaxFanIn 被依赖的服务数上限
+	MaxFanIn int
+	// MaxFanOut 依赖的服务数上限
+	MaxFanOut int
+	// MaxDepth 依赖链深度上限，即服务在 Levels 中所在的层级
+	MaxDepth int
+	// Strict 超过阈值时按 SeverityError 报告，默认为 SeverityWarning
+	Strict bool
+}
+
+// LintFinding 依赖图谱检查发现的问题
+type LintFinding struct {
+	Severity Severity
+	Service  string
+	// Rule 检查项：fan-in、fan-out、depth、cycle 或 orphan
+	Rule    string
+	Message string
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", f.Severity, f.Rule, f.Service, f.Message)
+}
+
+// LintError 开启 WithGraphLint 时构建发现了 SeverityError 级别的问题
+type LintError struct {
+	Findings []LintFinding
+}
+
+func (e *LintError) Error() string {
+	lines := make([]string, len(e.Findings))
+	for i, f := range e.Findings {
+		lines[i] = f.String()
+	}
+	return fmt.Sprintf("dependency graph lint failed:\n%s", strings.Join(lines, "\n"))
+}
+
+// WithGraphLint 每次构建成功后按 opts 检查依赖图谱，存在 SeverityError 级别的问题时返回 *LintError
+func WithGraphLint(opts LintOptions) Option {
+	return func(o *options) {
+		o.lint = &opts
+	}
+}
+
+// LintGraph 检查依赖图谱，按严重程度从高到低、服务名称排序返回发现的问题：
+// 扇入、扇出与依赖链深度超过阈值，未通过 AllowCycle 允许的循环依赖（error，已允许的为 info），
+// 以及既无依赖也不被依赖的孤立服务（info）；外部服务不参与检查
+func (s *Weave[T]) LintGraph(opts LintOptions) []LintFinding {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lintGraph(opts)
+}
+
+// lintGraph 检查依赖图谱，调用方需持有锁
+func (s *Weave[T]) lintGraph(opts LintOptions) []LintFinding {
+	graph := s.dependencyGraph()
+	threshold := SeverityWarning
+	if opts.Strict {
+		threshold = SeverityError
+	}
+
+	findings := []LintFinding{}
+	add := func(severity Severity, service, rule, format string, args ...any) {
+		findings = append(findings, LintFinding{Severity: severity, Service: service, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for service := range graph.Dependencies {
+		if graph.External[service] {
+			continue
+		}
+		fanOut := len(uniqueStrings(graph.Dependencies[service]))
+		fanIn := len(uniqueStrings(graph.Dependents[service]))
+		if opts.MaxFanOut > 0 && fanOut > opts.MaxFanOut {
+			add(threshold, service, "fan-out", "depends on %d services (max %d)", fanOut, opts.MaxFanOut)
+		}
+		if opts.MaxFanIn > 0 && fanIn > opts.MaxFanIn {
+			add(threshold, service, "fan-in", "is depended on by %d services (max %d)", fanIn, opts.MaxFanIn)
+		}
+		if graph.Category(service) == CategoryIsolated {
+			add(SeverityInfo, service, "orphan", "has no dependencies and no dependents")
+		}
+	}
+
+	if opts.MaxDepth > 0 {
+		for depth, services := range dependencyLevels(graph.Dependencies) {
+			if depth <= opts.MaxDepth {
+				continue
+			}
+			for _, service := range services {
+				add(threshold, service, "depth", "dependency chain depth %d (max %d)", depth, opts.MaxDepth)
+			}
+		}
+	}
+
+	for _, r := range s.cycleReports(graph) {
+		path := strings.Join(append(append([]string{}, r.Services...), r.Services[0]), " -> ")
+		if s.allowedCycles.Contains(r.Fingerprint) {
+			add(SeverityInfo, r.Services[0], "cycle", "allowed cycle %s", path)
+			continue
+		}
+		add(SeverityError, r.Services[0], "cycle", "circular dependency %s", path)
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Message < b.Message
+	})
+	return findings
+}
+
+// checkLint 开启 WithGraphLint 时检查依赖图谱
+func (s *Weave[T]) checkLint() error {
+	if s.opts.lint == nil {
+		return nil
+	}
+	var errs []LintFinding
+	for _, f := range s.lintGraph(*s.opts.lint) {
+		if f.Severity == SeverityError {
+			errs = append(errs, f)
+		}
+	}
+	if len(errs) > 0 {
+		return &LintError{Findings: errs}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package weave
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDI_LintGraph(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	// hub 被 a、b、c 依赖，deep 依赖 a、b、c，x 与 y 循环依赖，lonely 孤立
+	provideGraph(di, "hub", "a: hub", "b: hub", "c: hub", "deep: a b c", "x: y", "y: x", "lonely")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	findings := di.LintGraph(LintOptions{MaxFanIn: 2, MaxFanOut: 2, MaxDepth: 1})
+	got := make([]string, len(findings))
+	for i, f := range findings {
+		got[i] = f.Severity.String() + " " + f.Rule + " " + f.Service
+	}
+	want := []string{
+		"error cycle x",
+		"warning depth deep",
+		"warning fan-out deep",
+		"warning fan-in hub",
+		"info orphan lonely",
+	}
+	if !equalSlices(got, want) {
+		t.Errorf("检查结果错误:\n%v\n期望:\n%v", got, want)
+	}
+	if findings[0].Message != "circular dependency x -> y -> x" {
+		t.Errorf("循环依赖的描述错误: %s", findings[0].Message)
+	}
+
+	// 已允许的循环降为 info，Strict 将超过阈值的问题升为 error
+	di.AllowCycle(CycleFingerprint([]string{"x", "y"}))
+	for _, f := range di.LintGraph(LintOptions{MaxFanIn: 2, Strict: true}) {
+		if f.Rule == "cycle" && f.Severity != SeverityInfo {
+			t.Errorf("已允许的循环应该为 info: %v", f)
+		}
+		if f.Rule == "fan-in" && f.Severity != SeverityError {
+			t.Errorf("Strict 时超过阈值应该为 error: %v", f)
+		}
+	}
+	if findings := di.LintGraph(LintOptions{}); len(findings) != 2 {
+		t.Errorf("未设置阈值时只应该报告循环与孤立服务: %v", findings)
+	}
+}
+
+func TestDI_WithGraphLint(t *testing.T) {
+	di := New[TestContext](WithGraphLint(LintOptions{MaxFanIn: 5}))
+	di.SetCtx(&TestContext{Config: "test"})
+	// hub 被 a、b、c 依赖，deep 依赖 a、b、c，x 与 y 循环依赖，lonely 孤立
+	provideGraph(di, "hub", "a: hub", "b: hub", "c: hub", "deep: a b c", "x: y", "y: x", "lonely")
+	var lintErr *LintError
+	if err := di.Build(); !errors.As(err, &lintErr) {
+		t.Fatalf("存在 error 级别的问题时构建应该失败，实际为: %v", err)
+	}
+	if len(lintErr.Findings) != 1 || lintErr.Findings[0].Rule != "cycle" {
+		t.Errorf("只应该报告 error 级别的问题: %v", lintErr.Findings)
+	}
+
+	di = New[TestContext](WithGraphLint(LintOptions{MaxFanIn: 5}))
+	di.SetCtx(&TestContext{Config: "test"})
+	// hub 被 a、b、c 依赖，deep 依赖 a、b、c，x 与 y 循环依赖，lonely 孤立
+	provideGraph(di, "hub", "a: hub", "b: hub", "c: hub", "deep: a b c", "x: y", "y: x", "lonely")
+	di.AllowCycle(CycleFingerprint([]string{"x", "y"}))
+	if err := di.Build(); err != nil {
+		t.Errorf("没有 error 级别的问题时构建应该成功: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package weave
+
+import "fmt"
+
+// ErrLiveMutation 服务的实例已通过 Extract 交给使用方，原地修改需要 WithAllowLiveMutation
+type ErrLiveMutation struct {
+	Name string
+	// Op 被拒绝的操作
+	Op string
+}
+
+func (e *ErrLiveMutation) Error() string {
+	return fmt.Sprintf("service [%s] has been extracted, %s would mutate the shared instance (use WithAllowLiveMutation)", e.Name, e.Op)
+}
+
+// Generation 返回容器的代数，每次 Build 完成以及 Swap、Replace、ReplaceBuilder 改变服务实例后递增
+// 与注册表的 Map.Generation 比较即可判断 Extract 得到的注册表是否已过时
+func (s *Weave[T]) Generation() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.generation
+}
+
+// checkLiveMutation 未开启 WithAllowLiveMutation 时拒绝原地修改已被提取的服务
+func (s *Weave[T]) checkLiveMutation(op string, names []string) error {
+	if s.opts.allowLiveMutation {
+		return nil
+	}
+	for _, name := range names {
+		if e, ok := s.entries.Get(name); ok && e.extracted {
+			return &ErrLiveMutation{Name: name, Op: op}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package weave
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDI_ExtractProtectsLiveInstances(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "db", func(ctx *TestContext) *ServiceA { return &ServiceA{Name: "db"} })
+	Provide(di, "api", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "api(" + MustMake[TestContext, ServiceA](di, "db").Name + ")"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	registry := di.Extract()
+	if registry.Generation() != di.Generation() {
+		t.Fatalf("刚提取的注册表不应过时: %d != %d", registry.Generation(), di.Generation())
+	}
+	db := MustGetFromRegistry[ServiceA](registry, "db")
+
+	var live *ErrLiveMutation
+	if _, err := di.Swap("db", &ServiceA{Name: "db2"}); !errors.As(err, &live) || live.Name != "db" {
+		t.Errorf("替换已提取的服务应该返回 *ErrLiveMutation，实际为 %v", err)
+	}
+	err := di.ReplaceBuilder("db", func(ctx *TestContext) any { return &ServiceA{Name: "db3"} }, true)
+	if !errors.As(err, &live) {
+		t.Errorf("重新构建已提取的服务应该返回 *ErrLiveMutation，实际为 %v", err)
+	}
+	if db.Name != "db" || MustGetFromRegistry[ServiceA](registry, "api").Name != "api(db)" {
+		t.Errorf("使用方持有的实例不应被修改: %s", db.Name)
+	}
+	if registry.Generation() != di.Generation() {
+		t.Error("被拒绝的操作不应改变代数")
+	}
+
+	// 之后注册并构建的服务不受限制，但注册表已过时
+	Provide(di, "cache", func(ctx *TestContext) *ServiceA { return &ServiceA{Name: "cache"} })
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if registry.Generation() == di.Generation() {
+		t.Error("重新构建后注册表应该过时")
+	}
+	if _, err := di.Swap("cache", &ServiceA{Name: "cache2"}); err != nil {
+		t.Errorf("未提取的服务应该可以替换: %v", err)
+	}
+}
+
+func TestDI_AllowLiveMutation(t *testing.T) {
+	di := New[TestContext](WithAllowLiveMutation())
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "db", func(ctx *TestContext) *ServiceA { return &ServiceA{Name: "db"} })
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	registry := di.Extract()
+	db := MustGetFromRegistry[ServiceA](registry, "db")
+	if _, err := di.Swap("db", &ServiceA{Name: "db2"}); err != nil {
+		t.Fatalf("开启 WithAllowLiveMutation 后应该可以替换: %v", err)
+	}
+	if db.Name != "db2" {
+		t.Errorf("使用方应该看到新值: %s", db.Name)
+	}
+	if registry.Generation() == di.Generation() {
+		t.Error("替换后注册表应该过时")
+	}
+
+	gen := di.Generation()
+	if err := di.ReplaceBuilder("db", func(ctx *TestContext) any { return &ServiceA{Name: "db3"} }, false); err != nil {
+		t.Fatalf("开启 WithAllowLiveMutation 后应该可以重新构建: %v", err)
+	}
+	if db.Name != "db3" || di.Generation() != gen+1 {
+		t.Errorf("重新构建后应该看到新值并递增代数: %s %d", db.Name, di.Generation())
+	}
+}
@@ -1,10 +1,18 @@
 package weave
 
-import "sync"
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
 
 type Map[K comparable, V any] struct {
 	mu   sync.RWMutex
 	data map[K]V
+	// 冻结后只读，读取不再加锁，见 Freeze
+	frozen uint32
+	// Extract 时容器的代数，见 Generation
+	generation uint64
 }
 
 func NewMap[K comparable, V any]() *Map[K, V] {
@@ -13,13 +21,44 @@ func NewMap[K comparable, V any]() *Map[K, V] {
 	}
 }
 
+// Freeze 冻结 Map，之后的读取不再加锁，修改会 panic
+// 适合 Extract 得到的注册表在热路径中反复读取的场景
+func (m *Map[K, V]) Freeze() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	atomic.StoreUint32(&m.frozen, 1)
+}
+
+// Frozen 返回 Map 是否已冻结
+func (m *Map[K, V]) Frozen() bool {
+	return atomic.LoadUint32(&m.frozen) == 1
+}
+
+// mustMutable 修改已冻结的 Map 时 panic，调用方需持有写锁
+func (m *Map[K, V]) mustMutable() {
+	if m.frozen == 1 {
+		panic(fmt.Errorf("map is frozen"))
+	}
+}
+
+// Generation 返回通过 Extract 得到的注册表在提取时容器的代数，其他 Map 为 0
+// 与 Weave.Generation 不同时说明容器在提取后重新构建或替换过服务，注册表已过时
+func (m *Map[K, V]) Generation() uint64 {
+	return m.generation
+}
+
 func (m *Map[K, V]) Set(key K, value V) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.mustMutable()
 	m.data[key] = value
 }
 
 func (m *Map[K, V]) Get(key K) (V, bool) {
+	if m.Frozen() {
+		value, ok := m.data[key]
+		return value, ok
+	}
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	value, ok := m.data[key]
@@ -29,9 +68,32 @@ func (m *Map[K, V]) Get(key K) (V, bool) {
 func (m *Map[K, V]) Delete(key K) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.mustMutable()
 	delete(m.data, key)
 }
 
+// Pop 获取并删除键对应的值
+func (m *Map[K, V]) Pop(key K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mustMutable()
+	value, ok := m.data[key]
+	if ok {
+		delete(m.data, key)
+	}
+	return value, ok
+}
+
+// Swap 设置键对应的值，返回原来的值及其是否存在
+func (m *Map[K, V]) Swap(key K, value V) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mustMutable()
+	previous, loaded := m.data[key]
+	m.data[key] = value
+	return previous, loaded
+}
+
 func (m *Map[K, V]) Range(f func(key K, value V) bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -48,9 +110,23 @@ func (m *Map[K, V]) Len() int {
 	return len(m.data)
 }
 
+// CountFunc 返回满足 pred 的键值对数量，只持有一次读锁且不复制键值
+func (m *Map[K, V]) CountFunc(pred func(key K, value V) bool) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	count := 0
+	for key, value := range m.data {
+		if pred(key, value) {
+			count++
+		}
+	}
+	return count
+}
+
 func (m *Map[K, V]) Clear() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.mustMutable()
 	m.data = make(map[K]V)
 }
 
@@ -75,6 +151,10 @@ func (m *Map[K, V]) Values() []V {
 }
 
 func (m *Map[K, V]) Contains(key K) bool {
+	if m.Frozen() {
+		_, ok := m.data[key]
+		return ok
+	}
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	_, ok := m.data[key]
@@ -96,3 +176,29 @@ func (m *Map[K, V]) ToMap() map[K]V {
 	}
 	return copied
 }
+
+// Merge 将 other 中的所有键值复制到 m 中，返回被覆盖的键
+func (m *Map[K, V]) Merge(other *Map[K, V]) []K {
+	entries := other.ToMap()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mustMutable()
+	overwritten := []K{}
+	for key, value := range entries {
+		if _, ok := m.data[key]; ok {
+			overwritten = append(overwritten, key)
+		}
+		m.data[key] = value
+	}
+	return overwritten
+}
+
+// Resolve 按键获取值，Extract 得到的 *Map[string, any] 因此满足 ServiceResolver
+func (m *Map[K, V]) Resolve(key K) (any, error) {
+	value, ok := m.Get(key)
+	if !ok {
+		return nil, fmt.Errorf("service [%v] not found", key)
+	}
+	return value, nil
+}
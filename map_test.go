@@ -0,0 +1,170 @@
+package weave
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestMap_Pop(t *testing.T) {
+	m := NewMap[string, int]()
+	m.Set("a", 1)
+
+	if v, ok := m.Pop("a"); !ok || v != 1 {
+		t.Errorf("应该取出已有的值: %d %v", v, ok)
+	}
+	if m.Contains("a") {
+		t.Error("取出后应该删除")
+	}
+	if v, ok := m.Pop("a"); ok || v != 0 {
+		t.Errorf("不存在的键应该返回零值: %d %v", v, ok)
+	}
+}
+
+func TestMap_Swap(t *testing.T) {
+	m := NewMap[string, int]()
+
+	if v, loaded := m.Swap("a", 1); loaded || v != 0 {
+		t.Errorf("首次设置不应该有原值: %d %v", v, loaded)
+	}
+	if v, loaded := m.Swap("a", 2); !loaded || v != 1 {
+		t.Errorf("应该返回原值: %d %v", v, loaded)
+	}
+	if v, _ := m.Get("a"); v != 2 {
+		t.Errorf("应该设置新值: %d", v)
+	}
+}
+
+func TestMap_PopConcurrent(t *testing.T) {
+	m := NewMap[string, int]()
+	const n = 1000
+
+	// 并发 Set 与 Pop 同一个键，每个写入的值最多被取出一次
+	var wg sync.WaitGroup
+	popped := make(chan int, n)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= n; i++ {
+			m.Set("key", i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			if v, ok := m.Pop("key"); ok {
+				popped <- v
+			}
+		}
+	}()
+	wg.Wait()
+	close(popped)
+
+	seen := make(map[int]bool)
+	last := 0
+	for v := range popped {
+		if seen[v] {
+			t.Fatalf("值 %d 被取出了两次", v)
+		}
+		if v <= last {
+			t.Fatalf("取出的值应该递增: %d 在 %d 之后", v, last)
+		}
+		seen[v] = true
+		last = v
+	}
+}
+
+func TestMap_SwapConcurrent(t *testing.T) {
+	m := NewMap[string, int]()
+	const workers, rounds = 8, 500
+
+	// 每次 Swap 返回的原值互不相同，且恰好有一次返回不存在
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	previous := make(map[int]bool)
+	missing := 0
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				v, loaded := m.Swap("key", w*rounds+i+1)
+				mu.Lock()
+				if !loaded {
+					missing++
+				} else if previous[v] {
+					t.Errorf("原值 %d 被返回了两次", v)
+				} else {
+					previous[v] = true
+				}
+				mu.Unlock()
+			}
+		}(w)
+	}
+	wg.Wait()
+	if missing != 1 {
+		t.Errorf("应该只有一次 Swap 没有原值，实际为 %d", missing)
+	}
+}
+
+func TestMap_CountFunc(t *testing.T) {
+	m := NewMap[string, int]()
+	for i, key := range []string{"a", "b", "c", "d"} {
+		m.Set(key, i)
+	}
+
+	if got := m.CountFunc(func(_ string, v int) bool { return v%2 == 0 }); got != 2 {
+		t.Errorf("计数结果错误: %d", got)
+	}
+	if got := m.CountFunc(func(string, int) bool { return true }); got != m.Len() {
+		t.Errorf("全部匹配时应该等于 Len: %d", got)
+	}
+	if got := NewMap[string, int]().CountFunc(func(string, int) bool { return true }); got != 0 {
+		t.Errorf("空 Map 计数应该为 0: %d", got)
+	}
+}
+
+func TestMap_Freeze(t *testing.T) {
+	m := NewMap[string, int]()
+	m.Set("a", 1)
+	m.Freeze()
+
+	if !m.Frozen() {
+		t.Fatal("Freeze 后应该处于冻结状态")
+	}
+	if v, ok := m.Get("a"); !ok || v != 1 || !m.Contains("a") || m.Contains("b") {
+		t.Error("冻结后应该仍可读取")
+	}
+
+	for name, mutate := range map[string]func(){
+		"Set":    func() { m.Set("b", 2) },
+		"Delete": func() { m.Delete("a") },
+		"Pop":    func() { m.Pop("a") },
+		"Swap":   func() { m.Swap("a", 2) },
+		"Clear":  func() { m.Clear() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("冻结后 %s 应该 panic", name)
+				}
+			}()
+			mutate()
+		}()
+	}
+	if v, _ := m.Get("a"); v != 1 || m.Len() != 1 {
+		t.Error("冻结后的内容不应该被修改")
+	}
+
+	// 并发读取不需要加锁
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				m.Get("a")
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,17 @@
+package weave
+
+// NewMock 创建用于测试依赖方的容器
+// 上下文默认为零值，同一个 mock 实例允许注册为多个名称
+func NewMock[T any](opts ...Option) *Weave[T] {
+	di := New[T](append([]Option{WithAliasPolicy(AliasRecord)}, opts...)...)
+	di.SetCtx(new(T))
+	return di
+}
+
+// ProvideInstance 注册已构建好的实例（通常是 mock），实例原样返回，不经过占位符复制
+// 注册失败时 panic，便于在测试中直接书写
+func ProvideInstance[T any, R any](di *Weave[T], name string, instance *R) {
+	if err := ProvideValue(di, name, instance); err != nil {
+		panic(err)
+	}
+}
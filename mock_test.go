@@ -0,0 +1,47 @@
+package weave
+
+import "testing"
+
+func TestDI_NewMock(t *testing.T) {
+	di := NewMock[TestContext]()
+
+	mockA := &ServiceA{Name: "MockA"}
+	mockB := &ServiceB{Name: "MockB"}
+	ProvideInstance(di, "serviceA", mockA)
+	ProvideInstance(di, "serviceB", mockB)
+	// 同一个mock可以注册为多个名称
+	ProvideInstance(di, "legacyServiceA", mockA)
+
+	// 被测服务
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+		if ctx == nil {
+			t.Error("mock容器应该提供零值上下文")
+		}
+		return &ServiceC{
+			Name:     "ServiceC",
+			ServiceA: MustMake[TestContext, ServiceA](di, "legacyServiceA"),
+			ServiceB: MustMake[TestContext, ServiceB](di, "serviceB"),
+		}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	serviceC := MustMake[TestContext, ServiceC](di, "serviceC")
+	if serviceC.ServiceA != mockA || serviceC.ServiceB != mockB {
+		t.Error("被测服务应该直接拿到注册的mock实例")
+	}
+	if MustMake[TestContext, ServiceA](di, "serviceA") != mockA {
+		t.Error("mock实例应该原样返回，不经过复制")
+	}
+}
+
+func TestDI_ProvideInstancePanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("注册nil实例应该panic")
+		}
+	}()
+	ProvideInstance[TestContext, ServiceA](NewMock[TestContext](), "serviceA", nil)
+}
@@ -0,0 +1,127 @@
+package weave
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Resolver 可按名称解析服务的对象，Weave 本身即满足该接口
+type Resolver interface {
+	GetService(name string) (any, error)
+}
+
+// Mount 将指定前缀的服务解析委托给其他容器
+// 名称匹配前缀时去掉前缀后转发给 target，解析结果会被缓存
+func (s *Weave[T]) Mount(prefix string, target Resolver) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if prefix == "" {
+		return fmt.Errorf("mount prefix cannot be empty")
+	}
+	if target == nil {
+		return fmt.Errorf("mount [%s] target is nil", prefix)
+	}
+
+	var conflict string
+	s.mounts.Range(func(p string, _ Resolver) bool {
+		if strings.HasPrefix(p, prefix) || strings.HasPrefix(prefix, p) {
+			conflict = p
+			return false
+		}
+		return true
+	})
+	if conflict != "" {
+		return fmt.Errorf("mount [%s] conflicts with mount [%s]", prefix, conflict)
+	}
+	if local := s.localNamesWithPrefix(prefix); len(local) > 0 {
+		return fmt.Errorf("mount [%s] conflicts with local service [%s]", prefix, local[0])
+	}
+
+	s.mounts.Set(prefix, target)
+	return nil
+}
+
+// Unmount 取消前缀委托，并清除该前缀下缓存的解析结果
+func (s *Weave[T]) Unmount(prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.mounts.Contains(prefix) {
+		return fmt.Errorf("mount [%s] not found", prefix)
+	}
+	s.mounts.Delete(prefix)
+	for _, name := range s.externals.Keys() {
+		if strings.HasPrefix(name, prefix) {
+			s.externals.Delete(name)
+		}
+	}
+	return nil
+}
+
+// localNamesWithPrefix 返回与前缀冲突的本地服务名称
+func (s *Weave[T]) localNamesWithPrefix(prefix string) []string {
+	var names []string
+	s.entries.Range(func(name string, _ *entry[*T]) bool {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+// validateMounts 构建前检查挂载前缀与本地服务名称是否冲突
+func (s *Weave[T]) validateMounts() error {
+	prefixes := s.mounts.Keys()
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		if local := s.localNamesWithPrefix(prefix); len(local) > 0 {
+			return fmt.Errorf("mount [%s] conflicts with local service [%s]", prefix, local[0])
+		}
+	}
+	return nil
+}
+
+// isMounted 判断名称是否匹配某个挂载前缀
+func (s *Weave[T]) isMounted(name string) bool {
+	mounted := false
+	s.mounts.Range(func(p string, _ Resolver) bool {
+		mounted = strings.HasPrefix(name, p)
+		return !mounted
+	})
+	return mounted
+}
+
+// resolveMounted 通过挂载的容器解析服务，返回缓存的外部条目
+func (s *Weave[T]) resolveMounted(name string) (*entry[*T], bool, error) {
+	if e, ok := s.externals.Get(name); ok {
+		return e, true, nil
+	}
+
+	var prefix string
+	var target Resolver
+	s.mounts.Range(func(p string, r Resolver) bool {
+		if strings.HasPrefix(name, p) {
+			prefix, target = p, r
+			return false
+		}
+		return true
+	})
+	if target == nil {
+		return nil, false, nil
+	}
+
+	instance, err := target.GetService(strings.TrimPrefix(name, prefix))
+	if err != nil {
+		return nil, true, fmt.Errorf("service [%s] resolved via mount [%s]: %w", name, prefix, err)
+	}
+	e := &entry[*T]{
+		instance: instance,
+		built:    true,
+	}
+	s.externals.Set(name, e)
+	return e, true, nil
+}
@@ -0,0 +1,124 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type stubResolver struct {
+	calls    int
+	services map[string]any
+}
+
+func (r *stubResolver) GetService(name string) (any, error) {
+	r.calls++
+	if obj, ok := r.services[name]; ok {
+		return obj, nil
+	}
+	return nil, errors.New("stub: " + name + " missing")
+}
+
+func TestDI_Mount(t *testing.T) {
+	billing := New[TestContext]()
+	billing.SetCtx(&TestContext{Config: "billing"})
+	Provide(billing, "invoice", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "Invoice"}
+	})
+	if err := billing.Build(); err != nil {
+		t.Fatalf("构建billing容器失败: %v", err)
+	}
+
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	if err := di.Mount("billing.", billing); err != nil {
+		t.Fatalf("挂载失败: %v", err)
+	}
+	Provide(di, "checkout", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "Checkout", ServiceA: MustMake[TestContext, ServiceA](di, "billing.invoice")}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	checkout := MustMake[TestContext, ServiceB](di, "checkout")
+	if checkout.ServiceA != MustMake[TestContext, ServiceA](billing, "invoice") {
+		t.Error("应该拿到被委托容器中的同一实例")
+	}
+
+	graph := di.GetDependencyGraph()
+	if !graph.External["billing.invoice"] {
+		t.Errorf("billing.invoice应该被标记为外部服务: %v", graph.External)
+	}
+	if !equalSlices(graph.Dependents["billing.invoice"], []string{"checkout"}) {
+		t.Errorf("billing.invoice应该被checkout依赖，实际为: %v", graph.Dependents["billing.invoice"])
+	}
+	if dot := di.GenerateDOTGraph(); !strings.Contains(dot, "\"billing.invoice\" [fillcolor=white, style=\"filled,dashed\"") {
+		t.Errorf("DOT图应该以外部节点渲染billing.invoice:\n%s", dot)
+	}
+	if output := di.PrintDependencyGraph(); !strings.Contains(output, "外部服务") {
+		t.Errorf("文本输出应该包含外部服务分类:\n%s", output)
+	}
+}
+
+func TestDI_MountCacheAndErrors(t *testing.T) {
+	stub := &stubResolver{services: map[string]any{"invoice": &ServiceA{Name: "Invoice"}}}
+
+	di := New[TestContext]()
+	if err := di.Mount("billing.", stub); err != nil {
+		t.Fatalf("挂载失败: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := di.GetService("billing.invoice"); err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+	}
+	if stub.calls != 1 {
+		t.Errorf("解析结果应该被缓存，实际调用 %d 次", stub.calls)
+	}
+
+	_, err := di.GetService("billing.refund")
+	if err == nil || !strings.Contains(err.Error(), "stub: refund missing") || !strings.Contains(err.Error(), "[billing.]") {
+		t.Errorf("应该传递被委托容器的错误，实际为: %v", err)
+	}
+
+	if err := di.Unmount("billing."); err != nil {
+		t.Fatalf("取消挂载失败: %v", err)
+	}
+	if _, err := di.GetService("billing.invoice"); err == nil {
+		t.Error("取消挂载后不应该再解析到外部服务")
+	}
+	if err := di.Unmount("billing."); err == nil {
+		t.Error("重复取消挂载应该返回错误")
+	}
+}
+
+func TestDI_MountConflicts(t *testing.T) {
+	stub := &stubResolver{}
+
+	di := New[TestContext]()
+	Provide(di, "billing.local", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "Local"}
+	})
+	if err := di.Mount("billing.", stub); err == nil {
+		t.Error("与本地服务名称冲突时应该返回错误")
+	}
+	if err := di.Mount("", stub); err == nil {
+		t.Error("空前缀应该返回错误")
+	}
+	if err := di.Mount("pay.", stub); err != nil {
+		t.Fatalf("挂载失败: %v", err)
+	}
+	if err := di.Mount("pay.v2.", stub); err == nil {
+		t.Error("重叠的前缀应该返回错误")
+	}
+
+	// 挂载后再注册冲突名称，构建时报错
+	Provide(di, "pay.local", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "Local"}
+	})
+	if err := di.Build(); err == nil || !strings.Contains(err.Error(), "pay.local") {
+		t.Errorf("构建时应该检测到挂载冲突，实际为: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package weave
+
+import "errors"
+
+// MustBuild 构建容器，失败时 panic
+// 服务构建失败时 panic 的值为 *BuildError：未开启 WithCollectErrors 时 Errors 只包含最先失败的服务，
+// Chain 记录从顶层服务到最先失败的服务的解析链，Report 为本次构建的检查报告；
+// 其他错误（如 *ReadyError、*CycleError）原样 panic
+func (s *Weave[T]) MustBuild() {
+	err := s.Build()
+	if err == nil {
+		return
+	}
+
+	s.mu.RLock()
+	chain := s.failedChain
+	s.mu.RUnlock()
+	report := s.BuildReport()
+
+	if buildErr, ok := AsBuildError(err); ok {
+		if len(buildErr.Chain) == 0 {
+			buildErr.Chain = chain
+		}
+		buildErr.Report = report
+		panic(buildErr)
+	}
+	if len(chain) == 0 {
+		panic(err)
+	}
+	panic(&BuildError{Errors: map[string]error{chain[len(chain)-1]: err}, Chain: chain, Report: report})
+}
+
+// AsBuildError 从错误链中取出 *BuildError，便于结构化地记录失败的服务
+func AsBuildError(err error) (*BuildError, bool) {
+	var buildErr *BuildError
+	if errors.As(err, &buildErr) {
+		return buildErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,130 @@
+package weave
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDI_MustBuild(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "app", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "database")
+		return &ServiceA{Name: "app"}
+	})
+	Provide(di, "database", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "config")
+		return &ServiceA{Name: "database"}
+	})
+
+	defer func() {
+		buildErr, ok := recover().(*BuildError)
+		if !ok {
+			t.Fatal("MustBuild 应该以 *BuildError panic")
+		}
+		if len(buildErr.Errors) != 1 || buildErr.Errors["database"] == nil {
+			t.Errorf("应该记录最先失败的服务: %v", buildErr.Errors)
+		}
+		// 顶层服务取决于遍历顺序，链的末尾总是最先失败的服务
+		if n := len(buildErr.Chain); n == 0 || buildErr.Chain[n-1] != "database" {
+			t.Errorf("解析链错误: %v", buildErr.Chain)
+		}
+		if !strings.Contains(buildErr.Error(), "service [config] not found") {
+			t.Errorf("错误信息应该包含失败原因: %v", buildErr)
+		}
+		if buildErr.Report == nil {
+			t.Error("应该附带构建检查报告")
+		}
+	}()
+	di.MustBuild()
+}
+
+func TestDI_MustBuildChain(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "app", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "database")
+		return &ServiceA{Name: "app"}
+	})
+	Provide(di, "database", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "config")
+		return &ServiceA{Name: "database"}
+	})
+
+	var recovered any
+	func() {
+		defer func() { recovered = recover() }()
+		di.MustBuild()
+	}()
+	buildErr, ok := AsBuildError(fmt.Errorf("startup: %w", recovered.(error)))
+	if !ok {
+		t.Fatalf("应该可以从包装后的错误中取出 *BuildError: %v", recovered)
+	}
+	// 先遍历到 app 时 database 在其 builder 中构建
+	if chain := buildErr.Chain; !reflect.DeepEqual(chain, []string{"database"}) && !reflect.DeepEqual(chain, []string{"app", "database"}) {
+		t.Errorf("解析链错误: %v", chain)
+	}
+}
+
+func TestDI_MustBuildOtherErrors(t *testing.T) {
+	di := New[TestContext]()
+	defer func() {
+		var noServices *ErrNoServices
+		if err, ok := recover().(error); !ok || !errors.As(err, &noServices) {
+			t.Errorf("非服务构建错误应该原样 panic，实际为 %v", err)
+		}
+	}()
+	di.MustBuild()
+}
+
+func TestDI_MustBuildCollectErrors(t *testing.T) {
+	di := New[TestContext](WithCollectErrors())
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "a", func(ctx *TestContext) *ServiceA { return nil })
+	Provide(di, "b", func(ctx *TestContext) *ServiceA { return nil })
+
+	defer func() {
+		buildErr, ok := recover().(*BuildError)
+		if !ok || len(buildErr.Errors) != 2 {
+			t.Fatalf("开启 WithCollectErrors 时应该以汇总的 *BuildError panic: %v", buildErr)
+		}
+		if n := len(buildErr.Chain); n == 0 || buildErr.Errors[buildErr.Chain[n-1]] == nil {
+			t.Errorf("汇总的错误也应该记录最先失败的服务的解析链: %v", buildErr.Chain)
+		}
+		if buildErr.Report == nil {
+			t.Error("应该附带构建检查报告")
+		}
+	}()
+	di.MustBuild()
+}
+
+func TestDI_MustBuildReport(t *testing.T) {
+	check := func(name string) func() error {
+		return func() error {
+			if name == "app" {
+				return errors.New("leaked goroutine")
+			}
+			return nil
+		}
+	}
+	di := New[TestContext](WithBuildChecks(check))
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "app", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "config")
+		return &ServiceA{Name: "app"}
+	})
+
+	defer func() {
+		buildErr, ok := recover().(*BuildError)
+		if !ok || buildErr.Report == nil {
+			t.Fatalf("MustBuild 应该以附带报告的 *BuildError panic: %v", buildErr)
+		}
+		if failures := buildErr.Report.CheckFailures["app"]; len(failures) != 1 {
+			t.Errorf("报告应该包含构建检查发现的问题: %v", buildErr.Report)
+		}
+	}()
+	di.MustBuild()
+}
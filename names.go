@@ -0,0 +1,124 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// VerifyNames 检查服务名称是否都已注册，供测试使用
+// 名称未注册时给出相近名称的建议，所有问题汇总到一个错误中返回
+func VerifyNames[T any](di *Weave[T], names ...string) error {
+	problems := []string{}
+	for _, name := range names {
+		if !di.knows(name) {
+			problems = append(problems, di.notFound(name).Error())
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d service names are not registered:\n  %s", len(problems), strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// VerifyTypes 检查服务名称已注册且类型与期望相符，期望类型可以是 R、*R 或接口类型
+func VerifyTypes[T any](di *Weave[T], expected map[string]reflect.Type) error {
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	problems := []string{}
+	for _, name := range names {
+		want := expected[name]
+		e, ok := di.entries.Get(name)
+		if !ok {
+			if !di.knows(name) {
+				problems = append(problems, di.notFound(name).Error())
+			}
+			// 重命名或委托的服务无法在解析前校验类型
+			continue
+		}
+		got := e.instanceType()
+		if got.AssignableTo(want) || got.Kind() == reflect.Ptr && got.Elem() == want {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("service [%s] is %s, expected %s", name, got, want))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d service types do not match:\n  %s", len(problems), strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// knows 判断名称是否可以被解析（已注册、已重命名或匹配挂载前缀），不产生副作用
+func (s *Weave[T]) knows(name string) bool {
+	return s.entries.Contains(name) || s.renames.Contains(name) || s.isMounted(name)
+}
+
+// SuggestSimilar 服务不存在时附加编辑距离最小的服务名称，可用作 WithNotFoundHandler 的处理函数
+func SuggestSimilar(name string, available []string) error {
+	return &ErrServiceNotFound{Name: name, Suggestions: suggestNames(name, available)}
+}
+
+// suggestNames 返回与 name 编辑距离最小的候选名称（最多3个，距离相同时按名称排序）
+func suggestNames(name string, candidates []string) []string {
+	type scored struct {
+		name     string
+		distance int
+	}
+
+	// 允许的最大编辑距离随名称长度增长
+	limit := len(name)/3 + 1
+	matches := []scored{}
+	lower := strings.ToLower(name)
+	for _, c := range candidates {
+		d := levenshtein(lower, strings.ToLower(c))
+		if d <= limit {
+			matches = append(matches, scored{name: c, distance: d})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	result := []string{}
+	for i := 0; i < len(matches) && i < 3 && matches[i].distance == matches[0].distance; i++ {
+		result = append(result, matches[i].name)
+	}
+	return result
+}
+
+// levenshtein 计算两个字符串的编辑距离
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, minInt(curr[j-1]+1, prev[j-1]+cost))
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
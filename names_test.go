@@ -0,0 +1,134 @@
+package weave
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVerifyNames(t *testing.T) {
+	di := New[TestContext]()
+	Provide(di, "userService", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "User"}
+	})
+	Provide(di, "orderService", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "Order"}
+	})
+
+	if err := VerifyNames(di, "userService", "orderService"); err != nil {
+		t.Errorf("已注册的名称不应该报错: %v", err)
+	}
+
+	err := VerifyNames(di, "userService", "usrService", "paymentGateway")
+	if err == nil {
+		t.Fatal("未注册的名称应该报错")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "2 service names") {
+		t.Errorf("应该汇总所有未注册的名称: %s", msg)
+	}
+	if !strings.Contains(msg, "[usrService] not found, did you mean [userService]?") {
+		t.Errorf("应该给出相近名称的建议: %s", msg)
+	}
+	if strings.Contains(msg, "[paymentGateway] not found, did you mean") {
+		t.Errorf("没有相近名称时不应该给出建议: %s", msg)
+	}
+}
+
+func TestVerifyTypes(t *testing.T) {
+	di := New[TestContext]()
+	Provide(di, "userService", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "User"}
+	})
+	Provide(di, "orderService", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "Order"}
+	})
+
+	err := VerifyTypes(di, map[string]reflect.Type{
+		"userService":  reflect.TypeOf(ServiceA{}),
+		"orderService": reflect.TypeOf(&ServiceB{}),
+	})
+	if err != nil {
+		t.Errorf("类型相符时不应该报错: %v", err)
+	}
+
+	err = VerifyTypes(di, map[string]reflect.Type{
+		"userService":  reflect.TypeOf(ServiceB{}),
+		"orderServce":  reflect.TypeOf(ServiceB{}),
+		"orderService": reflect.TypeOf(ServiceB{}),
+	})
+	if err == nil {
+		t.Fatal("类型不符时应该报错")
+	}
+	if !strings.Contains(err.Error(), "service [userService] is *weave.ServiceA, expected weave.ServiceB") {
+		t.Errorf("应该说明实际类型和期望类型: %v", err)
+	}
+	if !strings.Contains(err.Error(), "did you mean [orderService]") {
+		t.Errorf("未注册的名称应该给出建议: %v", err)
+	}
+}
+
+func TestDI_NotFoundHandler(t *testing.T) {
+	di := New[TestContext](WithNotFoundHandler(SuggestSimilar))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "userService", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "userService"}
+	})
+	Provide(di, "orderService", func(ctx *TestContext) *ServiceB {
+		users, ok := TryMake[TestContext, ServiceA](di, "usrService")
+		if !ok {
+			return nil
+		}
+		return &ServiceB{Name: "orderService", ServiceA: users}
+	})
+
+	err := di.Build()
+	if err == nil || !strings.Contains(err.Error(), "service [usrService] not found, did you mean [userService]?") {
+		t.Errorf("构建错误应该包含相近的服务名称: %v", err)
+	}
+
+	var got []string
+	custom := New[TestContext](WithNotFoundHandler(func(name string, available []string) error {
+		got = available
+		return errors.New("custom: " + name)
+	}))
+	Provide(custom, "b", func(ctx *TestContext) *ServiceA { return &ServiceA{} })
+	Provide(custom, "a", func(ctx *TestContext) *ServiceA { return &ServiceA{} })
+	if _, err := custom.GetService("c"); err == nil || err.Error() != "custom: c" {
+		t.Errorf("应该使用自定义的错误: %v", err)
+	}
+	if strings.Join(got, ",") != "a,b" {
+		t.Errorf("应该传入排序后的服务名称: %v", got)
+	}
+}
+
+func TestDI_MustMakeSuggestions(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "database", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "database"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	_, err := di.GetService("databse")
+	var notFound *ErrServiceNotFound
+	if !errors.As(err, &notFound) {
+		t.Fatalf("应该返回 ErrServiceNotFound，实际为 %v", err)
+	}
+	if !reflect.DeepEqual(notFound.Suggestions, []string{"database"}) {
+		t.Errorf("建议的名称错误: %v", notFound.Suggestions)
+	}
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || err.Error() != "service [databse] not found, did you mean [database]?" {
+			t.Errorf("MustMake 的 panic 应该包含相近的服务名称: %v", r)
+		}
+	}()
+	MustMake[TestContext, ServiceA](di, "databse")
+}
@@ -0,0 +1,45 @@
+package weave
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ProvideOptional 注册可选服务，构建失败时该服务被跳过而不会导致 Build 失败
+// 依赖方应使用 TryMake 获取可选服务
+func ProvideOptional[T any, R any](di *Weave[T], name string, builder func(*T) *R) {
+	di.assignEntry(name, &entry[*T]{
+		builder:  pointerBuilder(builder),
+		instance: new(R),
+		optional: true,
+	})
+}
+
+// SkippedServices 返回最近一次 Build 中构建失败而被跳过的可选服务及原因
+func (s *Weave[T]) SkippedServices() map[string]error {
+	return s.skipped.ToMap()
+}
+
+// BuildError 开启 WithCollectErrors 后 Build 汇总的构建错误
+type BuildError struct {
+	Errors map[string]error
+	// Chain 由 MustBuild 包装时，从顶层服务到最先失败的服务的解析链
+	Chain []string
+	// Report 由 MustBuild 包装时，本次构建的检查报告，见 WithBuildChecks
+	Report *BuildReport
+}
+
+func (e *BuildError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, e.Errors[name].Error())
+	}
+	return fmt.Sprintf("%d services failed to build: %s", len(names), strings.Join(parts, "; "))
+}
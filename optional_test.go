@@ -0,0 +1,174 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDI_ProvideOptional(t *testing.T) {
+	di := New[TestContext](WithCollectErrors())
+	di.SetCtx(&TestContext{Config: "test"})
+
+	calls := 0
+	ProvideOptional(di, "metrics", func(ctx *TestContext) *ServiceA {
+		calls++
+		return nil
+	})
+	Provide(di, "userService", func(ctx *TestContext) *ServiceB {
+		metrics, _ := TryMake[TestContext, ServiceA](di, "metrics")
+		return &ServiceB{Name: "UserService", ServiceA: metrics}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("可选服务失败不应该导致构建失败: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("失败的可选服务只应该构建一次，实际为 %d 次", calls)
+	}
+
+	skipped := di.SkippedServices()
+	if len(skipped) != 1 || skipped["metrics"] == nil {
+		t.Fatalf("应该记录被跳过的可选服务，实际为: %v", skipped)
+	}
+
+	if svc := MustMake[TestContext, ServiceB](di, "userService"); svc.ServiceA != nil {
+		t.Error("依赖方应该拿不到被跳过的可选服务")
+	}
+	if _, ok := TryMake[TestContext, ServiceA](di, "metrics"); ok {
+		t.Error("构建后不应该能获取被跳过的可选服务")
+	}
+	if _, ok := di.Extract().Get("metrics"); ok {
+		t.Error("被跳过的可选服务不应该出现在提取的注册表中")
+	}
+}
+
+func TestDI_CollectErrors(t *testing.T) {
+	di := New[TestContext](WithCollectErrors())
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return nil
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return nil
+	})
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+		return &ServiceC{Name: "ServiceC"}
+	})
+
+	err := di.Build()
+	var buildErr *BuildError
+	if !errors.As(err, &buildErr) {
+		t.Fatalf("期望返回*BuildError，实际为: %v", err)
+	}
+	if len(buildErr.Errors) != 2 {
+		t.Errorf("期望汇总2个错误，实际为: %v", buildErr.Errors)
+	}
+	if !strings.Contains(err.Error(), "[serviceA]") || !strings.Contains(err.Error(), "[serviceB]") {
+		t.Errorf("错误信息应该包含所有失败的服务: %v", err)
+	}
+	if info, _ := di.Info("serviceC"); !info.Built {
+		t.Error("其余服务应该继续构建")
+	}
+}
+
+func TestDI_OptionalDependencies(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	ProvideValue(di, "cache", &ServiceA{Name: "cache"})
+	ProvideOptional(di, "tracer", func(ctx *TestContext) *ServiceA { return nil })
+	Provide(di, "api", func(ctx *TestContext) *ServiceB {
+		cache, _ := TryMake[TestContext, ServiceA](di, "cache")
+		MakeOr(di, "tracer", &ServiceA{Name: "noop"})
+		if metrics := MakeOr(di, "metrics", &ServiceA{Name: "noop"}); metrics.Name != "noop" {
+			t.Error("MakeOr 在服务不存在时应该返回 fallback")
+		}
+		return &ServiceB{Name: "api", ServiceA: cache}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	graph := di.GetDependencyGraph()
+	want := map[string]bool{"cache": true, "tracer": false, "metrics": false}
+	if got := graph.Optional["api"]; len(got) != 3 || got["cache"] != want["cache"] || got["tracer"] != want["tracer"] || got["metrics"] != want["metrics"] {
+		t.Errorf("可选依赖记录错误: %v", got)
+	}
+	if _, ok := graph.Optional["cache"]; ok {
+		t.Error("没有可选依赖的服务不应该出现")
+	}
+
+	dot := di.GenerateDOTGraph()
+	for _, line := range []string{
+		`"cache" -> "api" [style=dashed, label="optional"];`,
+		`"tracer" -> "api" [style=dotted, color=gray, label="optional (absent)"];`,
+		`"metrics" [fillcolor=white, fontcolor=gray40, style="filled,dotted", label="❔ metrics"];`,
+		`"metrics" -> "api" [style=dotted, color=gray, label="optional (absent)"];`,
+	} {
+		if !strings.Contains(dot, line) {
+			t.Errorf("DOT 图中应该包含 %s:\n%s", line, dot)
+		}
+	}
+
+	if out := di.PrintDependencyGraph(); !strings.Contains(out, "📦 api <- cache (present), metrics (absent), tracer (absent)") {
+		t.Errorf("依赖图谱中应该显示可选依赖是否存在:\n%s", out)
+	}
+
+	// 构建完成后调用 TryMake 不记录
+	TryMake[TestContext, ServiceA](di, "missing")
+	if len(di.GetDependencyGraph().Optional) != 1 {
+		t.Error("构建完成后的解析不应该记录可选依赖")
+	}
+}
+
+type zeroConfig struct {
+	Timeout int
+	Debug   bool
+}
+
+func TestMakeOrZero(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "server", func(ctx *TestContext) *ServiceA {
+		cfg := MakeOrZero[TestContext, zeroConfig](di, "serverConfig")
+		if cfg.Timeout != 0 || cfg.Debug {
+			t.Errorf("未注册的服务应该返回零值: %+v", cfg)
+		}
+		return &ServiceA{Name: "server"}
+	})
+	Provide(di, "db", func(ctx *TestContext) *ServiceA {
+		cfg := MakeOrZero[TestContext, zeroConfig](di, "dbConfig")
+		if cfg.Timeout != 30 {
+			t.Errorf("已注册的服务应该优先使用: %+v", cfg)
+		}
+		return &ServiceA{Name: "db"}
+	})
+	Provide(di, "dbConfig", func(ctx *TestContext) *zeroConfig { return &zeroConfig{Timeout: 30} })
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if _, ok := di.Extract().Get("serverConfig"); ok {
+		t.Error("零值不应该注册到容器中")
+	}
+	if a, b := MakeOrZero[TestContext, zeroConfig](di, "serverConfig"), MakeOrZero[TestContext, zeroConfig](di, "serverConfig"); a == b {
+		t.Error("每次调用应该返回新的零值实例")
+	}
+	if err := Assert(di).DependsOn("db", "dbConfig").Err(); err != nil {
+		t.Error(err)
+	}
+	if present, ok := di.GetDependencyGraph().Optional["server"]["serverConfig"]; !ok || present {
+		t.Error("未注册的服务应该作为不存在的可选依赖记录")
+	}
+
+	defer func() {
+		var mismatch *ErrTypeMismatch
+		if err, _ := recover().(error); !errors.As(err, &mismatch) {
+			t.Errorf("类型不符时应该 panic *ErrTypeMismatch，实际为 %v", err)
+		}
+	}()
+	MakeOrZero[TestContext, ServiceB](di, "dbConfig")
+}
@@ -0,0 +1,228 @@
+package weave
+
+import "time"
+
+// Option 容器配置项，在 New 时传入
+type Option func(*options)
+
+// options 容器配置
+type options struct {
+	// 严格重命名模式：通过旧名称解析服务时直接报错
+	strictRenames bool
+	// 弃用事件处理函数
+	onDeprecated func(DeprecationEvent)
+	// 实例指针共享策略
+	aliasPolicy AliasPolicy
+	// 当前激活的 profile
+	activeProfile string
+	// 失败注入的随机数种子
+	chaosSeed int64
+	// 单个服务的预热超时时间
+	warmTimeout time.Duration
+	// 构建失败时是否继续并汇总错误
+	collectErrors bool
+	// Ready 回调的最大并发数，0 表示串行执行
+	readyConcurrency int
+	// 是否延迟分配占位符
+	lazyPlaceholders bool
+	// 循环解析时是否返回错误
+	cycleErrors bool
+	// 构建过程记录的最大事件数，0 表示不记录
+	traceMaxEvents int
+	// 是否禁止未被允许的循环依赖
+	strictCycles bool
+	// 服务被替换时的处理函数
+	onReplaced func(ReplaceEvent)
+	// 服务不存在时生成错误的函数
+	onNotFound func(name string, available []string) error
+	// 是否将 builder 中的任意 panic 转换为错误
+	recoverPanics bool
+	// 是否允许构建没有任何服务的容器
+	allowEmpty bool
+	// 服务基于不同的上下文构建时是否返回错误
+	strictCtx bool
+	// 在每个 builder 前后执行的检查
+	buildChecks []BuildCheck
+	// 是否允许原地修改已被提取的服务
+	allowLiveMutation bool
+	// 是否打乱构建顺序
+	shuffleBuild bool
+	// 打乱构建顺序的随机数种子
+	shuffleSeed int64
+	// 是否检测 builder 通过其他容器解析服务
+	foreignCheck bool
+	// 服务注册时的回调
+	onRegister func(name string)
+	// 构建后的依赖图谱检查，为 nil 时不检查
+	lint *LintOptions
+}
+
+func defaultOptions() options {
+	return options{
+		onDeprecated: logDeprecation,
+		chaosSeed:    1,
+	}
+}
+
+// WithStrictRenames 开启严格重命名模式，通过旧名称解析服务将返回错误
+func WithStrictRenames() Option {
+	return func(o *options) {
+		o.strictRenames = true
+	}
+}
+
+// WithDeprecationHandler 设置通过旧名称解析服务时的弃用事件处理函数，默认输出日志
+func WithDeprecationHandler(fn func(DeprecationEvent)) Option {
+	return func(o *options) {
+		o.onDeprecated = fn
+	}
+}
+
+// AliasPolicy 同一实例指针以多个名称注册时的处理策略
+type AliasPolicy int
+
+const (
+	// AliasReject 拒绝第二次注册并返回错误
+	AliasReject AliasPolicy = iota
+	// AliasRecord 允许注册，并在 ServiceInfo 中记录共享关系
+	AliasRecord
+)
+
+// WithAliasPolicy 设置 ProvideValue 注册同一实例指针时的处理策略，默认为 AliasReject
+func WithAliasPolicy(policy AliasPolicy) Option {
+	return func(o *options) {
+		o.aliasPolicy = policy
+	}
+}
+
+// WithActiveProfile 设置当前激活的 profile，只有未指定 profile 或包含该 profile 的服务会被注册
+func WithActiveProfile(profile string) Option {
+	return func(o *options) {
+		o.activeProfile = profile
+	}
+}
+
+// WithChaosSeed 设置 FailBuildWithProbability 使用的随机数种子，默认为 1
+func WithChaosSeed(seed int64) Option {
+	return func(o *options) {
+		o.chaosSeed = seed
+	}
+}
+
+// WithWarmTimeout 设置 Warm 时单个服务的预热超时时间，默认不限制
+func WithWarmTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.warmTimeout = d
+	}
+}
+
+// WithCollectErrors 构建失败时继续构建其余服务，最终以 *BuildError 汇总返回所有错误
+func WithCollectErrors() Option {
+	return func(o *options) {
+		o.collectErrors = true
+	}
+}
+
+// WithConcurrentReady 使用最多 n 个并发执行 Ready 回调
+// 每个回调的 panic 会被单独捕获并以 *ReadyError 汇总，Build 会等待所有回调完成后返回
+func WithConcurrentReady(n int) Option {
+	return func(o *options) {
+		o.readyConcurrency = n
+	}
+}
+
+// WithLazyPlaceholders 注册服务时不预先分配占位符
+// 服务在构建完成前被引用（循环依赖或提前解析）时才分配占位符并在构建后复制结果，
+// 其余服务直接保存 builder 返回的实例，大型容器可省去大部分占位符分配
+func WithLazyPlaceholders() Option {
+	return func(o *options) {
+		o.lazyPlaceholders = true
+	}
+}
+
+// WithCycleErrors 解析正在构建中的服务时返回 *ErrResolutionInProgress，而不是尚未填充的占位符
+// builder 可据此自行决定如何处理循环依赖
+func WithCycleErrors() Option {
+	return func(o *options) {
+		o.cycleErrors = true
+	}
+}
+
+// WithBuildTrace 记录构建过程中的事件，最多保留最近的 maxEvents 个
+// 通过 BuildTrace 获取，FormatTrace 输出调用树
+func WithBuildTrace(maxEvents int) Option {
+	return func(o *options) {
+		o.traceMaxEvents = maxEvents
+	}
+}
+
+// WithStrictCycles 构建时发现未通过 AllowCycle 允许的循环依赖则返回 *CycleError
+func WithStrictCycles() Option {
+	return func(o *options) {
+		o.strictCycles = true
+	}
+}
+
+// WithReplaceHandler 设置服务被 Swap/SwapAll 替换后的处理函数，每次替换产生一个事件，新值均与原实例相等时不产生
+func WithReplaceHandler(fn func(ReplaceEvent)) Option {
+	return func(o *options) {
+		o.onReplaced = fn
+	}
+}
+
+// WithRegisterHook 设置服务注册时的回调，每次 Provide 系列函数写入服务（包括覆盖同名服务）时同步调用
+// 回调在持有容器锁时执行，不能再调用容器的方法；回调在写入前执行，可以 panic 拒绝不符合命名规范的服务
+func WithRegisterHook(fn func(name string)) Option {
+	return func(o *options) {
+		o.onRegister = fn
+	}
+}
+
+// WithNotFoundHandler 自定义服务不存在时的错误，available 为已注册的服务名称（已排序）
+// 可使用 SuggestSimilar 在错误中附加相近的服务名称
+func WithNotFoundHandler(fn func(name string, available []string) error) Option {
+	return func(o *options) {
+		o.onNotFound = fn
+	}
+}
+
+// WithPanicRecovery 将 builder 中的任意 panic 转换为构建错误
+// 未开启时只有 weave 自身的错误（如 MustMake 找不到服务）引发的 panic 会被转换，其他 panic 继续向上抛出
+func WithPanicRecovery() Option {
+	return func(o *options) {
+		o.recoverPanics = true
+	}
+}
+
+// WithAllowEmpty 允许构建没有注册任何服务的容器
+// 未开启时对空容器 Build 返回 *ErrNoServices
+func WithAllowEmpty() Option {
+	return func(o *options) {
+		o.allowEmpty = true
+	}
+}
+
+// WithStrictCtx 已构建的服务基于不同的上下文（两次构建之间调用了 SetCtx）时，Build 返回 *ErrMixedCtx
+// 未开启时只输出警告日志
+func WithStrictCtx() Option {
+	return func(o *options) {
+		o.strictCtx = true
+	}
+}
+
+// WithBuildChecks 在每个 builder 执行前调用 check，builder 执行后调用其返回的 post
+// post 按与检查相反的顺序调用，返回的错误不会使构建失败，按服务记录在 BuildReport 中；多次使用时检查依次追加
+// 内置的检查见 GoroutineLeakCheck 与 BuildBudgetCheck
+func WithBuildChecks(checks ...BuildCheck) Option {
+	return func(o *options) {
+		o.buildChecks = append(o.buildChecks, checks...)
+	}
+}
+
+// WithAllowLiveMutation 允许 Swap、SwapAll、ReplaceBuilder 原地修改已通过 Extract 提取的服务
+// 未开启时这些操作返回 *ErrLiveMutation，避免使用方持有的实例在不知情时被改变
+func WithAllowLiveMutation() Option {
+	return func(o *options) {
+		o.allowLiveMutation = true
+	}
+}
@@ -0,0 +1,169 @@
+package weave
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+)
+
+// BuildOrder 返回已构建服务的拓扑顺序，依赖总是排在依赖方之前
+// 通过 ProvideValue 注册的服务没有依赖，按名称排在最前面
+func (s *Weave[T]) BuildOrder() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.orderedNames()
+}
+
+// ForEachInOrder 按依赖顺序遍历已构建的服务，依赖总是先于依赖方被访问，已释放的仅构建期服务被跳过
+// 循环依赖中的服务作为一个整体连续访问，整体内部按名称排序；顺序与构建顺序无关，每次调用都相同
+// fn 返回错误时停止遍历，返回的错误标明出错的服务并可通过 errors.Is/As 取得原错误
+// 依赖关系被 CompactGraph 释放后按构建顺序遍历
+// 这是按依赖顺序启动、预热各服务的基础
+func (s *Weave[T]) ForEachInOrder(fn func(name string, instance any) error) error {
+	return s.forEachOrdered(false, fn)
+}
+
+// ForEachInReverseOrder 按 ForEachInOrder 的相反顺序遍历，依赖方先于依赖被访问，适合关闭、释放资源
+func (s *Weave[T]) ForEachInReverseOrder(fn func(name string, instance any) error) error {
+	return s.forEachOrdered(true, fn)
+}
+
+func (s *Weave[T]) forEachOrdered(reverse bool, fn func(name string, instance any) error) error {
+	s.mu.RLock()
+	names := s.topologicalNames()
+	if reverse {
+		for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+			names[i], names[j] = names[j], names[i]
+		}
+	}
+	// 跳过已释放的仅构建期服务
+	kept := make([]string, 0, len(names))
+	instances := make([]any, 0, len(names))
+	for _, name := range names {
+		e, _ := s.entries.Get(name)
+		if e.released {
+			continue
+		}
+		kept = append(kept, name)
+		instances = append(instances, e.instance)
+	}
+	names = kept
+	s.mu.RUnlock()
+
+	for i, name := range names {
+		if err := fn(name, instances[i]); err != nil {
+			return fmt.Errorf("service [%s]: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// topologicalNames 按依赖图返回已构建服务的确定性拓扑顺序
+// 强连通分量作为整体排序，无依赖关系的分量之间按首个名称排序
+func (s *Weave[T]) topologicalNames() []string {
+	if s.compacted&compactedGraph != 0 {
+		return s.orderedNames()
+	}
+
+	built := []string{}
+	s.entries.Range(func(name string, e *entry[*T]) bool {
+		if e.built {
+			built = append(built, name)
+		}
+		return true
+	})
+	dependencies, _ := s.edges.materialize(built)
+	s.addGroupOrderEdges(dependencies)
+
+	components := stronglyConnectedComponents(dependencies)
+	componentOf := make(map[string]int, len(built))
+	for i, c := range components {
+		for _, name := range c {
+			componentOf[name] = i
+		}
+	}
+
+	// pending 为分量尚未访问的依赖分量数
+	pending := make([]int, len(components))
+	dependents := make([][]int, len(components))
+	for i, c := range components {
+		seen := make(map[int]bool)
+		for _, name := range c {
+			for _, dep := range dependencies[name] {
+				j, ok := componentOf[dep]
+				if !ok || j == i || seen[j] {
+					continue
+				}
+				seen[j] = true
+				pending[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	queue := &intHeap{}
+	for i := range components {
+		if pending[i] == 0 {
+			heap.Push(queue, i)
+		}
+	}
+	names := make([]string, 0, len(built))
+	for queue.Len() > 0 {
+		i := heap.Pop(queue).(int)
+		for _, name := range components[i] {
+			// 依赖中可能包含外部服务，不参与遍历
+			if _, ok := dependencies[name]; ok {
+				names = append(names, name)
+			}
+		}
+		for _, j := range dependents[i] {
+			pending[j]--
+			if pending[j] == 0 {
+				heap.Push(queue, j)
+			}
+		}
+	}
+	return names
+}
+
+// intHeap 最小堆，分量按首个名称排序，下标越小越靠前
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *intHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// orderedNames 按构建顺序返回当前已构建的服务，服务被重新注册并构建时以最后一次为准
+func (s *Weave[T]) orderedNames() []string {
+	last := make(map[string]int, len(s.buildOrder))
+	for i, name := range s.buildOrder {
+		last[name] = i
+	}
+
+	values := []string{}
+	s.entries.Range(func(name string, e *entry[*T]) bool {
+		if _, ok := last[name]; !ok && e.built {
+			values = append(values, name)
+		}
+		return true
+	})
+	sort.Strings(values)
+
+	names := values
+	for i, name := range s.buildOrder {
+		if last[name] != i {
+			continue
+		}
+		if e, ok := s.entries.Get(name); ok && e.built {
+			names = append(names, name)
+		}
+	}
+	return names
+}
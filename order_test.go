@@ -0,0 +1,126 @@
+package weave
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDI_ForEachInOrder(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	ProvideValue(di, "config", &ServiceA{Name: "config"})
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+		return &ServiceC{
+			Name:     "ServiceC",
+			ServiceA: MustMake[TestContext, ServiceA](di, "serviceA"),
+			ServiceB: MustMake[TestContext, ServiceB](di, "serviceB"),
+		}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "config")
+		return &ServiceA{Name: "ServiceA"}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	expected := []string{"config", "serviceA", "serviceB", "serviceC"}
+	if order := di.BuildOrder(); !equalSlices(order, expected) {
+		t.Errorf("期望构建顺序为 %v，实际为 %v", expected, order)
+	}
+
+	visited := []string{}
+	err := di.ForEachInOrder(func(name string, instance any) error {
+		visited = append(visited, name)
+		if name == "serviceB" {
+			if instance.(*ServiceB).Name != "ServiceB" {
+				t.Errorf("应该传入已构建的实例")
+			}
+			return errors.New("stop")
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "service [serviceB]: stop" {
+		t.Errorf("应该返回标明服务的回调错误，实际为: %v", err)
+	}
+	if !equalSlices(visited, []string{"config", "serviceA", "serviceB"}) {
+		t.Errorf("出错后应该停止遍历，实际遍历: %v", visited)
+	}
+}
+
+func TestDI_ForEachInOrderCycle(t *testing.T) {
+	for i := 0; i < 5; i++ {
+		di := New[TestContext]()
+		di.SetCtx(&TestContext{Config: "test"})
+
+		// app -> (b <-> c) -> base，a 没有依赖
+		provideCycle(di, "app", "c")
+		provideCycle(di, "c", "b")
+		Provide(di, "b", func(ctx *TestContext) *ServiceA {
+			MustMake[TestContext, ServiceA](di, "c")
+			MustMake[TestContext, ServiceA](di, "base")
+			return &ServiceA{Name: "b"}
+		})
+		Provide(di, "base", func(ctx *TestContext) *ServiceA {
+			return &ServiceA{Name: "base"}
+		})
+		Provide(di, "a", func(ctx *TestContext) *ServiceA {
+			return &ServiceA{Name: "a"}
+		})
+		if err := di.Build(); err != nil {
+			t.Fatalf("构建失败: %v", err)
+		}
+
+		visited := []string{}
+		di.ForEachInOrder(func(name string, instance any) error {
+			visited = append(visited, name)
+			return nil
+		})
+		if !equalSlices(visited, []string{"a", "base", "b", "c", "app"}) {
+			t.Errorf("遍历顺序错误: %v", visited)
+		}
+
+		visited = visited[:0]
+		di.ForEachInReverseOrder(func(name string, instance any) error {
+			visited = append(visited, name)
+			return nil
+		})
+		if !equalSlices(visited, []string{"app", "c", "b", "base", "a"}) {
+			t.Errorf("逆序遍历顺序错误: %v", visited)
+		}
+	}
+}
+
+func TestDI_ForEachInOrderCompacted(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	for _, compact := range []func() error{di.CompactBuilders, di.CompactGraph} {
+		if err := compact(); err != nil {
+			t.Fatalf("压缩失败: %v", err)
+		}
+		visited := []string{}
+		di.ForEachInOrder(func(name string, instance any) error {
+			visited = append(visited, name)
+			return nil
+		})
+		if !equalSlices(visited, []string{"serviceA", "serviceB"}) {
+			t.Errorf("压缩后遍历顺序错误: %v", visited)
+		}
+	}
+}
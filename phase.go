@@ -0,0 +1,60 @@
+package weave
+
+import "sync/atomic"
+
+// Phase 服务解析发生的阶段
+type Phase uint8
+
+const (
+	// PhaseBuilding 构建阶段，由 builder 发起的解析
+	PhaseBuilding Phase = iota
+	// PhaseReady Ready 回调阶段
+	PhaseReady
+	// PhaseRuntime 构建完成后的运行阶段
+	PhaseRuntime
+)
+
+// ReadyNodePrefix Ready 阶段的依赖边以 "ready:回调名称" 作为发起方
+// 并发执行的回调无法区分发起方，统一记为 "ready:*"
+const ReadyNodePrefix = "ready:"
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseBuilding:
+		return "building"
+	case PhaseReady:
+		return "ready"
+	case PhaseRuntime:
+		return "runtime"
+	default:
+		return "unknown"
+	}
+}
+
+// Phase 返回容器当前所处的阶段
+func (s *Weave[T]) Phase() Phase {
+	return Phase(atomic.LoadUint32(&s.phase))
+}
+
+// setPhase 切换容器所处的阶段
+func (s *Weave[T]) setPhase(p Phase) {
+	atomic.StoreUint32(&s.phase, uint32(p))
+}
+
+// PhaseGraph 返回只包含指定阶段依赖边的依赖图谱
+// GetDependencyGraph 只包含构建阶段的边；Ready 阶段的边以 ReadyNodePrefix 开头的名称作为发起方
+// 运行阶段的解析没有发起方，不会记录依赖边
+func (s *Weave[T]) PhaseGraph(phases ...Phase) *DependencyGraph {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	graph := s.dependencyGraph()
+	graph.Dependencies, graph.Dependents = s.edges.materializePhases(s.entries.Keys(), phases...)
+	graph.External = make(map[string]bool)
+	for name := range graph.Dependents {
+		if _, ok := graph.Dependencies[name]; !ok {
+			graph.External[name] = true
+		}
+	}
+	return graph
+}
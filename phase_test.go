@@ -0,0 +1,64 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDI_PhaseEdges(t *testing.T) {
+	di := New[TestContext](WithBuildTrace(100))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+	var phase Phase
+	di.ReadyNamed("warmup", func() {
+		phase = di.Phase()
+		MustMake[TestContext, ServiceB](di, "serviceB")
+	})
+
+	if p := di.Phase(); p != PhaseBuilding {
+		t.Errorf("构建前应处于 building 阶段，实际为 %s", p)
+	}
+	if done, err := di.BuildIncremental(time.Hour); !done || err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if phase != PhaseReady {
+		t.Errorf("Ready 回调中应处于 ready 阶段，实际为 %s", phase)
+	}
+	if p := di.Phase(); p != PhaseRuntime {
+		t.Errorf("构建完成后应处于 runtime 阶段，实际为 %s", p)
+	}
+
+	// 默认图谱不包含 Ready 阶段的边
+	graph := di.GetDependencyGraph()
+	if ds := graph.Dependents["serviceB"]; len(ds) != 0 {
+		t.Errorf("构建阶段的图谱不应该包含 Ready 回调的解析: %v", ds)
+	}
+	if _, ok := graph.Dependencies["ready:warmup"]; ok {
+		t.Error("构建阶段的图谱不应该包含 Ready 回调节点")
+	}
+
+	ready := di.PhaseGraph(PhaseReady)
+	if deps := ready.Dependencies["ready:warmup"]; len(deps) != 1 || deps[0] != "serviceB" {
+		t.Errorf("Ready 阶段的边应该以回调为发起方: %v", deps)
+	}
+	if deps := ready.Dependencies["serviceB"]; len(deps) != 0 {
+		t.Errorf("Ready 阶段的图谱不应该包含构建阶段的边: %v", deps)
+	}
+	all := di.PhaseGraph(PhaseBuilding, PhaseReady)
+	if ds := all.Dependents["serviceB"]; len(ds) != 1 || ds[0] != "ready:warmup" {
+		t.Errorf("合并的图谱应该同时包含两个阶段的边: %v", ds)
+	}
+
+	var b strings.Builder
+	di.FormatTrace(&b)
+	if !strings.Contains(b.String(), "[ready] ready:warmup resolve serviceB") {
+		t.Errorf("构建记录应该标明 Ready 阶段的解析:\n%s", b.String())
+	}
+}
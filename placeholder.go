@@ -0,0 +1,33 @@
+package weave
+
+import "reflect"
+
+// placeholder 返回服务的实例，延迟分配模式下尚未构建完成的服务在此时分配占位符
+// 接口服务不分配占位符，未构建时返回带类型的 nil 指针
+func (s *Weave[T]) placeholder(e *entry[*T]) any {
+	if e.iface == nil && isNilPointer(e.instance) {
+		e.instance = reflect.New(reflect.TypeOf(e.instance).Elem()).Interface()
+	}
+	return e.instance
+}
+
+// isNilPointer 判断是否为带类型的 nil 指针
+func isNilPointer(v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// PlaceholderResolutions 返回构建时以未完成的占位符形式拿到依赖的服务 -> 这些依赖，列表已排序
+// 占位符在其服务构建完成前没有内容，builder 若在构建期间读取了它的字段，读到的是零值；
+// 开启 WithCycleErrors 时不会交出占位符，结果为空
+func (s *Weave[T]) PlaceholderResolutions() map[string][]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string][]string)
+	s.placeholderUses.Range(func(name string, deps []string) bool {
+		result[name] = sortedCopy(deps)
+		return true
+	})
+	return result
+}
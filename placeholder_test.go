@@ -0,0 +1,111 @@
+package weave
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDI_LazyPlaceholders(t *testing.T) {
+	di := New[TestContext](WithLazyPlaceholders())
+	di.SetCtx(&TestContext{Config: "test"})
+
+	var builtA *ServiceA
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		builtA = &ServiceA{Name: "ServiceA"}
+		return builtA
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+	// 循环依赖：cycleA -> cycleB -> cycleA
+	Provide(di, "cycleA", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceB](di, "cycleB")
+		return &ServiceA{Name: "cycleA"}
+	})
+	Provide(di, "cycleB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "cycleB", ServiceA: MustMake[TestContext, ServiceA](di, "cycleA")}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	a := MustMake[TestContext, ServiceA](di, "serviceA")
+	if a != builtA {
+		t.Error("未被循环引用的服务应该直接保存 builder 返回的实例")
+	}
+	if b := MustMake[TestContext, ServiceB](di, "serviceB"); b.ServiceA != a {
+		t.Error("依赖注入的实例应该与容器中的实例相同")
+	}
+
+	cycleA := MustMake[TestContext, ServiceA](di, "cycleA")
+	if cycleB := MustMake[TestContext, ServiceB](di, "cycleB"); cycleB.ServiceA != cycleA || cycleA.Name != "cycleA" {
+		t.Error("循环依赖中的占位符应该在构建后填充")
+	}
+}
+
+func TestDI_LazyPlaceholdersBeforeBuild(t *testing.T) {
+	di := New[TestContext](WithLazyPlaceholders())
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+
+	// 构建前获取的引用应该在构建后可用
+	early := MustMake[TestContext, ServiceA](di, "serviceA")
+	if early == nil {
+		t.Fatal("构建前应该返回占位符")
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if early.Name != "ServiceA" || MustMake[TestContext, ServiceA](di, "serviceA") != early {
+		t.Error("构建前获取的占位符应该被填充")
+	}
+}
+
+func TestDI_PlaceholderResolutions(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	// app -> cycleA -> cycleB -> cycleA，cycleB 拿到的 cycleA 尚未构建完成
+	Provide(di, "app", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "app", ServiceA: MustMake[TestContext, ServiceA](di, "cycleA")}
+	})
+	Provide(di, "cycleA", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceB](di, "cycleB")
+		return &ServiceA{Name: "cycleA"}
+	})
+	Provide(di, "cycleB", func(ctx *TestContext) *ServiceB {
+		a := MustMake[TestContext, ServiceA](di, "cycleA")
+		MustMake[TestContext, ServiceA](di, "cycleA")
+		return &ServiceB{Name: "cycleB", ServiceA: a}
+	})
+	if done, err := di.BuildIncremental(time.Hour); !done || err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	want := map[string][]string{"cycleB": {"cycleA"}}
+	if got := di.PlaceholderResolutions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("占位符解析记录错误: %v", got)
+	}
+
+	di = New[TestContext](WithCycleErrors())
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "cycleA", func(ctx *TestContext) *ServiceA {
+		TryMake[TestContext, ServiceA](di, "cycleB")
+		return &ServiceA{Name: "cycleA"}
+	})
+	Provide(di, "cycleB", func(ctx *TestContext) *ServiceA {
+		TryMake[TestContext, ServiceA](di, "cycleA")
+		return &ServiceA{Name: "cycleB"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if got := di.PlaceholderResolutions(); len(got) != 0 {
+		t.Errorf("WithCycleErrors 时不应该交出占位符: %v", got)
+	}
+}
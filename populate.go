@@ -0,0 +1,106 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PopulateInto 将已构建的服务填充到聚合结构体的字段中
+// 字段按 `weave:"name"` 标签匹配服务，没有标签时依次尝试字段名及首字母小写的字段名；
+// `weave:"-"` 的字段和未导出的字段被忽略，`weave:"name,optional"` 的字段找不到服务时保持原值，
+// 其余字段找不到已构建的服务或类型不符时返回错误，此时 target 不会被修改
+// 不持有容器锁，可在 builder 中调用，此时与 MakeGroup 一样构建尚未构建的服务并记录依赖关系
+func PopulateInto[T any, S any](di *Weave[T], target *S) error {
+	if target == nil {
+		return fmt.Errorf("populate target is nil")
+	}
+	v := reflect.ValueOf(target).Elem()
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("populate target %T is not a struct pointer", target)
+	}
+
+	type assignment struct {
+		index int
+		value reflect.Value
+	}
+	var assignments []assignment
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		tag, tagged := f.Tag.Lookup(autowireTag)
+		if tag == "-" || (!tagged && f.PkgPath != "") {
+			continue
+		}
+		if f.PkgPath != "" {
+			return fmt.Errorf("populate field %s is unexported", f.Name)
+		}
+
+		candidates := []string{f.Name, lowerFirst(f.Name)}
+		optional := false
+		if tagged && tag != "" {
+			parts := strings.Split(tag, ",")
+			candidates = parts[:1]
+			for _, opt := range parts[1:] {
+				optional = optional || opt == "optional"
+			}
+		}
+
+		instance, name, found, err := di.builtInstance(candidates)
+		if err != nil {
+			return fmt.Errorf("populate field %s: %w", f.Name, err)
+		}
+		if !found {
+			if optional {
+				continue
+			}
+			return fmt.Errorf("populate field %s: no built service named [%s]", f.Name, strings.Join(candidates, "] or ["))
+		}
+
+		value := reflect.ValueOf(instance)
+		if !value.IsValid() {
+			return fmt.Errorf("populate field %s: service [%s] is nil", f.Name, name)
+		}
+		if value.Kind() == reflect.Ptr && f.Type.Kind() == reflect.Func && value.Type().Elem() == f.Type {
+			// 函数类型的服务保存在 *F 中，接口服务保存的已经是接口中的值
+			value = value.Elem()
+		}
+		if !value.Type().AssignableTo(f.Type) {
+			return fmt.Errorf("populate field %s (%s) cannot be assigned service [%s] (%s)", f.Name, f.Type, name, value.Type())
+		}
+		assignments = append(assignments, assignment{index: i, value: value})
+	}
+
+	for _, a := range assignments {
+		v.Field(a.index).Set(a.value)
+	}
+	return nil
+}
+
+// builtInstance 按候选名称顺序查找第一个已构建的服务，在 builder 中调用时未构建的服务会被构建
+func (s *Weave[T]) builtInstance(candidates []string) (any, string, bool, error) {
+	// builder 执行期间 optionalHook 不为 nil，见 TryMake
+	building := s.optionalHook != nil
+	for _, name := range candidates {
+		current, e, err := s.lookup(name)
+		if err != nil || e.released || s.skipped.Contains(current) || (!e.built && !building) {
+			continue
+		}
+		instance, err := s.GetService(current)
+		if err != nil {
+			if s.skipped.Contains(current) {
+				continue
+			}
+			return nil, current, false, err
+		}
+		return instance, current, true, nil
+	}
+	return nil, "", false, nil
+}
+
+// lowerFirst 将首字母转换为小写
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
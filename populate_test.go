@@ -0,0 +1,137 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type appServices struct {
+	ServiceA *ServiceA
+	Users    *ServiceB `weave:"serviceB"`
+	Metrics  *ServiceC `weave:"metrics,optional"`
+	Greet    func(string) string
+	Ignored  *ServiceA `weave:"-"`
+	internal *ServiceA
+}
+
+func TestDI_PopulateInto(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+	ProvideFunc(di, "greet", func(ctx *TestContext) func(string) string {
+		return strings.ToUpper
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	var app appServices
+	if err := PopulateInto(di, &app); err != nil {
+		t.Fatalf("填充失败: %v", err)
+	}
+	if app.ServiceA != MustMake[TestContext, ServiceA](di, "serviceA") {
+		t.Error("应该按首字母小写的字段名匹配服务")
+	}
+	if app.Users == nil || app.Users.Name != "ServiceB" {
+		t.Error("应该按标签匹配服务")
+	}
+	if app.Metrics != nil || app.Ignored != nil || app.internal != nil {
+		t.Error("可选、忽略和未导出的字段不应该被填充")
+	}
+	if app.Greet == nil || app.Greet("a") != "A" {
+		t.Error("函数类型的字段应该被填充")
+	}
+}
+
+func TestDI_PopulateIntoMissing(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	var app appServices
+	err := PopulateInto(di, &app)
+	if err == nil || !strings.Contains(err.Error(), "field Users") {
+		t.Fatalf("缺少必需的服务时应该报错: %v", err)
+	}
+	if app.ServiceA != nil {
+		t.Error("出错时不应该修改目标结构体")
+	}
+
+	var wrong struct {
+		ServiceA *ServiceB
+	}
+	if err := PopulateInto(di, &wrong); err == nil || !strings.Contains(err.Error(), "cannot be assigned") {
+		t.Errorf("类型不符时应该报错: %v", err)
+	}
+}
+
+func TestDI_PopulateIntoInterfaceAndValue(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	ProvideInterface(di, "greeter", func(ctx *TestContext) greeter {
+		return englishGreeter{name: "weave"}
+	})
+	_ = di.Mount("ext.", &stubResolver{services: map[string]any{"port": 8080}})
+	Provide(di, "app", func(ctx *TestContext) *ServiceA {
+		// 在 builder 中调用不应该死锁，未构建的服务会被构建
+		var deps struct {
+			Greeter greeter `weave:"greeter"`
+		}
+		if err := PopulateInto(di, &deps); err != nil {
+			panic(err)
+		}
+		return &ServiceA{Name: deps.Greeter.Greet()}
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- di.Build()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("构建失败: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("在 builder 中调用 PopulateInto 不应该死锁")
+	}
+	if MustMake[TestContext, ServiceA](di, "app").Name != "hello weave" {
+		t.Error("builder 中应该填充接口类型的字段")
+	}
+	if !containsString(di.GetDependencyGraph().Dependencies["app"], "greeter") {
+		t.Error("builder 中填充字段应该记录依赖关系")
+	}
+
+	var target struct {
+		Greeter greeter   `weave:"greeter"`
+		Port    int       `weave:"ext.port"`
+		Wrong   *ServiceA `weave:"ext.port,optional"`
+	}
+	err := PopulateInto(di, &target)
+	if err == nil || !strings.Contains(err.Error(), "field Wrong (*weave.ServiceA) cannot be assigned service [ext.port] (int)") {
+		t.Fatalf("非指针服务类型不符时应该返回错误而不是 panic: %v", err)
+	}
+	var ok struct {
+		Greeter greeter `weave:"greeter"`
+		Port    int     `weave:"ext.port"`
+	}
+	if err := PopulateInto(di, &ok); err != nil {
+		t.Fatalf("填充失败: %v", err)
+	}
+	if ok.Greeter == nil || ok.Greeter.Greet() != "hello weave" || ok.Port != 8080 {
+		t.Errorf("接口与非指针服务应该被填充: %+v", ok)
+	}
+}
@@ -0,0 +1,51 @@
+package weave
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NamesWithPrefix 返回名称以 prefix 开头的所有已注册服务，已排序
+// 与 Group 一样不持有容器锁，可在 builder 中调用
+func (s *Weave[T]) NamesWithPrefix(prefix string) []string {
+	names := s.localNamesWithPrefix(prefix)
+	if names == nil {
+		names = []string{}
+	}
+	return names
+}
+
+// CountWithPrefix 返回名称以 prefix 开头的已注册服务数量，不分配名称列表
+func (s *Weave[T]) CountWithPrefix(prefix string) int {
+	return s.entries.CountFunc(func(name string, _ *entry[*T]) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
+
+// MakeByPrefix 获取名称以 prefix 开头的所有已构建服务
+// 在 builder 中调用时与 MakeGroup 一样构建尚未构建的服务并记录依赖关系；
+// 构建之外调用时未构建的服务会被忽略；被跳过或禁用的服务会被忽略，其余服务获取失败或类型不符时 panic
+func MakeByPrefix[T any, R any](di *Weave[T], prefix string) map[string]*R {
+	// builder 执行期间 optionalHook 不为 nil，见 TryMake
+	building := di.optionalHook != nil
+	result := make(map[string]*R)
+	for _, name := range di.localNamesWithPrefix(prefix) {
+		e, ok := di.entries.Get(name)
+		if !ok || e.released || di.disabled.Contains(name) || (!e.built && !building) {
+			continue
+		}
+		obj, err := di.GetService(name)
+		if err != nil {
+			if _, skipped := di.skipped.Get(name); skipped {
+				continue
+			}
+			panic(err)
+		}
+		r, ok := obj.(*R)
+		if !ok {
+			panic(&ErrTypeMismatch{Name: name, Want: fmt.Sprintf("%T", r), Got: fmt.Sprintf("%T", obj)})
+		}
+		result[name] = r
+	}
+	return result
+}
@@ -0,0 +1,94 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDI_MakeByPrefix(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	for _, name := range []string{"handler.user", "handler.order", "handler.admin"} {
+		name := name
+		Provide(di, name, func(ctx *TestContext) *ServiceA {
+			return &ServiceA{Name: name}
+		})
+	}
+	Provide(di, "handlerRegistry", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "registry"}
+	})
+
+	if got := strings.Join(di.NamesWithPrefix("handler."), ","); got != "handler.admin,handler.order,handler.user" {
+		t.Errorf("前缀匹配结果错误: %s", got)
+	}
+	if names := di.NamesWithPrefix("missing."); names == nil || len(names) != 0 {
+		t.Errorf("没有匹配时应该返回空切片: %v", names)
+	}
+
+	if n := di.CountWithPrefix("handler."); n != 3 {
+		t.Errorf("前缀计数错误: %d", n)
+	}
+	if n := di.CountWithPrefix("missing."); n != 0 {
+		t.Errorf("没有匹配时计数应该为 0: %d", n)
+	}
+
+	if handlers := MakeByPrefix[TestContext, ServiceA](di, "handler."); len(handlers) != 0 {
+		t.Errorf("构建前不应该返回未构建的服务: %v", handlers)
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	handlers := MakeByPrefix[TestContext, ServiceA](di, "handler.")
+	if len(handlers) != 3 || handlers["handler.user"].Name != "handler.user" {
+		t.Errorf("按前缀获取服务错误: %v", handlers)
+	}
+
+	defer func() {
+		var mismatch *ErrTypeMismatch
+		if err, _ := recover().(error); !errors.As(err, &mismatch) {
+			t.Errorf("类型不符时应该以 *ErrTypeMismatch panic，实际为 %v", err)
+		}
+	}()
+	MakeByPrefix[TestContext, ServiceA](di, "handler")
+}
+
+func TestDI_MakeByPrefixInBuilder(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	for _, name := range []string{"handler.user", "handler.order"} {
+		name := name
+		Provide(di, name, func(ctx *TestContext) *ServiceA {
+			return &ServiceA{Name: name}
+		})
+	}
+	var names []string
+	var handlers map[string]*ServiceA
+	Provide(di, "router", func(ctx *TestContext) *ServiceB {
+		names = di.NamesWithPrefix("handler.")
+		handlers = MakeByPrefix[TestContext, ServiceA](di, "handler.")
+		return &ServiceB{Name: "router"}
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- di.Build() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("构建失败: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("在 builder 中按前缀获取服务不应该死锁")
+	}
+
+	if len(names) != 2 || len(handlers) != 2 || handlers["handler.user"].Name != "handler.user" {
+		t.Errorf("builder 中按前缀获取服务错误: %v %v", names, handlers)
+	}
+	if !equalSlices(di.DirectDependencies("router"), []string{"handler.order", "handler.user"}) {
+		t.Errorf("应该记录依赖关系: %v", di.DirectDependencies("router"))
+	}
+}
@@ -0,0 +1,33 @@
+package weave
+
+// ProvideForProfile 注册仅在指定 profile 下启用的服务
+// profiles 为空时等同于 Provide；当前 profile 未启用时解析该服务会返回错误
+func ProvideForProfile[T any, R any](di *Weave[T], name string, profiles []string, builder func(*T) *R) {
+	if !di.profileActive(profiles) {
+		di.mu.Lock()
+		defer di.mu.Unlock()
+		if !di.entries.Contains(name) {
+			di.inactive.Set(name, append([]string(nil), profiles...))
+		}
+		return
+	}
+	Provide(di, name, builder)
+}
+
+// ActiveProfile 返回当前激活的 profile
+func (s *Weave[T]) ActiveProfile() string {
+	return s.opts.activeProfile
+}
+
+// profileActive 判断 profile 列表是否包含当前激活的 profile
+func (s *Weave[T]) profileActive(profiles []string) bool {
+	if len(profiles) == 0 {
+		return true
+	}
+	for _, p := range profiles {
+		if p == s.opts.activeProfile {
+			return true
+		}
+	}
+	return false
+}
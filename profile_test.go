@@ -0,0 +1,53 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDI_ProvideForProfile(t *testing.T) {
+	di := New[TestContext](WithActiveProfile("prod"))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	ProvideForProfile(di, "database", []string{"dev"}, func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "SQLite"}
+	})
+	ProvideForProfile(di, "database", []string{"staging", "prod"}, func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "PostgreSQL"}
+	})
+	ProvideForProfile(di, "database", []string{"test"}, func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "Memory"}
+	})
+	ProvideForProfile(di, "debugger", []string{"dev"}, func(ctx *TestContext) *ServiceB {
+		t.Error("dev profile的服务不应该被构建")
+		return &ServiceB{Name: "Debugger"}
+	})
+	ProvideForProfile(di, "userService", nil, func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "UserService", ServiceA: MustMake[TestContext, ServiceA](di, "database")}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if di.ActiveProfile() != "prod" {
+		t.Errorf("期望激活的profile为 'prod'，实际为 '%s'", di.ActiveProfile())
+	}
+	if db := MustMake[TestContext, ServiceA](di, "database"); db.Name != "PostgreSQL" {
+		t.Errorf("prod profile下应该使用PostgreSQL，实际为 '%s'", db.Name)
+	}
+	if svc := MustMake[TestContext, ServiceB](di, "userService"); svc.ServiceA.Name != "PostgreSQL" {
+		t.Errorf("未指定profile的服务应该被构建并注入PostgreSQL")
+	}
+
+	_, err := di.GetService("debugger")
+	if err == nil || !strings.Contains(err.Error(), "disabled for profile [prod]") {
+		t.Errorf("解析未启用的服务应该报告profile禁用，实际为: %v", err)
+	}
+	if _, err := di.GetService("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("不存在的服务应该报告not found，实际为: %v", err)
+	}
+	if _, ok := di.GetDependencyGraph().Dependencies["debugger"]; ok {
+		t.Error("未启用的服务不应该出现在依赖图谱中")
+	}
+}
@@ -0,0 +1,200 @@
+package weave
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// readyHook 构建完成后执行的回调
+type readyHook struct {
+	name  string
+	after []string
+	fn    func()
+	// 本轮构建中已执行成功，构建失败后重试时跳过
+	done bool
+}
+
+// ReadyError Ready 回调发生的 panic，按回调名称汇总
+type ReadyError struct {
+	Errors map[string]error
+}
+
+func (e *ReadyError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("ready hook [%s]: %v", name, e.Errors[name]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ReadyNamed 注册具名的 Ready 回调，回调只会在 after 中列出的回调全部执行完后开始
+func (s *Weave[T]) ReadyNamed(name string, fn func(), after ...string) {
+	s.ready = append(s.ready, readyHook{name: name, after: after, fn: fn})
+}
+
+// hookName 返回回调名称，未命名的回调以注册序号命名
+func (h readyHook) hookName(i int) string {
+	if h.name != "" {
+		return h.name
+	}
+	return fmt.Sprintf("#%d", i)
+}
+
+// readyOrder 按依赖关系对回调排序，依赖相同时保持注册顺序
+func (s *Weave[T]) readyOrder() ([]int, error) {
+	index := make(map[string]int, len(s.ready))
+	for i, h := range s.ready {
+		if h.name == "" {
+			continue
+		}
+		if _, ok := index[h.name]; ok {
+			return nil, fmt.Errorf("ready hook [%s] is registered twice", h.name)
+		}
+		index[h.name] = i
+	}
+
+	pending := make([]int, len(s.ready))
+	next := make([][]int, len(s.ready))
+	for i, h := range s.ready {
+		for _, dep := range h.after {
+			j, ok := index[dep]
+			if !ok {
+				return nil, fmt.Errorf("ready hook [%s] waits for unknown hook [%s]", h.hookName(i), dep)
+			}
+			pending[i]++
+			next[j] = append(next[j], i)
+		}
+	}
+
+	order := make([]int, 0, len(s.ready))
+	done := make([]bool, len(s.ready))
+	for len(order) < len(s.ready) {
+		progressed := false
+		for i := range s.ready {
+			if done[i] || pending[i] > 0 {
+				continue
+			}
+			done[i] = true
+			progressed = true
+			order = append(order, i)
+			for _, j := range next[i] {
+				pending[j]--
+			}
+		}
+		if !progressed {
+			return nil, fmt.Errorf("ready hooks have circular ordering constraints")
+		}
+	}
+	return order, nil
+}
+
+// runReady 执行所有 Ready 回调
+func (s *Weave[T]) runReady(order []int) error {
+	if s.opts.readyConcurrency <= 0 {
+		return s.runReadySerially(order)
+	}
+	return s.runReadyConcurrently(order)
+}
+
+// runReadySerially 依次执行回调，回调 panic 时停止并返回 *ReadyError
+// 开启 WithCollectErrors 时继续执行其余回调并汇总错误
+// 服务已全部构建完成，回调失败时容器回到未构建状态，下次 Build 只重新执行未成功的回调
+func (s *Weave[T]) runReadySerially(order []int) error {
+	errs := make(map[string]error)
+	for _, i := range order {
+		if s.ready[i].done {
+			continue
+		}
+		s.readyRequester = ReadyNodePrefix + s.ready[i].hookName(i)
+		if err := s.ready[i].call(); err != nil {
+			errs[s.ready[i].hookName(i)] = err
+			if !s.opts.collectErrors {
+				break
+			}
+			continue
+		}
+		s.ready[i].done = true
+	}
+	if len(errs) > 0 {
+		return &ReadyError{Errors: errs}
+	}
+	return nil
+}
+
+// resetReady 清除回调的执行记录，下一轮构建重新执行全部回调
+func (s *Weave[T]) resetReady() {
+	for i := range s.ready {
+		s.ready[i].done = false
+	}
+}
+
+// call 执行回调并将 panic 转换为错误
+func (h readyHook) call() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	h.fn()
+	return nil
+}
+
+// runReadyConcurrently 使用工作池并发执行回调，每个回调单独 recover
+func (s *Weave[T]) runReadyConcurrently(order []int) error {
+	s.readyRequester = ReadyNodePrefix + "*"
+	index := make(map[string]int, len(s.ready))
+	for i, h := range s.ready {
+		if h.name != "" {
+			index[h.name] = i
+		}
+	}
+	finished := make([]chan struct{}, len(s.ready))
+	for i := range finished {
+		finished[i] = make(chan struct{})
+	}
+
+	sem := make(chan struct{}, s.opts.readyConcurrency)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(map[string]error)
+
+	for _, i := range order {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer close(finished[i])
+
+			h := s.ready[i]
+			if h.done {
+				return
+			}
+			for _, dep := range h.after {
+				<-finished[index[dep]]
+			}
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := h.call(); err != nil {
+				mu.Lock()
+				errs[h.hookName(i)] = err
+				mu.Unlock()
+				return
+			}
+			s.ready[i].done = true
+		}(i)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return &ReadyError{Errors: errs}
+	}
+	return nil
+}
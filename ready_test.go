@@ -0,0 +1,170 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDI_ConcurrentReady(t *testing.T) {
+	di := New[TestContext](WithAllowEmpty(), WithConcurrentReady(4))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	for i := 0; i < 4; i++ {
+		di.Ready(func() { time.Sleep(50 * time.Millisecond) })
+	}
+
+	start := time.Now()
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 150*time.Millisecond {
+		t.Errorf("Ready 回调应该并发执行，实际耗时 %v", elapsed)
+	}
+}
+
+func TestDI_ReadyNamedOrder(t *testing.T) {
+	for _, opts := range [][]Option{{WithAllowEmpty()}, {WithAllowEmpty(), WithConcurrentReady(4)}} {
+		di := New[TestContext](opts...)
+		di.SetCtx(&TestContext{Config: "test"})
+
+		var mu sync.Mutex
+		var order []string
+		record := func(name string) func() {
+			return func() {
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				order = append(order, name)
+				mu.Unlock()
+			}
+		}
+		di.ReadyNamed("server", record("server"), "db", "cache")
+		di.ReadyNamed("db", record("db"))
+		di.ReadyNamed("cache", record("cache"), "db")
+
+		if err := di.Build(); err != nil {
+			t.Fatalf("构建失败: %v", err)
+		}
+		if got := strings.Join(order, ","); got != "db,cache,server" {
+			t.Errorf("Ready 回调顺序错误: %s", got)
+		}
+	}
+}
+
+func TestDI_ConcurrentReadyPanics(t *testing.T) {
+	di := New[TestContext](WithAllowEmpty(), WithConcurrentReady(2))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	ran := false
+	di.ReadyNamed("db", func() { panic("connection refused") })
+	di.ReadyNamed("server", func() { ran = true }, "db")
+	di.Ready(func() { panic("boom") })
+
+	err := di.Build()
+	var readyErr *ReadyError
+	if !errors.As(err, &readyErr) {
+		t.Fatalf("应该返回 *ReadyError，实际为 %v", err)
+	}
+	if len(readyErr.Errors) != 2 {
+		t.Errorf("应该汇总 2 个错误，实际为 %v", readyErr.Errors)
+	}
+	if _, ok := readyErr.Errors["db"]; !ok {
+		t.Errorf("错误应该以回调名称标识: %v", readyErr)
+	}
+	if !ran {
+		t.Error("前置回调失败后，后续回调仍应该执行")
+	}
+}
+
+func TestDI_ReadyNamedInvalid(t *testing.T) {
+	di := New[TestContext](WithAllowEmpty())
+	di.SetCtx(&TestContext{Config: "test"})
+	di.ReadyNamed("server", func() {}, "db")
+	if err := di.Build(); err == nil || !strings.Contains(err.Error(), "unknown hook [db]") {
+		t.Errorf("未知的前置回调应该报错，实际为 %v", err)
+	}
+
+	di = New[TestContext](WithAllowEmpty())
+	di.SetCtx(&TestContext{Config: "test"})
+	di.ReadyNamed("a", func() {}, "b")
+	di.ReadyNamed("b", func() {}, "a")
+	if err := di.Build(); err == nil || !strings.Contains(err.Error(), "circular") {
+		t.Errorf("循环的前置回调应该报错，实际为 %v", err)
+	}
+}
+
+func TestDI_ReadyPanic(t *testing.T) {
+	for _, collect := range []bool{false, true} {
+		opts := []Option{WithAllowEmpty()}
+		if collect {
+			opts = append(opts, WithCollectErrors())
+		}
+		di := New[TestContext](opts...)
+		di.SetCtx(&TestContext{Config: "test"})
+
+		ran := false
+		di.ReadyNamed("first", func() { panic("boom") })
+		di.ReadyNamed("second", func() { ran = true })
+
+		err := di.Build()
+		var readyErr *ReadyError
+		if !errors.As(err, &readyErr) {
+			t.Fatalf("回调 panic 时应该返回 *ReadyError，实际为 %v", err)
+		}
+		if !strings.Contains(readyErr.Error(), "ready hook [first]: panic: boom") {
+			t.Errorf("错误应该包含回调名称与 panic 内容: %v", readyErr)
+		}
+		if ran != collect {
+			t.Errorf("collect=%v 时后续回调是否执行应为 %v", collect, collect)
+		}
+
+		// 锁应该已经释放
+		if _, ok := di.Info("missing"); ok {
+			t.Error("不存在的服务不应该有信息")
+		}
+	}
+}
+
+func TestDI_ReadyFailureRetry(t *testing.T) {
+	for _, concurrency := range []int{0, 2} {
+		opts := []Option{WithAllowEmpty(), WithCollectErrors()}
+		if concurrency > 0 {
+			opts = append(opts, WithConcurrentReady(concurrency))
+		}
+		di := New[TestContext](opts...)
+		di.SetCtx(&TestContext{Config: "test"})
+
+		fail := true
+		var dbRuns, serverRuns int32
+		di.ReadyNamed("db", func() { atomic.AddInt32(&dbRuns, 1) })
+		di.ReadyNamed("server", func() {
+			atomic.AddInt32(&serverRuns, 1)
+			if fail {
+				panic("port in use")
+			}
+		}, "db")
+
+		if err := di.Build(); err == nil {
+			t.Fatal("回调失败时构建应该返回错误")
+		}
+		generation := di.Generation()
+
+		// 再次构建不应该是空操作，只重试失败的回调
+		fail = false
+		if err := di.Build(); err != nil {
+			t.Fatalf("重试构建失败: %v", err)
+		}
+		if di.LastBuildNoop() {
+			t.Error("回调失败后再次构建不应该是空操作")
+		}
+		if dbRuns != 1 || serverRuns != 2 {
+			t.Errorf("concurrency=%d 时成功的回调不应该重复执行: db=%d server=%d", concurrency, dbRuns, serverRuns)
+		}
+		if di.Generation() != generation+1 {
+			t.Errorf("重试成功后应该进入下一代，实际为 %d -> %d", generation, di.Generation())
+		}
+	}
+}
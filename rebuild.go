@@ -0,0 +1,253 @@
+package weave
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// rebuildState 重新构建前的服务状态，失败时用于恢复
+type rebuildState[T any] struct {
+	builder  func(T) any
+	instance any
+	value    reflect.Value
+	deps     []string
+	ctxGen   uint64
+}
+
+// savedValue 复制占位符中的值，重新构建失败时写回；直接保存实例的服务恢复 instance 即可
+func savedValue[T any](e *entry[T]) reflect.Value {
+	if e.direct || isNilPointer(e.instance) {
+		return reflect.Value{}
+	}
+	value := reflect.New(reflect.TypeOf(e.instance).Elem()).Elem()
+	value.Set(reflect.ValueOf(e.instance).Elem())
+	return value
+}
+
+// ReplaceBuilder 替换服务的 builder
+// 服务尚未构建时只替换 builder；已构建时立即使用新 builder 重新构建该服务，
+// rebuildDependents 为 true 时按依赖顺序一并重新构建直接或间接依赖它的服务，其余服务保持不变。
+// 重新构建的结果复制到原实例指针中，已持有指针的服务会看到新值，每个重新构建的服务产生一个替换事件；
+// 需要重新构建的服务的 builder 或依赖关系已被压缩释放时返回 *CompactError，
+// 重新构建失败时恢复所有服务的 builder、实例与依赖关系；
+// 需要重新构建的服务已通过 Extract 提取时，未开启 WithAllowLiveMutation 返回 *ErrLiveMutation
+func (s *Weave[T]) ReplaceBuilder(name string, builder func(*T) any, rebuildDependents bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries.Get(name)
+	if !ok {
+		return s.notFound(name)
+	}
+	if builder == nil {
+		return fmt.Errorf("service [%s] replacement builder is nil", name)
+	}
+	if e.released {
+		return &ErrBuildOnly{Name: name}
+	}
+	if e.bridge != nil {
+		return fmt.Errorf("service [%s] is bridged from another weave and has no builder", name)
+	}
+	typ := e.instanceType()
+	replacement := func(ctx *T) any {
+		instance := builder(ctx)
+		if instance == nil || isNilPointer(instance) {
+			return nil
+		}
+		if got := reflect.TypeOf(instance); !e.accepts(instance) {
+			panic(&ErrTypeMismatch{Name: name, Want: typ.String(), Got: got.String()})
+		}
+		return instance
+	}
+	if !e.built {
+		e.builder = replacement
+		return nil
+	}
+
+	if s.compacted&compactedCtx != 0 {
+		return &CompactError{Op: fmt.Sprintf("rebuild service [%s]", name), Released: "context"}
+	}
+	names := []string{name}
+	if rebuildDependents {
+		if s.compacted&compactedGraph != 0 {
+			return &CompactError{Op: fmt.Sprintf("rebuild dependents of service [%s]", name), Released: "dependency graph"}
+		}
+		names = append(names, s.transitiveDependents(name)...)
+	}
+	rebuild := make(map[string]bool, len(names))
+	for _, n := range names {
+		rebuild[n] = true
+		if n == name {
+			continue
+		}
+		dep, _ := s.entries.Get(n)
+		if dep.released {
+			return &ErrBuildOnly{Name: n}
+		}
+		if dep.builder == nil || dep.bridge != nil {
+			return &CompactError{Op: fmt.Sprintf("rebuild service [%s]", n), Released: "builder"}
+		}
+	}
+	order := make([]string, 0, len(names))
+	for _, n := range s.topologicalNames() {
+		if rebuild[n] {
+			order = append(order, n)
+		}
+	}
+	if err := s.checkLiveMutation("rebuild", order); err != nil {
+		return err
+	}
+
+	// 保存原状态后标记为未构建
+	saved := make(map[string]rebuildState[*T], len(order))
+	for _, n := range order {
+		dep, _ := s.entries.Get(n)
+		saved[n] = rebuildState[*T]{builder: dep.builder, instance: dep.instance, value: savedValue(dep), deps: s.edges.dependsOn(n), ctxGen: dep.ctxGen}
+
+		dep.built = false
+		s.setBuilt(n, false)
+		s.edges.removeFrom(n)
+		if dep.direct {
+			dep.instance = dep.unbuiltInstance()
+		}
+	}
+	e.builder = replacement
+
+	phase := s.Phase()
+	s.setPhase(PhaseBuilding)
+	defer s.setPhase(phase)
+
+	for _, n := range order {
+		dep, _ := s.entries.Get(n)
+		if err := s.build(n, dep); err != nil {
+			s.restoreRebuild(saved)
+			return err
+		}
+	}
+
+	s.generation++
+	if s.opts.onReplaced != nil {
+		for _, n := range order {
+			s.opts.onReplaced(ReplaceEvent{Services: []string{n}})
+		}
+	}
+	return nil
+}
+
+// transitiveDependents 返回直接或间接依赖该服务的服务，按发现顺序
+func (s *Weave[T]) transitiveDependents(name string) []string {
+	seen := map[string]bool{name: true}
+	result := []string{}
+	queue := []string{name}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dependent := range s.edges.dependents(current) {
+			if seen[dependent] || !s.entries.Contains(dependent) {
+				continue
+			}
+			seen[dependent] = true
+			result = append(result, dependent)
+			queue = append(queue, dependent)
+		}
+	}
+	return result
+}
+
+// restoreRebuild 恢复重新构建前的服务状态
+func (s *Weave[T]) restoreRebuild(saved map[string]rebuildState[*T]) {
+	for n, state := range saved {
+		e, _ := s.entries.Get(n)
+		e.builder = state.builder
+		if e.direct || isNilPointer(e.instance) {
+			e.instance = state.instance
+		} else {
+			reflect.ValueOf(e.instance).Elem().Set(state.value)
+		}
+		s.edges.removeFrom(n)
+		for _, dep := range state.deps {
+			s.edges.add(n, dep)
+		}
+		e.built = true
+		e.ctxGen = state.ctxGen
+		s.setBuilt(n, true)
+	}
+}
+
+// RebuildIfChanged 用于配置热加载：equal 判断 newCtx 与当前上下文相同时不做任何事并返回 false，
+// 否则切换到 newCtx，重新构建所有通过 builder 构建的服务并返回 true；equal 为 nil 时使用 reflect.DeepEqual。
+// 已构建的容器再次 SetCtx + Build 是空操作，需要基于新上下文重建时使用该方法。
+// 重新构建的结果复制到原实例指针中，ProvideValue 注册、Seed 提供的实例与 UseExternal 桥接的服务保持不变；
+// builder 已被压缩释放或存在已释放的仅构建期服务时返回错误且不修改容器；
+// 需要重新构建的服务已通过 Extract 提取时，未开启 WithAllowLiveMutation 返回 *ErrLiveMutation；
+// 重新构建失败时恢复原上下文及所有服务的实例与依赖关系，返回 true 与该错误；
+// 每次实际重新构建都会与 Build 一样重新执行所有 Ready 回调，回调需要能够重复执行
+func (s *Weave[T]) RebuildIfChanged(newCtx *T, equal func(a, b *T) bool) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if newCtx == nil {
+		return false, errors.New("build context is nil")
+	}
+	if equal == nil {
+		equal = func(a, b *T) bool { return reflect.DeepEqual(a, b) }
+	}
+	if s.ctx != nil && equal(s.ctx, newCtx) {
+		return false, nil
+	}
+	if s.compacted&(compactedBuilders|compactedCtx) != 0 {
+		return false, &CompactError{Op: "rebuild with a new context", Released: "builders"}
+	}
+
+	names := []string{}
+	for _, n := range s.topologicalNames() {
+		e, _ := s.entries.Get(n)
+		if e.released {
+			return false, &ErrBuildOnly{Name: n}
+		}
+		if e.built && e.builder != nil && e.bridge == nil && !e.seeded {
+			names = append(names, n)
+		}
+	}
+	if err := s.checkLiveMutation("rebuild", names); err != nil {
+		return false, err
+	}
+
+	saved := make(map[string]rebuildState[*T], len(names))
+	for _, n := range names {
+		e, _ := s.entries.Get(n)
+		saved[n] = rebuildState[*T]{builder: e.builder, instance: e.instance, value: savedValue(e), deps: s.edges.dependsOn(n), ctxGen: e.ctxGen}
+
+		e.built = false
+		s.setBuilt(n, false)
+		s.edges.removeFrom(n)
+		if e.direct {
+			e.instance = e.unbuiltInstance()
+		}
+	}
+	oldCtx, oldGen, oldBuilt := s.ctx, s.ctxGen, s.built
+	s.SetCtx(newCtx)
+	s.built = false
+	s.resetBuilt()
+
+	atomic.AddUint32(&s.buildCount, 1)
+	if err := s.buildLocked(); err != nil {
+		// 回滚本次构建新增的服务，再恢复原有服务
+		for _, n := range s.entries.Keys() {
+			if _, ok := saved[n]; ok {
+				continue
+			}
+			if e, _ := s.entries.Get(n); e.built && e.builder != nil && e.ctxGen == s.ctxGen {
+				e.built = false
+				s.setBuilt(n, false)
+			}
+		}
+		s.restoreRebuild(saved)
+		s.ctx, s.ctxGen, s.built = oldCtx, oldGen, oldBuilt
+		s.resetReady()
+		return true, err
+	}
+	return true, nil
+}
@@ -0,0 +1,231 @@
+package weave
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDI_ReplaceBuilder(t *testing.T) {
+	var events []string
+	di := New[TestContext](WithReplaceHandler(func(e ReplaceEvent) {
+		events = append(events, e.Services...)
+	}))
+	di.SetCtx(&TestContext{Config: "test"})
+	// config -> db -> repo -> api <- cache，logger 独立
+	builds := provideGraph(di, "config", "db: config", "repo: db", "cache", "api: repo cache", "logger")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	repo := MustMake[TestContext, ServiceA](di, "repo")
+
+	err := di.ReplaceBuilder("db", func(ctx *TestContext) any {
+		return &ServiceA{Name: "db2(" + MustMake[TestContext, ServiceA](di, "config").Name + ")"}
+	}, true)
+	if err != nil {
+		t.Fatalf("替换 builder 失败: %v", err)
+	}
+
+	if !reflect.DeepEqual(events, []string{"db", "repo", "api"}) {
+		t.Errorf("应该按依赖顺序只重新构建 db 及其依赖方，实际为 %v", events)
+	}
+	want := map[string]int{"config": 1, "db": 1, "repo": 2, "cache": 1, "api": 2, "logger": 1}
+	if !reflect.DeepEqual(builds, want) {
+		t.Errorf("builder 调用次数错误: %v", builds)
+	}
+	if got := MustMake[TestContext, ServiceA](di, "api").Name; got != "api(repo(db2(config)))(cache)" {
+		t.Errorf("依赖方应该使用新的实例: %s", got)
+	}
+	if repo.Name != "repo(db2(config))" {
+		t.Error("已持有的实例指针应该看到新值")
+	}
+	if deps := di.DirectDependencies("repo"); !reflect.DeepEqual(deps, []string{"db"}) {
+		t.Errorf("重新构建不应该重复记录依赖: %v", deps)
+	}
+}
+
+func TestDI_ReplaceBuilderOnly(t *testing.T) {
+	var events []string
+	di := New[TestContext](WithReplaceHandler(func(e ReplaceEvent) {
+		events = append(events, e.Services...)
+	}))
+	di.SetCtx(&TestContext{Config: "test"})
+	// config -> db -> repo -> api <- cache，logger 独立
+	builds := provideGraph(di, "config", "db: config", "repo: db", "cache", "api: repo cache", "logger")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	err := di.ReplaceBuilder("db", func(ctx *TestContext) any { return &ServiceA{Name: "db2"} }, false)
+	if err != nil {
+		t.Fatalf("替换 builder 失败: %v", err)
+	}
+	if !reflect.DeepEqual(events, []string{"db"}) || builds["repo"] != 1 {
+		t.Errorf("不重新构建依赖方时只应该重新构建 db: %v %v", events, builds)
+	}
+	if MustMake[TestContext, ServiceA](di, "repo").Name != "repo(db(config))" {
+		t.Error("未重新构建的依赖方应该保持原值")
+	}
+}
+
+func TestDI_ReplaceBuilderFailure(t *testing.T) {
+	var events []string
+	di := New[TestContext](WithReplaceHandler(func(e ReplaceEvent) {
+		events = append(events, e.Services...)
+	}))
+	di.SetCtx(&TestContext{Config: "test"})
+	// config -> db -> repo -> api <- cache，logger 独立
+	provideGraph(di, "config", "db: config", "repo: db", "cache", "api: repo cache", "logger")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	repo := MustMake[TestContext, ServiceA](di, "repo")
+
+	if err := di.ReplaceBuilder("db", func(ctx *TestContext) any { return (*ServiceA)(nil) }, true); err == nil {
+		t.Fatal("新 builder 失败时应该返回错误")
+	}
+	if len(events) != 0 {
+		t.Errorf("失败时不应该产生事件: %v", events)
+	}
+	if repo.Name != "repo(db(config))" || MustMake[TestContext, ServiceA](di, "db").Name != "db(config)" {
+		t.Error("失败时应该恢复原实例")
+	}
+	if info, _ := di.Info("db"); !info.Built {
+		t.Error("失败时服务应该保持已构建")
+	}
+
+	var mismatch *ErrTypeMismatch
+	err := di.ReplaceBuilder("db", func(ctx *TestContext) any { return &ServiceB{} }, false)
+	if !errors.As(err, &mismatch) {
+		t.Errorf("新 builder 返回的类型不同时应该返回 ErrTypeMismatch，实际为 %v", err)
+	}
+}
+
+func TestDI_ReplaceBuilderCompacted(t *testing.T) {
+	var events []string
+	di := New[TestContext](WithReplaceHandler(func(e ReplaceEvent) {
+		events = append(events, e.Services...)
+	}))
+	di.SetCtx(&TestContext{Config: "test"})
+	// config -> db -> repo -> api <- cache，logger 独立
+	provideGraph(di, "config", "db: config", "repo: db", "cache", "api: repo cache", "logger")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if err := di.CompactBuilders(); err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+
+	var compactErr *CompactError
+	err := di.ReplaceBuilder("db", func(ctx *TestContext) any { return &ServiceA{} }, true)
+	if !errors.As(err, &compactErr) || compactErr.Released != "builder" {
+		t.Errorf("依赖方的 builder 已释放时应该返回 *CompactError，实际为 %v", err)
+	}
+	if err := di.ReplaceBuilder("db", func(ctx *TestContext) any { return &ServiceA{Name: "db2"} }, false); err != nil {
+		t.Errorf("只重新构建服务自身时不需要依赖方的 builder: %v", err)
+	}
+}
+
+func TestDI_RebuildIfChanged(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "v1"})
+	builds := 0
+	Provide(di, "config", func(ctx *TestContext) *ServiceA {
+		builds++
+		if ctx.Config == "broken" {
+			panic(&ErrNotBuilt{Name: "source"})
+		}
+		return &ServiceA{Name: ctx.Config}
+	})
+	Provide(di, "server", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "server", ServiceA: MustMake[TestContext, ServiceA](di, "config")}
+	})
+	ProvideInstance(di, "static", &ServiceC{Name: "static"})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	config := MustMake[TestContext, ServiceA](di, "config")
+	equal := func(a, b *TestContext) bool { return a.Config == b.Config }
+
+	changed, err := di.RebuildIfChanged(&TestContext{Config: "v1"}, equal)
+	if err != nil || changed || builds != 1 {
+		t.Errorf("上下文未变化时不应该重新构建: changed=%v err=%v builds=%d", changed, err, builds)
+	}
+
+	changed, err = di.RebuildIfChanged(&TestContext{Config: "v2"}, equal)
+	if err != nil || !changed {
+		t.Fatalf("上下文变化时应该重新构建: changed=%v err=%v", changed, err)
+	}
+	if builds != 2 || config.Name != "v2" {
+		t.Errorf("重新构建的结果应该写入原实例指针: builds=%d name=%s", builds, config.Name)
+	}
+	if server := MustMake[TestContext, ServiceB](di, "server"); server.ServiceA != config {
+		t.Error("依赖方应该继续持有原实例指针")
+	}
+	if MustMake[TestContext, ServiceC](di, "static").Name != "static" {
+		t.Error("ProvideInstance 注册的实例不应该被重新构建")
+	}
+
+	// 重新构建失败时恢复原上下文与实例
+	gen := di.CtxGeneration()
+	changed, err = di.RebuildIfChanged(&TestContext{Config: "broken"}, equal)
+	if !changed || err == nil {
+		t.Fatalf("重新构建失败时应该返回错误: changed=%v err=%v", changed, err)
+	}
+	if config.Name != "v2" || di.CtxGeneration() != gen {
+		t.Errorf("失败后应该恢复原状态: name=%s gen=%d", config.Name, di.CtxGeneration())
+	}
+	if _, err := di.GetService("config"); err != nil {
+		t.Errorf("失败后服务应该仍可获取: %v", err)
+	}
+
+	if _, err := di.RebuildIfChanged(nil, equal); err == nil {
+		t.Error("上下文为 nil 时应该返回错误")
+	}
+}
+
+func TestDI_RebuildIfChangedExtracted(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "v1"})
+	Provide(di, "config", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: ctx.Config}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	di.Extract()
+
+	var live *ErrLiveMutation
+	changed, err := di.RebuildIfChanged(&TestContext{Config: "v2"}, func(a, b *TestContext) bool { return a.Config == b.Config })
+	if changed || !errors.As(err, &live) {
+		t.Errorf("已提取的服务应该拒绝重新构建: changed=%v err=%v", changed, err)
+	}
+}
+
+func TestDI_RebuildIfChangedDefaultEqual(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "v1"})
+	builds := 0
+	Provide(di, "config", func(ctx *TestContext) *ServiceA {
+		builds++
+		return &ServiceA{Name: ctx.Config}
+	})
+	readyRuns := 0
+	di.Ready(func() { readyRuns++ })
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	// equal 为 nil 时按 reflect.DeepEqual 比较
+	changed, err := di.RebuildIfChanged(&TestContext{Config: "v1"}, nil)
+	if err != nil || changed || builds != 1 {
+		t.Errorf("内容相同的上下文不应该重新构建: changed=%v err=%v builds=%d", changed, err, builds)
+	}
+	changed, err = di.RebuildIfChanged(&TestContext{Config: "v2"}, nil)
+	if err != nil || !changed || builds != 2 {
+		t.Errorf("内容不同的上下文应该重新构建: changed=%v err=%v builds=%d", changed, err, builds)
+	}
+	if readyRuns != 2 {
+		t.Errorf("每次重新构建都应该重新执行 Ready 回调，实际执行 %d 次", readyRuns)
+	}
+}
@@ -0,0 +1,117 @@
+package weave
+
+import (
+	"fmt"
+	"log"
+	"sort"
+)
+
+// DeprecationEvent 通过旧名称解析已重命名服务时产生的弃用事件
+type DeprecationEvent struct {
+	// OldName 调用方使用的旧名称
+	OldName string
+	// NewName 服务当前的名称
+	NewName string
+	// Caller 调用方位置（file:line）
+	Caller string
+}
+
+func (e DeprecationEvent) String() string {
+	return fmt.Sprintf("service [%s] is deprecated, use [%s] instead (called at %s)", e.OldName, e.NewName, e.Caller)
+}
+
+// logDeprecation 默认的弃用事件处理函数
+func logDeprecation(e DeprecationEvent) {
+	log.Printf("weave: %s", e)
+}
+
+// Rename 重命名服务，旧名称保留转发，解析旧名称时会产生弃用事件
+// 开启 WithStrictRenames 后，通过旧名称解析将返回错误
+func (s *Weave[T]) Rename(oldName, newName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if oldName == newName {
+		return fmt.Errorf("service [%s] cannot be renamed to itself", oldName)
+	}
+	e, ok := s.entries.Get(oldName)
+	if !ok {
+		return fmt.Errorf("service [%s] not found", oldName)
+	}
+	if s.entries.Contains(newName) {
+		return fmt.Errorf("service [%s] already exists", newName)
+	}
+
+	s.entries.Delete(oldName)
+	s.entries.Set(newName, e)
+	s.setBuilt(oldName, false)
+	s.setBuilt(newName, e.built)
+	if names, ok := s.values.Get(instanceAddr(e.instance)); ok {
+		for i, n := range names {
+			if n == oldName {
+				names[i] = newName
+			}
+		}
+	}
+
+	// 新名称可能曾经是某个服务的旧名称，此时不再转发
+	s.renames.Delete(newName)
+
+	// 指向旧名称的转发改为指向新名称
+	for _, from := range s.RenamedFrom(oldName) {
+		s.renames.Set(from, newName)
+	}
+	s.renames.Set(oldName, newName)
+
+	// 更新已记录的依赖关系
+	s.edges.rename(oldName, newName)
+	for i, n := range s.buildOrder {
+		if n == oldName {
+			s.buildOrder[i] = newName
+		}
+	}
+
+	// 按名称记录的状态随服务一起迁移
+	moveKey(s.disabled, oldName, newName)
+	moveKey(s.skipped, oldName, newName)
+	moveKey(s.checkFailures, oldName, newName)
+	moveKey(s.exports, oldName, newName)
+	moveKey(s.placeholderUses, oldName, newName)
+	moveKey(s.optionalDeps, oldName, newName)
+	s.placeholderUses.Range(func(_ string, deps []string) bool {
+		for i, dep := range deps {
+			if dep == oldName {
+				deps[i] = newName
+			}
+		}
+		return true
+	})
+	s.optionalDeps.Range(func(_ string, deps map[string]bool) bool {
+		if present, ok := deps[oldName]; ok {
+			delete(deps, oldName)
+			deps[newName] = present
+		}
+		return true
+	})
+	return nil
+}
+
+// moveKey 将旧名称下的记录移到新名称下
+func moveKey[V any](m *Map[string, V], oldName, newName string) {
+	if value, ok := m.Pop(oldName); ok {
+		m.Set(newName, value)
+	}
+}
+
+// RenamedFrom 返回服务的所有旧名称
+func (s *Weave[T]) RenamedFrom(name string) []string {
+	var olds []string
+	s.renames.Range(func(from, to string) bool {
+		if to == name {
+			olds = append(olds, from)
+		}
+		return true
+	})
+	sort.Strings(olds)
+	return olds
+}
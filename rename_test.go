@@ -0,0 +1,187 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDI_Rename(t *testing.T) {
+	var events []DeprecationEvent
+	di := New[TestContext](WithDeprecationHandler(func(e DeprecationEvent) {
+		events = append(events, e)
+	}))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if err := di.Rename("serviceA", "database"); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+	if err := di.Rename("missing", "other"); err == nil {
+		t.Error("重命名不存在的服务应该返回错误")
+	}
+	if err := di.Rename("serviceB", "database"); err == nil {
+		t.Error("重命名为已存在的名称应该返回错误")
+	}
+
+	// 旧名称仍可解析，并产生弃用事件
+	serviceA := MustMake[TestContext, ServiceA](di, "serviceA")
+	if serviceA.Name != "ServiceA" {
+		t.Errorf("期望服务名称为 'ServiceA'，实际为 '%s'", serviceA.Name)
+	}
+	if len(events) != 1 {
+		t.Fatalf("期望产生1个弃用事件，实际为 %d", len(events))
+	}
+	if events[0].OldName != "serviceA" || events[0].NewName != "database" {
+		t.Errorf("弃用事件内容错误: %+v", events[0])
+	}
+	if !strings.Contains(events[0].Caller, "rename_test.go:") {
+		t.Errorf("弃用事件应该包含调用方位置，实际为 '%s'", events[0].Caller)
+	}
+
+	// 新名称解析不产生弃用事件
+	MustMake[TestContext, ServiceA](di, "database")
+	if len(events) != 1 {
+		t.Errorf("通过新名称解析不应该产生弃用事件")
+	}
+
+	// 依赖关系同步更新
+	graph := di.GetDependencyGraph()
+	if !equalSlices(graph.Dependencies["serviceB"], []string{"database"}) {
+		t.Errorf("serviceB的依赖应该更新为database，实际为: %v", graph.Dependencies["serviceB"])
+	}
+	if !equalSlices(graph.Renamed["database"], []string{"serviceA"}) {
+		t.Errorf("database应该记录旧名称serviceA，实际为: %v", graph.Renamed["database"])
+	}
+
+	dot := di.GenerateDOTGraph()
+	if !strings.Contains(dot, "database (原 serviceA)") {
+		t.Errorf("DOT图应该同时显示新旧名称:\n%s", dot)
+	}
+
+	// 连续重命名时，最早的旧名称也转发到最新名称
+	if err := di.Rename("database", "db"); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+	if _, ok := TryMake[TestContext, ServiceA](di, "serviceA"); !ok {
+		t.Error("最早的旧名称应该转发到最新名称")
+	}
+	if !equalSlices(di.RenamedFrom("db"), []string{"database", "serviceA"}) {
+		t.Errorf("db的旧名称错误: %v", di.RenamedFrom("db"))
+	}
+}
+
+func TestDI_RenameReregisterOldName(t *testing.T) {
+	var events []DeprecationEvent
+	di := New[TestContext](WithDeprecationHandler(func(e DeprecationEvent) {
+		events = append(events, e)
+	}))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "renamed"}
+	})
+	if err := di.Rename("serviceA", "database"); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "registered"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if name := MustMake[TestContext, ServiceA](di, "serviceA").Name; name != "registered" {
+		t.Errorf("重新注册的旧名称应该解析到新注册的服务，实际为 '%s'", name)
+	}
+	if len(events) != 0 {
+		t.Errorf("重新注册的旧名称不应该再转发: %+v", events)
+	}
+	if olds := di.RenamedFrom("database"); len(olds) != 0 {
+		t.Errorf("database不应该再记录旧名称serviceA: %v", olds)
+	}
+
+	// 再次重命名时，已重新注册的名称不应该被改为转发
+	if err := di.Rename("database", "db"); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+	if name := MustMake[TestContext, ServiceA](di, "serviceA").Name; name != "registered" {
+		t.Errorf("再次重命名后旧名称仍应该解析到新注册的服务，实际为 '%s'", name)
+	}
+}
+
+func TestDI_RenameMovesState(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "A"}
+	})
+	ProvideOptional(di, "metrics", func(ctx *TestContext) *ServiceB {
+		return nil
+	})
+	Provide(di, "app", func(ctx *TestContext) *ServiceC {
+		TryMake[TestContext, ServiceB](di, "metrics")
+		return &ServiceC{Name: "app"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if err := di.Disable("serviceA"); err != nil {
+		t.Fatalf("禁用失败: %v", err)
+	}
+
+	if err := di.Rename("serviceA", "database"); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+	if err := di.Rename("metrics", "telemetry"); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+
+	if !di.Disabled("database") || di.Disabled("serviceA") {
+		t.Error("禁用状态应该随服务迁移到新名称")
+	}
+	skipped := di.SkippedServices()
+	if _, ok := skipped["telemetry"]; !ok {
+		t.Errorf("被跳过的可选服务应该以新名称记录: %v", skipped)
+	}
+	if _, ok := skipped["metrics"]; ok {
+		t.Errorf("旧名称不应该再记录为被跳过: %v", skipped)
+	}
+	optional := di.GetDependencyGraph().Optional["app"]
+	if present, ok := optional["telemetry"]; len(optional) != 1 || !ok || present {
+		t.Errorf("可选依赖应该更新为新名称: %v", optional)
+	}
+}
+
+func TestDI_StrictRenames(t *testing.T) {
+	di := New[TestContext](WithStrictRenames())
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	if err := di.Rename("serviceA", "database"); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	_, err := di.GetService("serviceA")
+	if err == nil || !strings.Contains(err.Error(), "renamed to [database]") {
+		t.Errorf("严格模式下通过旧名称解析应该返回重命名错误，实际为: %v", err)
+	}
+	if _, err := di.GetService("database"); err != nil {
+		t.Errorf("新名称应该可以解析: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package weave
+
+import "fmt"
+
+// SetResolver 替换服务解析函数，主要用于单独测试某个 builder
+// 设置后 GetService 及构建期间的依赖解析都会交给 fn 处理，传入 nil 恢复默认解析
+// 只能在 Build 之前调用
+func (s *Weave[T]) SetResolver(fn func(name string) (any, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.built {
+		return fmt.Errorf("cannot set resolver after Build() is called")
+	}
+	s.resolver = fn
+	return nil
+}
+
+// ServiceResolver 按名称解析服务的最小接口，是与其他框架集成的稳定入口
+// *Weave[T] 与 Extract 得到的注册表都满足该接口，第三方解析器也可通过 AdaptResolver 接入，
+// 并与 MustResolve、TryResolve 等类型化工具函数配合使用
+type ServiceResolver interface {
+	Resolve(name string) (any, error)
+}
+
+// Resolve 实现 ServiceResolver
+func (s *Weave[T]) Resolve(name string) (any, error) {
+	return s.GetService(name)
+}
+
+// ResolverFunc 将函数适配为 ServiceResolver
+type ResolverFunc func(name string) (any, error)
+
+// Resolve 实现 ServiceResolver
+func (f ResolverFunc) Resolve(name string) (any, error) {
+	return f(name)
+}
+
+// AdaptResolver 将任意解析函数包装为 ServiceResolver
+func AdaptResolver(fn func(name string) (any, error)) ServiceResolver {
+	return ResolverFunc(fn)
+}
+
+// MustResolve 通过任意 ServiceResolver 获取服务，失败时 panic
+func MustResolve[R any](r ServiceResolver, name string) *R {
+	obj, err := r.Resolve(name)
+	if err != nil {
+		panic(err)
+	}
+	result, ok := obj.(*R)
+	if !ok {
+		panic(&ErrTypeMismatch{Name: name, Want: fmt.Sprintf("%T", result), Got: fmt.Sprintf("%T", obj)})
+	}
+	return result
+}
+
+// optionalRecorder 可记录可选依赖解析结果的解析器，*Weave[T] 在构建期间将其记录到依赖图谱中
+type optionalRecorder interface {
+	recordOptional(name string, present bool)
+}
+
+// TryResolve 通过任意 ServiceResolver 获取服务
+// r 为 *Weave[T] 时与 TryMake 相同，在 builder 中调用会记录可选依赖
+func TryResolve[R any](r ServiceResolver, name string) (*R, bool) {
+	result, ok := tryResolve[R](r, name)
+	if recorder, isRecorder := r.(optionalRecorder); isRecorder {
+		recorder.recordOptional(name, ok)
+	}
+	return result, ok
+}
+
+func tryResolve[R any](r ServiceResolver, name string) (*R, bool) {
+	obj, err := r.Resolve(name)
+	if err != nil {
+		return nil, false
+	}
+	result, ok := obj.(*R)
+	return result, ok
+}
+
+// ResolveOr 通过任意 ServiceResolver 获取服务，服务不存在或构建失败时返回 fallback
+func ResolveOr[R any](r ServiceResolver, name string, fallback *R) *R {
+	if result, ok := TryResolve[R](r, name); ok {
+		return result
+	}
+	return fallback
+}
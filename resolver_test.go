@@ -0,0 +1,81 @@
+package weave
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDI_SetResolver(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	fake := &ServiceA{Name: "FakeA"}
+	err := di.SetResolver(func(name string) (any, error) {
+		if name == "serviceA" {
+			return fake, nil
+		}
+		return nil, errors.New("unexpected " + name)
+	})
+	if err != nil {
+		t.Fatalf("设置解析函数失败: %v", err)
+	}
+
+	// 只注册被测服务，依赖由解析函数提供
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	// 构建完成后 GetService 同样被拦截，需恢复默认解析才能获取已注册服务
+	if _, err := di.GetService("serviceB"); err == nil {
+		t.Error("设置解析函数后GetService应该被拦截")
+	}
+	if err := di.SetResolver(nil); err == nil {
+		t.Error("Build之后不允许修改解析函数")
+	}
+
+	graph := di.GetDependencyGraph()
+	if !equalSlices(graph.Dependencies["serviceB"], []string{"serviceA"}) {
+		t.Errorf("通过解析函数获取的依赖也应该被记录，实际为: %v", graph.Dependencies["serviceB"])
+	}
+
+	registry := di.Extract()
+	serviceB := MustGetFromRegistry[ServiceB](registry, "serviceB")
+	if serviceB.ServiceA != fake {
+		t.Error("serviceB应该注入解析函数提供的假实现")
+	}
+}
+
+func TestDI_ServiceResolver(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	resolvers := map[string]ServiceResolver{
+		"weave":    di,
+		"registry": di.Extract(),
+		"adapted": AdaptResolver(func(name string) (any, error) {
+			return di.GetService(name)
+		}),
+	}
+	for kind, r := range resolvers {
+		if a := MustResolve[ServiceA](r, "serviceA"); a.Name != "ServiceA" {
+			t.Errorf("%s 解析结果错误: %v", kind, a)
+		}
+		if _, ok := TryResolve[ServiceA](r, "serviceX"); ok {
+			t.Errorf("%s 解析不存在的服务应该失败", kind)
+		}
+		if _, ok := TryResolve[ServiceB](r, "serviceA"); ok {
+			t.Errorf("%s 类型不符时应该失败", kind)
+		}
+	}
+}
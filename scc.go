@@ -0,0 +1,318 @@
+package weave
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StronglyConnectedComponents 使用 Tarjan 算法计算依赖图的强连通分量
+// 节点数大于 1 的分量即为一组相互依赖的服务（循环簇），单节点分量无循环
+// 每个分量内部按名称排序，分量之间按首个名称排序
+func (s *Weave[T]) StronglyConnectedComponents() [][]string {
+	graph := s.GetDependencyGraph()
+	return stronglyConnectedComponents(graph.Dependencies)
+}
+
+// stronglyConnectedComponents Tarjan 算法，时间复杂度 O(V+E)
+func stronglyConnectedComponents(dependencies map[string][]string) [][]string {
+	nodes := make([]string, 0, len(dependencies))
+	for node := range dependencies {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	index := 0
+	indices := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	stack := []string{}
+	components := [][]string{}
+
+	var strongConnect func(string)
+	strongConnect = func(v string) {
+		indices[v] = index
+		lowlink[v] = index
+		index++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range dependencies[v] {
+			if _, visited := indices[w]; !visited {
+				strongConnect(w)
+				if lowlink[w] < lowlink[v] {
+					lowlink[v] = lowlink[w]
+				}
+			} else if onStack[w] && indices[w] < lowlink[v] {
+				lowlink[v] = indices[w]
+			}
+		}
+
+		if lowlink[v] == indices[v] {
+			component := []string{}
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, node := range nodes {
+		if _, visited := indices[node]; !visited {
+			strongConnect(node)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+	return components
+}
+
+// Levels 按拓扑层级对服务分组，第 0 层为没有依赖的服务，其余服务位于其依赖所在最深层级的下一层
+// 循环簇中的服务位于同一层，未注册的外部服务不参与分层；每层内部按名称排序
+func (s *Weave[T]) Levels() [][]string {
+	graph := s.GetDependencyGraph()
+	return dependencyLevels(graph.Dependencies)
+}
+
+// dependencyLevels 在强连通分量压缩后的无环图上计算最长路径层级
+func dependencyLevels(dependencies map[string][]string) [][]string {
+	components := stronglyConnectedComponents(dependencies)
+	componentOf := make(map[string]int, len(dependencies))
+	for i, component := range components {
+		for _, service := range component {
+			componentOf[service] = i
+		}
+	}
+
+	level := make([]int, len(components))
+	done := make([]bool, len(components))
+	var visit func(int) int
+	visit = func(i int) int {
+		if done[i] {
+			return level[i]
+		}
+		done[i] = true
+		for _, service := range components[i] {
+			for _, dep := range dependencies[service] {
+				if _, registered := dependencies[dep]; !registered {
+					continue
+				}
+				j := componentOf[dep]
+				if j == i {
+					continue
+				}
+				if l := visit(j) + 1; l > level[i] {
+					level[i] = l
+				}
+			}
+		}
+		return level[i]
+	}
+
+	levels := [][]string{}
+	for i, component := range components {
+		l := visit(i)
+		for len(levels) <= l {
+			levels = append(levels, []string{})
+		}
+		for _, service := range component {
+			// 外部服务不参与分层
+			if _, ok := dependencies[service]; ok {
+				levels[l] = append(levels[l], service)
+			}
+		}
+	}
+	for _, names := range levels {
+		sort.Strings(names)
+	}
+	return levels
+}
+
+// GenerateCondensedDOT 生成压缩后的DOT依赖图
+// 每个强连通分量折叠为一个节点，分量之间的边构成无环图，适合查看大型循环系统的整体结构
+func (s *Weave[T]) GenerateCondensedDOT() string {
+	graph := s.GetDependencyGraph()
+	components := stronglyConnectedComponents(graph.Dependencies)
+
+	componentOf := make(map[string]int)
+	for i, component := range components {
+		for _, service := range component {
+			componentOf[service] = i
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString("digraph CondensedDependencyGraph {\n")
+	builder.WriteString("  rankdir=TB;\n")
+	builder.WriteString("  node [shape=box, style=filled];\n")
+
+	builder.WriteString("\n  // 分量节点\n")
+	for i, component := range components {
+		labels := make([]string, len(component))
+		for j, service := range component {
+			labels[j] = graph.label(service)
+		}
+		if len(component) > 1 {
+			// 循环簇
+			builder.WriteString(fmt.Sprintf("  \"scc%d\" [fillcolor=lightcoral, label=\"🔁 %s\"];\n", i, strings.Join(labels, "\\n")))
+		} else {
+			builder.WriteString(fmt.Sprintf("  \"scc%d\" [fillcolor=lightblue, label=\"%s\"];\n", i, labels[0]))
+		}
+	}
+
+	builder.WriteString("\n  // 分量之间的依赖关系边\n")
+	seen := make(map[[2]int]bool)
+	for i, component := range components {
+		for _, service := range component {
+			for _, dep := range graph.Dependencies[service] {
+				from, ok := componentOf[dep]
+				if !ok || from == i {
+					continue
+				}
+				edge := [2]int{from, i}
+				if seen[edge] {
+					continue
+				}
+				seen[edge] = true
+				builder.WriteString(fmt.Sprintf("  \"scc%d\" -> \"scc%d\";\n", from, i))
+			}
+		}
+	}
+
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// GenerateCycleDOT 生成只包含循环依赖的DOT依赖图
+// 只保留参与循环的节点和构成循环的边，其余无环部分全部省略；没有循环时输出空图
+func (s *Weave[T]) GenerateCycleDOT() string {
+	graph := s.GetDependencyGraph()
+
+	// 循环中的边 From 依赖 To
+	cycleNodes := make(map[string]bool)
+	cycleEdges := make(map[GraphEdge]bool)
+	for _, r := range s.cycleReports(graph) {
+		for _, node := range r.Services {
+			cycleNodes[node] = true
+		}
+		for _, e := range r.Edges {
+			cycleEdges[e] = true
+		}
+	}
+
+	services := make([]string, 0, len(cycleNodes))
+	for service := range cycleNodes {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	var builder strings.Builder
+	builder.WriteString("digraph CycleGraph {\n")
+	builder.WriteString("  rankdir=TB;\n")
+	builder.WriteString("  node [shape=box, style=filled];\n")
+
+	builder.WriteString("\n  // 循环依赖节点\n")
+	for _, service := range services {
+		builder.WriteString(fmt.Sprintf("  \"%s\" [fillcolor=lightcoral, label=\"⚠️ %s\"];\n", service, graph.label(service)))
+	}
+
+	builder.WriteString("\n  // 循环依赖边\n")
+	for _, service := range services {
+		for _, dep := range uniqueStrings(graph.Dependencies[service]) {
+			if cycleEdges[GraphEdge{From: service, To: dep}] {
+				builder.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\" [color=red, penwidth=2.0, label=\"⚠️\"];\n", dep, service))
+			}
+		}
+	}
+
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// CycleReport 循环依赖报告
+type CycleReport struct {
+	// Fingerprint 循环的稳定指纹，见 CycleFingerprint
+	Fingerprint string
+	// Services 循环中的服务，从名称最小的服务开始按依赖方向排列
+	Services []string
+	// Length 循环中的服务数
+	Length int
+	// Edges 构成循环的依赖关系边
+	Edges []GraphEdge
+	// Roots 在循环外没有被依赖的服务，修复循环时可优先考虑
+	Roots []string
+	// Leaves 在循环外没有依赖的服务
+	Leaves []string
+}
+
+// CycleReports 返回所有循环依赖的结构化报告，按长度及服务名称排序
+func (s *Weave[T]) CycleReports() []CycleReport {
+	return s.cycleReports(s.GetDependencyGraph())
+}
+
+// cycleReports 生成图谱中所有循环依赖的报告
+func (s *Weave[T]) cycleReports(graph *DependencyGraph) []CycleReport {
+	reports := []CycleReport{}
+	seen := make(map[string]bool)
+	for _, cycle := range s.allCycles(graph) {
+		// 同一循环从不同节点发现时闭合点不同，按去掉闭合点后的服务序列去重
+		services := uniqueStrings(cycle)
+		key := strings.Join(services, "->")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		inCycle := make(map[string]bool, len(services))
+		for _, name := range services {
+			inCycle[name] = true
+		}
+
+		report := CycleReport{
+			Fingerprint: CycleFingerprint(services),
+			Services:    services,
+			Length:      len(services),
+			Edges:       make([]GraphEdge, 0, len(services)),
+			Roots:       []string{},
+			Leaves:      []string{},
+		}
+		for i, name := range services {
+			report.Edges = append(report.Edges, GraphEdge{From: name, To: services[(i+1)%len(services)]})
+			if !hasOutside(graph.Dependents[name], inCycle) {
+				report.Roots = append(report.Roots, name)
+			}
+			if !hasOutside(graph.Dependencies[name], inCycle) {
+				report.Leaves = append(report.Leaves, name)
+			}
+		}
+		sort.Strings(report.Roots)
+		sort.Strings(report.Leaves)
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Length != reports[j].Length {
+			return reports[i].Length < reports[j].Length
+		}
+		return strings.Join(reports[i].Services, ",") < strings.Join(reports[j].Services, ",")
+	})
+	return reports
+}
+
+// hasOutside 判断列表中是否有不在集合内的名称
+func hasOutside(names []string, set map[string]bool) bool {
+	for _, name := range names {
+		if !set[name] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,217 @@
+package weave
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDI_CycleReports(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	// app -> a -> b -> c -> a，c -> base
+	Provide(di, "app", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "a")
+		return &ServiceA{Name: "app"}
+	})
+	Provide(di, "a", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "b")
+		return &ServiceA{Name: "a"}
+	})
+	Provide(di, "b", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "c")
+		return &ServiceA{Name: "b"}
+	})
+	Provide(di, "c", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "a")
+		MustMake[TestContext, ServiceA](di, "base")
+		return &ServiceA{Name: "c"}
+	})
+	Provide(di, "base", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "base"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	reports := di.CycleReports()
+	if len(reports) != 1 {
+		t.Fatalf("应该发现 1 个循环，实际为 %+v", reports)
+	}
+	r := reports[0]
+	if r.Length != 3 || fmt.Sprint(r.Services) != "[a b c]" {
+		t.Errorf("循环中的服务错误: %+v", r)
+	}
+	if fmt.Sprint(r.Edges) != "[{a b} {b c} {c a}]" {
+		t.Errorf("循环中的边错误: %v", r.Edges)
+	}
+	if fmt.Sprint(r.Roots) != "[b c]" {
+		t.Errorf("循环外没有被依赖的服务应为 [b c]，实际为 %v", r.Roots)
+	}
+	if fmt.Sprint(r.Leaves) != "[a b]" {
+		t.Errorf("循环外没有依赖的服务应为 [a b]，实际为 %v", r.Leaves)
+	}
+}
+
+func TestDI_GenerateCycleDOT(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	// app -> a -> b -> a，b -> base
+	Provide(di, "app", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "a")
+		return &ServiceA{Name: "app"}
+	})
+	Provide(di, "a", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "b")
+		return &ServiceA{Name: "a"}
+	})
+	Provide(di, "b", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "a")
+		MustMake[TestContext, ServiceA](di, "base")
+		return &ServiceA{Name: "b"}
+	})
+	Provide(di, "base", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "base"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	want := `digraph CycleGraph {
+  rankdir=TB;
+  node [shape=box, style=filled];
+
+  // 循环依赖节点
+  "a" [fillcolor=lightcoral, label="⚠️ a"];
+  "b" [fillcolor=lightcoral, label="⚠️ b"];
+
+  // 循环依赖边
+  "b" -> "a" [color=red, penwidth=2.0, label="⚠️"];
+  "a" -> "b" [color=red, penwidth=2.0, label="⚠️"];
+}
+`
+	if got := di.GenerateCycleDOT(); got != want {
+		t.Errorf("循环子图错误:\n%s\n期望:\n%s", got, want)
+	}
+
+	acyclic := New[TestContext]()
+	Provide(acyclic, "base", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "base"}
+	})
+	if got := acyclic.GenerateCycleDOT(); strings.Contains(got, "base") {
+		t.Errorf("没有循环时不应该包含任何节点:\n%s", got)
+	}
+}
+
+func TestDI_GenerateCycleDOTLongCycle(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	// a -> b -> c -> a
+	provideCycle(di, "a", "b")
+	provideCycle(di, "b", "c")
+	provideCycle(di, "c", "a")
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	for _, dot := range []string{di.GenerateCycleDOT(), di.GenerateDOTGraph()} {
+		for _, edge := range []string{`"b" -> "a"`, `"c" -> "b"`, `"a" -> "c"`} {
+			if !strings.Contains(dot, edge+" [color=red, penwidth=2.0, label=\"⚠️\"]") {
+				t.Errorf("循环边 %s 应该高亮显示:\n%s", edge, dot)
+			}
+		}
+		if strings.Count(dot, "color=red") != 3 {
+			t.Errorf("应该只有 3 条高亮的循环边:\n%s", dot)
+		}
+	}
+}
+
+func TestDI_IsAcyclic(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "a", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "b")
+		return &ServiceA{Name: "a"}
+	})
+	Provide(di, "b", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "b"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if !di.acyclicValid {
+		t.Error("Build 完成时应该计算并缓存结果")
+	}
+	if !di.IsAcyclic() {
+		t.Error("无环图谱应该返回 true")
+	}
+	version := di.acyclicVersion
+	di.IsAcyclic()
+	if di.acyclicVersion != version {
+		t.Error("图谱未变化时不应该重新计算")
+	}
+
+	// 重新注册 b 使其依赖 a，形成循环
+	Provide(di, "b", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "a")
+		return &ServiceA{Name: "b"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if di.IsAcyclic() {
+		t.Error("图谱变化后应该重新计算，存在循环时返回 false")
+	}
+	if has, _ := di.HasCircularDependency(); !has {
+		t.Error("结果应该与 HasCircularDependency 一致")
+	}
+}
+
+func TestDI_Levels(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	// base <- a <-> b <- app，cfg <- app，a 依赖未注册的 ext
+	Provide(di, "base", func(ctx *TestContext) *ServiceA { return &ServiceA{Name: "base"} })
+	Provide(di, "cfg", func(ctx *TestContext) *ServiceA { return &ServiceA{Name: "cfg"} })
+	Provide(di, "a", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "base")
+		MustMake[TestContext, ServiceA](di, "b")
+		TryMake[TestContext, ServiceA](di, "ext")
+		return &ServiceA{Name: "a"}
+	})
+	Provide(di, "b", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "a")
+		return &ServiceA{Name: "b"}
+	})
+	Provide(di, "app", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "b")
+		MustMake[TestContext, ServiceA](di, "cfg")
+		return &ServiceA{Name: "app"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if got := fmt.Sprint(di.Levels()); got != "[[base cfg] [a b] [app]]" {
+		t.Errorf("拓扑层级错误: %s", got)
+	}
+
+	dot := di.GenerateDOTGraph(DOTRankByLevel())
+	for _, want := range []string{
+		`{ rank=same; "base"; "cfg"; }`,
+		`{ rank=same; "a"; "b"; }`,
+		`{ rank=same; "app"; }`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT 图应包含 %s:\n%s", want, dot)
+		}
+	}
+	if strings.Contains(di.GenerateDOTGraph(), "rank=same") {
+		t.Error("默认输出不应包含层级分组")
+	}
+}
@@ -0,0 +1,141 @@
+package weave
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Scope 作用域，作用域服务在每个作用域内各自构建一次，其余服务委托给根容器解析
+// 满足 Resolver 与 ServiceResolver，可与 MustResolve、TryResolve 配合使用
+type Scope[T any] struct {
+	root  *Weave[T]
+	state *scopeState[T]
+	// 当前作用域服务的构建栈，用于检测循环
+	path []string
+}
+
+type scopeState[T any] struct {
+	mu      sync.Mutex
+	deriver func(root *T) *T
+	derived bool
+	once    sync.Once
+	ctx     *T
+	cells   map[string]*scopedCell
+}
+
+// scopedCell 作用域服务在一个作用域内的实例，并发的首次解析只构建一次
+type scopedCell struct {
+	once     sync.Once
+	instance any
+	err      error
+}
+
+// ProvideScoped 注册作用域服务，根容器的 Build 不会构建该服务
+// builder 接收作用域的上下文（见 SetCtxDeriver）及作用域本身，通过作用域解析其他服务
+// 与 Provide 一样触发 WithRegisterHook 回调
+func ProvideScoped[T any, R any](di *Weave[T], name string, builder func(ctx *T, scope *Scope[T]) *R) {
+	di.mu.Lock()
+	defer di.mu.Unlock()
+
+	di.registerName(name)
+	di.scoped.Set(name, func(ctx *T, scope *Scope[T]) any {
+		// 避免 nil 指针被包装成非 nil 的 any
+		if instance := builder(ctx, scope); instance != nil {
+			return instance
+		}
+		return nil
+	})
+}
+
+// NewScope 创建新的作用域
+func (s *Weave[T]) NewScope() *Scope[T] {
+	return &Scope[T]{
+		root:  s,
+		state: &scopeState[T]{cells: make(map[string]*scopedCell)},
+	}
+}
+
+// SetCtxDeriver 设置作用域上下文的派生函数
+// 作用域在首次解析作用域服务时以根容器的上下文调用 fn 一次，作用域服务的 builder 接收派生的上下文；
+// 根容器的服务仍使用根上下文。上下文派生后不能再设置
+func (sc *Scope[T]) SetCtxDeriver(fn func(root *T) *T) error {
+	sc.state.mu.Lock()
+	defer sc.state.mu.Unlock()
+
+	if sc.state.derived {
+		return fmt.Errorf("cannot set context deriver after the scope context is derived")
+	}
+	sc.state.deriver = fn
+	return nil
+}
+
+// Ctx 返回作用域的上下文，首次调用时派生
+func (sc *Scope[T]) Ctx() *T {
+	sc.state.once.Do(func() {
+		sc.state.mu.Lock()
+		sc.state.derived = true
+		deriver := sc.state.deriver
+		sc.state.mu.Unlock()
+
+		sc.state.ctx = sc.root.ctx
+		if deriver != nil {
+			sc.state.ctx = deriver(sc.root.ctx)
+		}
+	})
+	return sc.state.ctx
+}
+
+// GetService 获取服务，作用域服务在本作用域内构建，其余服务由根容器解析
+// builder 的 panic 与根容器的处理方式相同，见 WithPanicRecovery；panic 继续抛出后该服务在本作用域内解析会返回错误
+func (sc *Scope[T]) GetService(name string) (any, error) {
+	builder, ok := sc.root.scoped.Get(name)
+	if !ok {
+		return sc.root.GetService(name)
+	}
+	for _, p := range sc.path {
+		if p == name {
+			path := append(append([]string{}, sc.path...), name)
+			return nil, &ErrResolutionInProgress{Name: name, Path: path}
+		}
+	}
+
+	sc.state.mu.Lock()
+	cell, ok := sc.state.cells[name]
+	if !ok {
+		cell = &scopedCell{}
+		sc.state.cells[name] = cell
+	}
+	sc.state.mu.Unlock()
+
+	cell.once.Do(func() {
+		child := &Scope[T]{
+			root:  sc.root,
+			state: sc.state,
+			path:  append(append([]string{}, sc.path...), name),
+		}
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if err, ok := r.(error); ok && isWeaveError(err) {
+				cell.err = fmt.Errorf("service [%s] build failed: %w", name, err)
+				return
+			}
+			cell.err = fmt.Errorf("service [%s] builder panicked: %v", name, r)
+			if !sc.root.opts.recoverPanics {
+				panic(r)
+			}
+		}()
+		cell.instance = builder(sc.Ctx(), child)
+		if cell.instance == nil {
+			cell.err = fmt.Errorf("service [%s] build failed", name)
+		}
+	})
+	return cell.instance, cell.err
+}
+
+// Resolve 实现 ServiceResolver
+func (sc *Scope[T]) Resolve(name string) (any, error) {
+	return sc.GetService(name)
+}
@@ -0,0 +1,142 @@
+package weave
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestScope_CtxDeriver(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "root"})
+
+	var rootCtx *TestContext
+	Provide(di, "database", func(ctx *TestContext) *ServiceA {
+		rootCtx = ctx
+		return &ServiceA{Name: "database"}
+	})
+	ProvideScoped(di, "request", func(ctx *TestContext, scope *Scope[TestContext]) *ServiceB {
+		return &ServiceB{Name: ctx.Config, ServiceA: MustResolve[ServiceA](scope, "database")}
+	})
+	ProvideScoped(di, "handler", func(ctx *TestContext, scope *Scope[TestContext]) *ServiceC {
+		return &ServiceC{Name: ctx.Config, ServiceB: MustResolve[ServiceB](scope, "request")}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if _, err := di.GetService("request"); err == nil || !strings.Contains(err.Error(), "is scoped") {
+		t.Errorf("根容器不应该解析作用域服务: %v", err)
+	}
+
+	var derived int32
+	newScope := func(tenant string) *Scope[TestContext] {
+		scope := di.NewScope()
+		if err := scope.SetCtxDeriver(func(root *TestContext) *TestContext {
+			atomic.AddInt32(&derived, 1)
+			return &TestContext{Config: root.Config + "/" + tenant}
+		}); err != nil {
+			t.Fatalf("设置派生函数失败: %v", err)
+		}
+		return scope
+	}
+
+	scope := newScope("tenant-a")
+	// 并发的首次解析只派生一次上下文，每个作用域服务只构建一次
+	var wg sync.WaitGroup
+	handlers := make([]*ServiceC, 32)
+	for i := range handlers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			handlers[i] = MustResolve[ServiceC](scope, "handler")
+		}(i)
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&derived); n != 1 {
+		t.Errorf("每个作用域应该只派生一次上下文，实际 %d 次", n)
+	}
+	for _, h := range handlers {
+		if h != handlers[0] {
+			t.Fatal("同一作用域内应该共享作用域服务的实例")
+		}
+	}
+	if handlers[0].Name != "root/tenant-a" || handlers[0].ServiceB.Name != "root/tenant-a" {
+		t.Errorf("作用域服务应该接收派生的上下文: %s %s", handlers[0].Name, handlers[0].ServiceB.Name)
+	}
+	if rootCtx.Config != "root" || handlers[0].ServiceB.ServiceA != MustMake[TestContext, ServiceA](di, "database") {
+		t.Error("根容器的服务应该使用根上下文并在作用域间共享")
+	}
+	if err := scope.SetCtxDeriver(nil); err == nil {
+		t.Error("上下文派生后不应该再设置派生函数")
+	}
+
+	other := newScope("tenant-b")
+	if h := MustResolve[ServiceC](other, "handler"); h == handlers[0] || h.Name != "root/tenant-b" {
+		t.Errorf("不同作用域应该各自构建作用域服务: %s", h.Name)
+	}
+}
+
+func TestScope_Cycle(t *testing.T) {
+	di := New[TestContext]()
+	ProvideScoped(di, "a", func(ctx *TestContext, scope *Scope[TestContext]) *ServiceA {
+		MustResolve[ServiceA](scope, "b")
+		return &ServiceA{Name: "a"}
+	})
+	ProvideScoped(di, "b", func(ctx *TestContext, scope *Scope[TestContext]) *ServiceA {
+		MustResolve[ServiceA](scope, "a")
+		return &ServiceA{Name: "b"}
+	})
+
+	_, err := di.NewScope().GetService("a")
+	var inProgress *ErrResolutionInProgress
+	if !errors.As(err, &inProgress) || strings.Join(inProgress.Path, "->") != "a->b->a" {
+		t.Errorf("作用域服务的循环依赖应该返回 ErrResolutionInProgress，实际为 %v", err)
+	}
+}
+
+func TestScope_RegisterHookAndPanics(t *testing.T) {
+	for _, recoverPanics := range []bool{false, true} {
+		var registered []string
+		opts := []Option{WithAllowEmpty(), WithRegisterHook(func(name string) {
+			registered = append(registered, name)
+		})}
+		if recoverPanics {
+			opts = append(opts, WithPanicRecovery())
+		}
+		di := New[TestContext](opts...)
+		di.SetCtx(&TestContext{Config: "root"})
+		ProvideScoped(di, "request", func(ctx *TestContext, scope *Scope[TestContext]) *ServiceA {
+			panic("bad request")
+		})
+		if strings.Join(registered, ",") != "request" {
+			t.Errorf("注册作用域服务应该触发注册回调: %v", registered)
+		}
+		if err := di.Build(); err != nil {
+			t.Fatalf("构建失败: %v", err)
+		}
+
+		scope := di.NewScope()
+		resolve := func() (panicked bool, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					panicked = true
+				}
+			}()
+			_, err = scope.GetService("request")
+			return false, err
+		}
+		panicked, err := resolve()
+		if panicked == recoverPanics {
+			t.Errorf("recoverPanics=%v 时 builder 的 panic 是否继续抛出应为 %v", recoverPanics, !recoverPanics)
+		}
+		if recoverPanics && (err == nil || !strings.Contains(err.Error(), "builder panicked: bad request")) {
+			t.Errorf("开启 WithPanicRecovery 时应该返回错误: %v", err)
+		}
+		// 再次解析不会重新构建，返回记录的错误
+		if panicked, err := resolve(); panicked || err == nil {
+			t.Errorf("再次解析应该返回错误: err=%v panicked=%v", err, panicked)
+		}
+	}
+}
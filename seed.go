@@ -0,0 +1,49 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Seed 以已有实例作为已注册服务的构建结果，Build 时跳过这些服务只构建其余服务
+// 实例原样保存，不经过占位符复制，类型必须与服务注册的类型一致；
+// 服务不存在、已构建或类型不符时返回错误且不修改任何服务。适合在集成测试中混用真实服务与 mock
+func (s *Weave[T]) Seed(instances map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(instances))
+	for name := range instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		e, ok := s.entries.Get(name)
+		if !ok {
+			return s.notFound(name)
+		}
+		if e.built {
+			return fmt.Errorf("service [%s] is already built", name)
+		}
+		instance := instances[name]
+		if instance == nil || isNilPointer(instance) {
+			return fmt.Errorf("service [%s] seed instance is nil", name)
+		}
+		if !e.accepts(instance) {
+			return &ErrTypeMismatch{Name: name, Want: e.instanceType().String(), Got: reflect.TypeOf(instance).String()}
+		}
+	}
+
+	for _, name := range names {
+		e, _ := s.entries.Get(name)
+		e.instance = instances[name]
+		e.built = true
+		e.ctxGen = 0
+		e.seeded = true
+		s.skipped.Delete(name)
+		s.setBuilt(name, true)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package weave
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDI_Seed(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	built := map[string]int{}
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		built["serviceA"]++
+		return &ServiceA{Name: "real"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		built["serviceB"]++
+		return &ServiceB{Name: "B", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+
+	var mismatch *ErrTypeMismatch
+	if err := di.Seed(map[string]any{"serviceA": &ServiceB{}}); !errors.As(err, &mismatch) {
+		t.Errorf("类型不符时应该返回类型错误，实际为: %v", err)
+	}
+	var notFound *ErrServiceNotFound
+	if err := di.Seed(map[string]any{"serviceA": &ServiceA{}, "missing": &ServiceA{}}); !errors.As(err, &notFound) {
+		t.Errorf("服务不存在时应该返回错误，实际为: %v", err)
+	}
+	if err := di.Seed(map[string]any{"serviceA": (*ServiceA)(nil)}); err == nil {
+		t.Error("nil 实例应该返回错误")
+	}
+
+	fake := &ServiceA{Name: "fake"}
+	if err := di.Seed(map[string]any{"serviceA": fake}); err != nil {
+		t.Fatalf("Seed 失败: %v", err)
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if built["serviceA"] != 0 || built["serviceB"] != 1 {
+		t.Errorf("只应该构建未提供实例的服务: %v", built)
+	}
+	if MustMake[TestContext, ServiceA](di, "serviceA") != fake || MustMake[TestContext, ServiceB](di, "serviceB").ServiceA != fake {
+		t.Error("应该原样使用提供的实例")
+	}
+	if !equalSlices(di.GetDependencyGraph().Dependencies["serviceB"], []string{"serviceA"}) {
+		t.Error("依赖关系应该照常记录")
+	}
+
+	if err := di.Seed(map[string]any{"serviceB": &ServiceB{}}); err == nil {
+		t.Error("已构建的服务不应该被替换")
+	}
+
+	// 重新构建时不会覆盖提供的实例
+	if _, err := di.RebuildIfChanged(&TestContext{Config: "v2"}, func(a, b *TestContext) bool { return a.Config == b.Config }); err != nil {
+		t.Fatalf("重新构建失败: %v", err)
+	}
+	if built["serviceA"] != 0 || fake.Name != "fake" {
+		t.Error("重新构建不应该覆盖提供的实例")
+	}
+}
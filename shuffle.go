@@ -0,0 +1,41 @@
+package weave
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+)
+
+// WithShuffledBuild 按 seed 打乱互不依赖的服务的构建顺序，用于暴露未声明的顺序依赖
+// 依赖仍会先于使用方构建；同一 seed 的顺序固定，构建失败时错误和日志中会带上 seed 以便复现
+func WithShuffledBuild(seed int64) Option {
+	return func(o *options) {
+		o.shuffleBuild = true
+		o.shuffleSeed = seed
+	}
+}
+
+// buildSequence 返回 Build 遍历服务的顺序，OrderGroups 指定的有序分组排在前面
+// 开启 WithShuffledBuild 时先按名称排序再按 seed 打乱，结果与 map 遍历顺序无关
+func (s *Weave[T]) buildSequence() []string {
+	names := s.entries.Keys()
+	if s.opts.shuffleBuild {
+		sort.Strings(names)
+		r := rand.New(rand.NewSource(s.opts.shuffleSeed))
+		r.Shuffle(len(names), func(i, j int) {
+			names[i], names[j] = names[j], names[i]
+		})
+	}
+	s.sortByGroupWave(names)
+	return names
+}
+
+// shuffleFailure 为打乱顺序后的构建失败附加 seed
+func (s *Weave[T]) shuffleFailure(err error) error {
+	if err == nil || !s.opts.shuffleBuild {
+		return err
+	}
+	log.Printf("weave: build failed with shuffle seed %d", s.opts.shuffleSeed)
+	return fmt.Errorf("build with shuffle seed %d: %w", s.opts.shuffleSeed, err)
+}
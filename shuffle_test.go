@@ -0,0 +1,70 @@
+package weave
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDI_WithShuffledBuild(t *testing.T) {
+	// reader 依赖 writer 设置的配置，但没有通过 Make 声明依赖
+	run := func(seed int64) error {
+		config := ""
+		di := New[TestContext](WithShuffledBuild(seed), WithPanicRecovery())
+		di.SetCtx(&TestContext{Config: "test"})
+		Provide(di, "writer", func(ctx *TestContext) *ServiceA {
+			config = "loaded"
+			return &ServiceA{Name: "writer"}
+		})
+		Provide(di, "reader", func(ctx *TestContext) *ServiceB {
+			if config == "" {
+				panic("config not loaded")
+			}
+			return &ServiceB{Name: config}
+		})
+		for i := 0; i < 4; i++ {
+			Provide(di, fmt.Sprintf("filler%d", i), func(ctx *TestContext) *ServiceC {
+				return &ServiceC{}
+			})
+		}
+		return di.Build()
+	}
+
+	var passed, failed bool
+	for seed := int64(1); seed <= 20; seed++ {
+		err := run(seed)
+		if err == nil {
+			passed = true
+			continue
+		}
+		failed = true
+		if !strings.Contains(err.Error(), fmt.Sprintf("shuffle seed %d", seed)) {
+			t.Errorf("失败时错误中应该包含 seed: %v", err)
+		}
+		if again := run(seed); again == nil || again.Error() != err.Error() {
+			t.Errorf("相同 seed 的结果应该可复现: %v, %v", err, again)
+		}
+	}
+	if !passed || !failed {
+		t.Errorf("隐藏的顺序依赖应该在部分 seed 下失败、部分 seed 下成功: passed=%v failed=%v", passed, failed)
+	}
+}
+
+func TestDI_WithShuffledBuildKeepsDependencies(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		di := New[TestContext](WithShuffledBuild(seed))
+		di.SetCtx(&TestContext{Config: "test"})
+		Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+			return &ServiceA{Name: "A"}
+		})
+		Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+			return &ServiceB{Name: "B", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+		})
+		if err := di.Build(); err != nil {
+			t.Fatalf("seed %d 构建失败: %v", seed, err)
+		}
+		if b := MustMake[TestContext, ServiceB](di, "serviceB"); b.ServiceA == nil || b.ServiceA.Name != "A" {
+			t.Errorf("seed %d 依赖应该先于使用方构建", seed)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package weave
+
+// NewSimple 创建不需要上下文的容器，已设置空上下文，可直接注册和构建
+func NewSimple(opts ...Option) *Weave[struct{}] {
+	di := New[struct{}](opts...)
+	di.SetCtx(&struct{}{})
+	return di
+}
@@ -0,0 +1,21 @@
+package weave
+
+import "testing"
+
+func TestDI_NewSimple(t *testing.T) {
+	di := NewSimple(WithStrictCycles())
+
+	Provide(di, "serviceA", func(_ *struct{}) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(_ *struct{}) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[struct{}, ServiceA](di, "serviceA")}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if b := MustMake[struct{}, ServiceB](di, "serviceB"); b.ServiceA.Name != "ServiceA" {
+		t.Error("依赖注入失败")
+	}
+}
@@ -0,0 +1,210 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ReplaceEvent SwapAll 替换服务后产生的事件
+type ReplaceEvent struct {
+	// Services 本次替换的服务，已排序
+	Services []string
+	// Unchanged 与原实例相等而跳过的服务，已排序
+	Unchanged []string
+}
+
+// SwapResult Swap 的结果
+type SwapResult struct {
+	// Unchanged 新值与原实例相等，未复制也未产生替换事件
+	Unchanged bool
+}
+
+// EqualFunc 设置服务被替换时判断新旧实例是否相等的函数
+// 相等时 Swap/SwapAll 跳过复制，也不会为该服务产生替换事件；
+// 未设置时，若 *R 实现了 Equal(*R) bool 方法则自动使用；
+// ProvideInterface 注册的服务以接口类型作为 R，equal 收到的是指向新旧接口值副本的指针
+func EqualFunc[T any, R any](di *Weave[T], name string, equal func(old, new *R) bool) error {
+	di.mu.Lock()
+	defer di.mu.Unlock()
+
+	e, ok := di.entries.Get(name)
+	if !ok {
+		return di.notFound(name)
+	}
+	if e.iface != nil {
+		if want := reflect.TypeOf((*R)(nil)).Elem(); want != e.iface {
+			return &ErrTypeMismatch{Name: name, Want: want.String(), Got: e.iface.String()}
+		}
+		e.equal = func(old, new any) bool {
+			o, n := old.(R), new.(R)
+			return equal(&o, &n)
+		}
+		return nil
+	}
+	if _, ok := e.instance.(*R); !ok {
+		return &ErrTypeMismatch{Name: name, Want: fmt.Sprintf("%T", (*R)(nil)), Got: fmt.Sprintf("%T", e.instance)}
+	}
+	e.equal = func(old, new any) bool {
+		return equal(old.(*R), new.(*R))
+	}
+	return nil
+}
+
+// unchanged 判断替换值是否与服务当前实例相等
+func (e *entry[T]) unchanged(value any) bool {
+	if e.equal != nil {
+		return e.equal(e.instance, value)
+	}
+	method := reflect.ValueOf(e.instance).MethodByName("Equal")
+	if !method.IsValid() {
+		return false
+	}
+	typ := method.Type()
+	if typ.NumIn() != 1 || typ.In(0) != reflect.TypeOf(value) || typ.NumOut() != 1 || typ.Out(0).Kind() != reflect.Bool {
+		return false
+	}
+	return method.Call([]reflect.Value{reflect.ValueOf(value)})[0].Bool()
+}
+
+// Swap 替换单个已构建服务的实例，语义与 SwapAll 相同
+// 新值与原实例相等时不复制，返回的结果中 Unchanged 为 true
+func (s *Weave[T]) Swap(name string, value any) (SwapResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed, err := s.swap(map[string]any{name: value})
+	if err != nil {
+		return SwapResult{}, err
+	}
+	return SwapResult{Unchanged: len(changed) == 0}, nil
+}
+
+// SwapAll 原子地替换多个已构建服务的实例
+// 先校验所有替换值的类型与原实例一致，再在同一临界区内将新值复制到原实例指针中，
+// 已持有实例指针的服务会看到新值；复制过程中出错时恢复所有已替换的服务
+// 复制写入的是实例本身：ProvideValue、ProvideDirect 注册的外部对象会被原地修改，
+// 按 AliasRecord 共享同一实例的其他服务也会一同看到新值；只想替换单个名称时使用 Replace
+// ProvideInterface 注册的服务没有占位符，替换的是容器保存的值，已持有旧值的依赖方不会看到新值；
+// 与原实例相等的服务不复制，记录在替换事件的 Unchanged 中，全部相等时不产生事件；
+// 替换已通过 Extract 提取的服务需要 WithAllowLiveMutation，否则返回 *ErrLiveMutation
+func (s *Weave[T]) SwapAll(replacements map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.swap(replacements)
+	return err
+}
+
+// swap 校验并复制替换值，返回实际替换的服务
+func (s *Weave[T]) swap(replacements map[string]any) (names []string, err error) {
+	all := make([]string, 0, len(replacements))
+	for name := range replacements {
+		all = append(all, name)
+	}
+	sort.Strings(all)
+
+	unchanged := []string{}
+	for _, name := range all {
+		e, ok := s.entries.Get(name)
+		if !ok {
+			return nil, s.notFound(name)
+		}
+		if !e.built {
+			return nil, fmt.Errorf("service [%s] is not built and cannot be swapped", name)
+		}
+		if e.released {
+			return nil, &ErrBuildOnly{Name: name}
+		}
+		value := replacements[name]
+		if value == nil || isNilPointer(value) {
+			return nil, fmt.Errorf("service [%s] replacement is nil", name)
+		}
+		if !e.accepts(value) {
+			return nil, fmt.Errorf("service [%s] is %s, replacement is %s", name, e.instanceType(), reflect.TypeOf(value))
+		}
+		if e.unchanged(value) {
+			unchanged = append(unchanged, name)
+		} else {
+			names = append(names, name)
+		}
+	}
+
+	if err := s.checkLiveMutation("swap", names); err != nil {
+		return nil, err
+	}
+
+	// 保存原值，复制失败时回滚
+	originals := make([]reflect.Value, 0, len(names))
+	defer func() {
+		if r := recover(); r != nil {
+			for i, original := range originals {
+				e, _ := s.entries.Get(names[i])
+				if e.iface != nil {
+					e.instance = original.Interface()
+					continue
+				}
+				reflect.ValueOf(e.instance).Elem().Set(original)
+			}
+			names, err = nil, fmt.Errorf("swap failed, all services restored: %v", r)
+		}
+	}()
+	for _, name := range names {
+		e, _ := s.entries.Get(name)
+		if e.iface != nil {
+			// 接口服务没有占位符可以写入，替换保存的值
+			originals = append(originals, reflect.ValueOf(e.instance))
+			e.instance = replacements[name]
+			e.extracted = false
+			continue
+		}
+		target := reflect.ValueOf(e.instance).Elem()
+		original := reflect.New(target.Type()).Elem()
+		original.Set(target)
+		originals = append(originals, original)
+		target.Set(reflect.ValueOf(replacements[name]).Elem())
+	}
+
+	if len(names) > 0 {
+		s.generation++
+	}
+	if s.opts.onReplaced != nil && len(names) > 0 {
+		s.opts.onReplaced(ReplaceEvent{Services: names, Unchanged: unchanged})
+	}
+	return names, nil
+}
+
+// Replace 将已构建服务的实例替换为另一个已构建好的实例
+// 与 SwapAll 不同，Replace 替换容器中保存的指针而不复制值：之后的解析得到新实例，
+// 已经持有旧指针的依赖方仍使用旧实例；需要依赖方同步看到新值时使用 SwapAll
+// 共享同一实例的其他服务仍指向旧实例，被替换的服务不再记录为共享
+func (s *Weave[T]) Replace(name string, instance any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries.Get(name)
+	if !ok {
+		return s.notFound(name)
+	}
+	if !e.built {
+		return fmt.Errorf("service [%s] is not built and cannot be replaced", name)
+	}
+	if e.released {
+		return &ErrBuildOnly{Name: name}
+	}
+	if instance == nil || isNilPointer(instance) {
+		return fmt.Errorf("service [%s] replacement is nil", name)
+	}
+	if !e.accepts(instance) {
+		return fmt.Errorf("service [%s] is %s, replacement is %s", name, e.instanceType(), reflect.TypeOf(instance))
+	}
+
+	s.forgetValue(name, e.instance)
+	e.instance = instance
+	e.extracted = false
+	s.generation++
+	if s.opts.onReplaced != nil {
+		s.opts.onReplaced(ReplaceEvent{Services: []string{name}})
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDI_SwapAll(t *testing.T) {
+	var events []ReplaceEvent
+	di := New[TestContext](WithReplaceHandler(func(e ReplaceEvent) {
+		events = append(events, e)
+	}))
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "parser", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "parser-v1"}
+	})
+	Provide(di, "schema", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "schema-v1", ServiceA: MustMake[TestContext, ServiceA](di, "parser")}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	parser := MustMake[TestContext, ServiceA](di, "parser")
+	schema := MustMake[TestContext, ServiceB](di, "schema")
+
+	err := di.SwapAll(map[string]any{
+		"parser": &ServiceA{Name: "parser-v2"},
+		"schema": &ServiceB{Name: "schema-v2", ServiceA: parser},
+	})
+	if err != nil {
+		t.Fatalf("替换失败: %v", err)
+	}
+
+	if parser.Name != "parser-v2" || schema.Name != "schema-v2" {
+		t.Errorf("已持有的实例指针应该看到新值: %s %s", parser.Name, schema.Name)
+	}
+	if schema.ServiceA.Name != "parser-v2" {
+		t.Error("依赖方应该看到替换后的依赖")
+	}
+	if len(events) != 1 || strings.Join(events[0].Services, ",") != "parser,schema" {
+		t.Errorf("应该产生一个批量替换事件: %v", events)
+	}
+}
+
+func TestDI_SwapAllRollback(t *testing.T) {
+	var events []ReplaceEvent
+	di := New[TestContext](WithReplaceHandler(func(e ReplaceEvent) {
+		events = append(events, e)
+	}))
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "parser", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "parser-v1"}
+	})
+	Provide(di, "schema", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "schema-v1", ServiceA: MustMake[TestContext, ServiceA](di, "parser")}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	parser := MustMake[TestContext, ServiceA](di, "parser")
+	schema := MustMake[TestContext, ServiceB](di, "schema")
+
+	err := di.SwapAll(map[string]any{
+		"parser": &ServiceA{Name: "parser-v2"},
+		"schema": &ServiceA{Name: "wrong type"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "service [schema]") {
+		t.Fatalf("类型不符时应该报错: %v", err)
+	}
+	if parser.Name != "parser-v1" || schema.Name != "schema-v1" {
+		t.Errorf("任一替换失败时所有服务都应该保持原值: %s %s", parser.Name, schema.Name)
+	}
+	if len(events) != 0 {
+		t.Errorf("替换失败时不应该产生事件: %v", events)
+	}
+
+	if err := di.SwapAll(map[string]any{"missing": &ServiceA{}}); err == nil {
+		t.Error("替换不存在的服务应该报错")
+	}
+}
+
+func TestDI_Replace(t *testing.T) {
+	var events []ReplaceEvent
+	di := New[TestContext](WithReplaceHandler(func(e ReplaceEvent) {
+		events = append(events, e)
+	}))
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "parser", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "parser-v1"}
+	})
+	Provide(di, "schema", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "schema-v1", ServiceA: MustMake[TestContext, ServiceA](di, "parser")}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	old := MustMake[TestContext, ServiceA](di, "parser")
+	schema := MustMake[TestContext, ServiceB](di, "schema")
+
+	replacement := &ServiceA{Name: "parser-v2"}
+	if err := di.Replace("parser", replacement); err != nil {
+		t.Fatalf("替换失败: %v", err)
+	}
+
+	if MustMake[TestContext, ServiceA](di, "parser") != replacement {
+		t.Error("之后的解析应该得到新实例")
+	}
+	if old.Name != "parser-v1" || schema.ServiceA != old {
+		t.Error("已持有旧指针的依赖方应该仍使用旧实例")
+	}
+	if len(events) != 1 || events[0].Services[0] != "parser" {
+		t.Errorf("应该产生替换事件: %v", events)
+	}
+
+	if err := di.Replace("parser", &ServiceB{}); err == nil {
+		t.Error("类型不符时应该报错")
+	}
+	if err := di.Replace("parser", (*ServiceA)(nil)); err == nil {
+		t.Error("替换为 nil 时应该报错")
+	}
+}
+
+// versioned 实现 Equal 方法，版本号相同即视为相等
+type versioned struct {
+	Version int
+	Payload string
+}
+
+func (v *versioned) Equal(other *versioned) bool {
+	return v.Version == other.Version
+}
+
+func TestDI_SwapUnchanged(t *testing.T) {
+	var events []ReplaceEvent
+	di := New[TestContext](WithReplaceHandler(func(e ReplaceEvent) {
+		events = append(events, e)
+	}))
+	di.SetCtx(&TestContext{Config: "test"})
+	Provide(di, "parser", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "parser-v1"}
+	})
+	Provide(di, "schema", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "schema-v1", ServiceA: MustMake[TestContext, ServiceA](di, "parser")}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if err := EqualFunc(di, "parser", func(old, new *ServiceA) bool {
+		// 只比较版本前缀
+		return old.Name[:9] == new.Name[:9]
+	}); err != nil {
+		t.Fatalf("设置相等函数失败: %v", err)
+	}
+	parser := MustMake[TestContext, ServiceA](di, "parser")
+
+	result, err := di.Swap("parser", &ServiceA{Name: "parser-v1-patch"})
+	if err != nil {
+		t.Fatalf("替换失败: %v", err)
+	}
+	if !result.Unchanged {
+		t.Error("新旧实例相等时应该报告 unchanged")
+	}
+	if parser.Name != "parser-v1" {
+		t.Error("新旧实例相等时不应该复制")
+	}
+	if len(events) != 0 {
+		t.Errorf("新旧实例相等时依赖方不应该收到替换事件: %v", events)
+	}
+
+	err = di.SwapAll(map[string]any{
+		"parser": &ServiceA{Name: "parser-v1"},
+		"schema": &ServiceB{Name: "schema-v2", ServiceA: parser},
+	})
+	if err != nil {
+		t.Fatalf("替换失败: %v", err)
+	}
+	if len(events) != 1 || strings.Join(events[0].Services, ",") != "schema" || strings.Join(events[0].Unchanged, ",") != "parser" {
+		t.Errorf("替换事件应该只包含发生变化的服务: %v", events)
+	}
+
+	result, err = di.Swap("parser", &ServiceA{Name: "parser-v2"})
+	if err != nil || result.Unchanged || parser.Name != "parser-v2" {
+		t.Errorf("新旧实例不等时应该复制: %v %v %s", result, err, parser.Name)
+	}
+
+	if err := EqualFunc(di, "parser", func(old, new *ServiceB) bool { return true }); err == nil {
+		t.Error("类型不符时应该报错")
+	}
+}
+
+func TestDI_SwapEqualMethod(t *testing.T) {
+	di := New[TestContext]()
+	if err := ProvideValue(di, "config", &versioned{Version: 1, Payload: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	config := MustMake[TestContext, versioned](di, "config")
+
+	result, err := di.Swap("config", &versioned{Version: 1, Payload: "b"})
+	if err != nil || !result.Unchanged || config.Payload != "a" {
+		t.Errorf("应该自动使用 Equal 方法: %v %v %s", result, err, config.Payload)
+	}
+	result, err = di.Swap("config", &versioned{Version: 2, Payload: "b"})
+	if err != nil || result.Unchanged || config.Payload != "b" {
+		t.Errorf("版本变化时应该复制: %v %v %s", result, err, config.Payload)
+	}
+}
+
+func TestDI_SwapAliasedValue(t *testing.T) {
+	di := New[TestContext](WithAliasPolicy(AliasRecord))
+	di.SetCtx(&TestContext{Config: "test"})
+	shared := &ServiceA{Name: "v1"}
+	if err := ProvideValue(di, "primary", shared); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	if err := ProvideValue(di, "alias", shared); err != nil {
+		t.Fatalf("注册别名失败: %v", err)
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	// Swap 原地复制，外部对象与别名都会看到新值
+	if _, err := di.Swap("primary", &ServiceA{Name: "v2"}); err != nil {
+		t.Fatalf("替换失败: %v", err)
+	}
+	if shared.Name != "v2" || MustMake[TestContext, ServiceA](di, "alias").Name != "v2" {
+		t.Errorf("Swap 应该修改共享的实例: shared=%s alias=%s", shared.Name, MustMake[TestContext, ServiceA](di, "alias").Name)
+	}
+	if info, _ := di.Info("alias"); strings.Join(info.SharedWith, ",") != "primary" {
+		t.Errorf("Swap 后共享关系应该保持: %v", info.SharedWith)
+	}
+
+	// Replace 只替换单个名称，别名仍指向原实例
+	if err := di.Replace("primary", &ServiceA{Name: "v3"}); err != nil {
+		t.Fatalf("替换失败: %v", err)
+	}
+	if shared.Name != "v2" || MustMake[TestContext, ServiceA](di, "alias") != shared {
+		t.Error("Replace 不应该修改共享的实例")
+	}
+	if MustMake[TestContext, ServiceA](di, "primary").Name != "v3" {
+		t.Error("Replace 后应该解析到新实例")
+	}
+	if info, _ := di.Info("alias"); len(info.SharedWith) != 0 {
+		t.Errorf("Replace 后不应该再记录共享关系: %v", info.SharedWith)
+	}
+}
@@ -0,0 +1,56 @@
+package weave
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+)
+
+// GraphEdge 依赖关系边：From 依赖 To
+type GraphEdge struct {
+	From string
+	To   string
+}
+
+// ServiceNames 返回按名称排序的所有服务
+func (s *Weave[T]) ServiceNames() []string {
+	names := s.entries.Keys()
+	sort.Strings(names)
+	return names
+}
+
+// GraphEdges 返回去重并排序后的所有依赖关系边
+func (s *Weave[T]) GraphEdges() []GraphEdge {
+	graph := s.GetDependencyGraph()
+
+	edges := []GraphEdge{}
+	for from, deps := range graph.Dependencies {
+		for i, to := range deps {
+			// 依赖列表已排序，重复的边相邻
+			if i > 0 && deps[i-1] == to {
+				continue
+			}
+			edges = append(edges, GraphEdge{From: from, To: to})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+// TopologyHash 返回依赖拓扑的稳定哈希，服务或依赖关系变化时哈希随之改变
+// 可作为黄金值在 CI 中检测意外的装配变化
+func (s *Weave[T]) TopologyHash() string {
+	h := sha256.New()
+	for _, name := range s.ServiceNames() {
+		h.Write([]byte("service\x00" + name + "\n"))
+	}
+	for _, e := range s.GraphEdges() {
+		h.Write([]byte("edge\x00" + e.From + "\x00" + e.To + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,42 @@
+package weave
+
+import "testing"
+
+func TestDI_TopologyHash(t *testing.T) {
+	// serviceB 重复解析 serviceA，不应该影响拓扑
+	specs := []string{"serviceA", "serviceB: serviceA serviceA", "serviceC"}
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	provideGraph(di, specs...)
+	di.Build()
+
+	if names := di.ServiceNames(); !equalSlices(names, []string{"serviceA", "serviceB", "serviceC"}) {
+		t.Errorf("服务名称错误: %v", names)
+	}
+	edges := di.GraphEdges()
+	if len(edges) != 1 || edges[0] != (GraphEdge{From: "serviceB", To: "serviceA"}) {
+		t.Errorf("依赖关系边应该去重，实际为: %v", edges)
+	}
+
+	hash := di.TopologyHash()
+	if len(hash) != 64 {
+		t.Errorf("哈希长度错误: %s", hash)
+	}
+	for i := 0; i < 5; i++ {
+		same := New[TestContext]()
+		same.SetCtx(&TestContext{Config: "test"})
+		provideGraph(same, specs...)
+		same.Build()
+		if got := same.TopologyHash(); got != hash {
+			t.Fatalf("相同拓扑的哈希应该稳定: %s != %s", got, hash)
+		}
+	}
+
+	changed := New[TestContext]()
+	changed.SetCtx(&TestContext{Config: "test"})
+	provideGraph(changed, "serviceA", "serviceB: serviceA serviceA", "serviceC: serviceB")
+	changed.Build()
+	if changed.TopologyHash() == hash {
+		t.Error("依赖关系变化后哈希应该改变")
+	}
+}
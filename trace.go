@@ -0,0 +1,115 @@
+package weave
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TraceKind 构建事件类型
+type TraceKind string
+
+const (
+	// TraceBuildStart 开始执行 builder
+	TraceBuildStart TraceKind = "build"
+	// TraceBuildEnd builder 执行成功
+	TraceBuildEnd TraceKind = "done"
+	// TraceBuildFailed builder 执行失败
+	TraceBuildFailed TraceKind = "fail"
+	// TraceResolve builder 解析依赖
+	TraceResolve TraceKind = "resolve"
+	// TraceResolveFailed 依赖解析失败
+	TraceResolveFailed TraceKind = "missing"
+	// TracePlaceholder 依赖正在构建中（循环依赖），返回占位符
+	TracePlaceholder TraceKind = "placeholder"
+)
+
+// TraceEvent 构建事件
+type TraceEvent struct {
+	// Kind 事件类型
+	Kind TraceKind
+	// Service 事件所属的服务，解析事件中为发起解析的服务
+	Service string
+	// Target 解析事件中被解析的服务
+	Target string
+	// Depth 事件在调用树中的缩进层级
+	Depth int
+	// Err 失败事件的错误
+	Err error
+	// Phase 事件发生的阶段
+	Phase Phase
+}
+
+func (e TraceEvent) String() string {
+	var b strings.Builder
+	if e.Phase != PhaseBuilding {
+		fmt.Fprintf(&b, "[%s] %s ", e.Phase, e.Service)
+	}
+	b.WriteString(string(e.Kind))
+	b.WriteString(" ")
+	if e.Target != "" {
+		b.WriteString(e.Target)
+	} else {
+		b.WriteString(e.Service)
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&b, ": %v", e.Err)
+	}
+	return b.String()
+}
+
+// trace 记录构建事件，超出上限时丢弃最早的事件
+func (s *Weave[T]) trace(kind TraceKind, service, target string, err error) {
+	if s.opts.traceMaxEvents <= 0 {
+		return
+	}
+	// builder 的事件比其构建事件多缩进一层，嵌套构建再缩进一层
+	depth := 2 * len(s.resolving)
+	if target != "" && depth > 0 {
+		depth--
+	}
+
+	s.traceMu.Lock()
+	defer s.traceMu.Unlock()
+	if len(s.traceEvents) >= s.opts.traceMaxEvents {
+		s.traceEvents = s.traceEvents[1:]
+		s.traceDropped++
+	}
+	s.traceEvents = append(s.traceEvents, TraceEvent{
+		Kind:    kind,
+		Service: service,
+		Target:  target,
+		Depth:   depth,
+		Err:     err,
+		Phase:   s.Phase(),
+	})
+}
+
+// BuildTrace 返回最近一次构建记录的事件，需要开启 WithBuildTrace
+func (s *Weave[T]) BuildTrace() []TraceEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := make([]TraceEvent, len(s.traceEvents))
+	copy(events, s.traceEvents)
+	return events
+}
+
+// FormatTrace 以缩进的调用树形式输出构建记录
+func (s *Weave[T]) FormatTrace(w io.Writer) error {
+	s.mu.RLock()
+	dropped := s.traceDropped
+	s.mu.RUnlock()
+
+	if dropped > 0 {
+		if _, err := fmt.Fprintf(w, "... %d earlier events dropped\n", dropped); err != nil {
+			return err
+		}
+	}
+	for _, e := range s.BuildTrace() {
+		if _, err := fmt.Fprintf(w, "%s%s\n", strings.Repeat("  ", e.Depth), e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
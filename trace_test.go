@@ -0,0 +1,118 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildSorted 按名称顺序构建，保证记录稳定
+func buildSorted(t *testing.T, di *Weave[TestContext]) {
+	t.Helper()
+	if done, err := di.BuildIncremental(time.Hour); !done || err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+}
+
+func formatTrace(t *testing.T, di *Weave[TestContext]) string {
+	t.Helper()
+	var b strings.Builder
+	if err := di.FormatTrace(&b); err != nil {
+		t.Fatalf("输出构建记录失败: %v", err)
+	}
+	return b.String()
+}
+
+func TestDI_BuildTrace(t *testing.T) {
+	di := New[TestContext](WithBuildTrace(100))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	// serviceA -> serviceB -> serviceC
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceB](di, "serviceB")
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		MustMake[TestContext, ServiceC](di, "serviceC")
+		return &ServiceB{Name: "ServiceB"}
+	})
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+		return &ServiceC{Name: "ServiceC"}
+	})
+	buildSorted(t, di)
+
+	want := `build serviceA
+  resolve serviceB
+    build serviceB
+      resolve serviceC
+        build serviceC
+        done serviceC
+    done serviceB
+done serviceA
+`
+	if got := formatTrace(t, di); got != want {
+		t.Errorf("构建记录错误:\n%s\n期望:\n%s", got, want)
+	}
+}
+
+func TestDI_BuildTraceCycle(t *testing.T) {
+	di := New[TestContext](WithBuildTrace(100))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	// serviceA -> serviceB -> serviceA
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceB](di, "serviceB")
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		a := MustMake[TestContext, ServiceA](di, "serviceA")
+		TryMake[TestContext, ServiceD](di, "serviceD")
+		return &ServiceB{Name: "ServiceB", ServiceA: a}
+	})
+	buildSorted(t, di)
+
+	want := `build serviceA
+  resolve serviceB
+    build serviceB
+      placeholder serviceA
+      missing serviceD: service [serviceD] not found, did you mean [serviceA], [serviceB]?
+    done serviceB
+done serviceA
+`
+	if got := formatTrace(t, di); got != want {
+		t.Errorf("构建记录错误:\n%s\n期望:\n%s", got, want)
+	}
+}
+
+func TestDI_BuildTraceBounded(t *testing.T) {
+	di := New[TestContext](WithBuildTrace(2))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB"}
+	})
+	buildSorted(t, di)
+
+	events := di.BuildTrace()
+	if len(events) != 2 || events[0].Kind != TraceBuildStart || events[0].Service != "serviceB" {
+		t.Errorf("应该只保留最近的事件: %v", events)
+	}
+	if got := formatTrace(t, di); !strings.HasPrefix(got, "... 2 earlier events dropped\n") {
+		t.Errorf("应该提示丢弃的事件数: %s", got)
+	}
+
+	plain := New[TestContext]()
+	plain.SetCtx(&TestContext{Config: "test"})
+	Provide(plain, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	if err := plain.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if events := plain.BuildTrace(); len(events) != 0 {
+		t.Errorf("默认不应该记录构建过程: %v", events)
+	}
+}
@@ -0,0 +1,83 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ProvideValue 注册已构建好的服务实例
+// 同一实例指针以多个名称注册时，按 WithAliasPolicy 配置拒绝或记录共享关系；
+// 零大小类型的不同实例可能共用同一地址，不参与共享检测
+func ProvideValue[T any, R any](di *Weave[T], name string, value *R) error {
+	if value == nil {
+		return fmt.Errorf("service [%s] value is nil", name)
+	}
+
+	di.mu.Lock()
+	defer di.mu.Unlock()
+
+	addr := instanceAddr(value)
+	names, _ := di.values.Get(addr)
+	others := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != name {
+			others = append(others, n)
+		}
+	}
+	if len(others) > 0 && di.opts.aliasPolicy == AliasReject {
+		return fmt.Errorf("service [%s] instance %p is already registered as [%s]", name, value, others[0])
+	}
+
+	di.register(name, &entry[*T]{
+		instance: value,
+		built:    true,
+	})
+	if addr != 0 {
+		di.values.Set(addr, append(others, name))
+	}
+	return nil
+}
+
+// forgetValue 移除实例指针与服务名称的登记
+func (s *Weave[T]) forgetValue(name string, instance any) {
+	addr := instanceAddr(instance)
+	names, ok := s.values.Get(addr)
+	if !ok {
+		return
+	}
+	kept := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != name {
+			kept = append(kept, n)
+		}
+	}
+	if len(kept) == 0 {
+		s.values.Delete(addr)
+	} else {
+		s.values.Set(addr, kept)
+	}
+}
+
+// sharedWith 返回与指定服务共享同一实例指针的其他服务
+func (s *Weave[T]) sharedWith(name string, instance any) []string {
+	names, _ := s.values.Get(instanceAddr(instance))
+	others := []string{}
+	for _, n := range names {
+		if n != name {
+			others = append(others, n)
+		}
+	}
+	sort.Strings(others)
+	return others
+}
+
+// instanceAddr 返回实例指针的地址，接口服务保存的非指针值返回 0
+// 零大小类型的实例也返回 0：Go 可能为所有零大小的分配返回同一地址，地址不能区分它们
+func instanceAddr(instance any) uintptr {
+	rv := reflect.ValueOf(instance)
+	if rv.Kind() != reflect.Ptr || rv.Type().Elem().Size() == 0 {
+		return 0
+	}
+	return rv.Pointer()
+}
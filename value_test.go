@@ -0,0 +1,115 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDI_ProvideValue(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	db := &ServiceA{Name: "PostgreSQL"}
+	if err := ProvideValue(di, "database", db); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	Provide(di, "userService", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "UserService", ServiceA: MustMake[TestContext, ServiceA](di, "database")}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	if got := MustMake[TestContext, ServiceA](di, "database"); got != db {
+		t.Error("ProvideValue注册的实例应该原样返回")
+	}
+	if got := MustMake[TestContext, ServiceB](di, "userService"); got.ServiceA != db {
+		t.Error("依赖方应该拿到ProvideValue注册的实例")
+	}
+
+	if err := ProvideValue[TestContext, ServiceA](di, "nilService", nil); err == nil {
+		t.Error("注册nil实例应该返回错误")
+	}
+}
+
+func TestDI_ProvideValueAliasReject(t *testing.T) {
+	di := New[TestContext]()
+
+	x := &ServiceA{Name: "shared"}
+	if err := ProvideValue(di, "a", x); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	err := ProvideValue(di, "b", x)
+	if err == nil {
+		t.Fatal("同一实例指针注册第二个名称应该返回错误")
+	}
+	if !strings.Contains(err.Error(), "[b]") || !strings.Contains(err.Error(), "[a]") {
+		t.Errorf("错误信息应该包含两个服务名称，实际为: %v", err)
+	}
+	if _, ok := di.Info("b"); ok {
+		t.Error("被拒绝的注册不应该生效")
+	}
+
+	// 同名重复注册同一实例是允许的
+	if err := ProvideValue(di, "a", x); err != nil {
+		t.Errorf("同名重复注册不应该返回错误: %v", err)
+	}
+
+	// 覆盖后旧实例可以用其他名称注册
+	Provide(di, "a", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "rebuilt"}
+	})
+	if err := ProvideValue(di, "b", x); err != nil {
+		t.Errorf("覆盖后旧实例应该可以重新注册: %v", err)
+	}
+}
+
+func TestDI_ProvideValueZeroSize(t *testing.T) {
+	type emptyA struct{}
+	type emptyB struct{}
+	di := New[TestContext]()
+
+	// 零大小类型的不同实例可能共用同一地址，不应该被当作同一实例
+	if err := ProvideValue(di, "a", &emptyA{}); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	if err := ProvideValue(di, "b", &emptyB{}); err != nil {
+		t.Errorf("不同的零大小实例不应该被拒绝: %v", err)
+	}
+	if info, _ := di.Info("a"); len(info.SharedWith) != 0 {
+		t.Errorf("零大小实例不应该记录共享关系: %v", info.SharedWith)
+	}
+}
+
+func TestDI_ProvideValueAliasRecord(t *testing.T) {
+	di := New[TestContext](WithAliasPolicy(AliasRecord))
+
+	x := &ServiceA{Name: "shared"}
+	if err := ProvideValue(di, "a", x); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	if err := ProvideValue(di, "b", x); err != nil {
+		t.Fatalf("记录策略下不应该返回错误: %v", err)
+	}
+
+	infoA, ok := di.Info("a")
+	if !ok {
+		t.Fatal("应该能获取服务a的信息")
+	}
+	if !equalSlices(infoA.SharedWith, []string{"b"}) {
+		t.Errorf("服务a应该与b共享实例，实际为: %v", infoA.SharedWith)
+	}
+	infoB, _ := di.Info("b")
+	if !equalSlices(infoB.SharedWith, []string{"a"}) {
+		t.Errorf("服务b应该与a共享实例，实际为: %v", infoB.SharedWith)
+	}
+
+	if err := di.Rename("b", "c"); err != nil {
+		t.Fatalf("重命名失败: %v", err)
+	}
+	infoA, _ = di.Info("a")
+	if !equalSlices(infoA.SharedWith, []string{"c"}) {
+		t.Errorf("重命名后共享关系应该同步更新，实际为: %v", infoA.SharedWith)
+	}
+}
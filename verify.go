@@ -0,0 +1,164 @@
+package weave
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WiringIssue 构建后检查发现的装配问题
+type WiringIssue struct {
+	// Service 存在问题的服务
+	Service string
+	// Field 相关字段，共享实例问题时为空
+	Field string
+	// Dependency 相关的依赖服务
+	Dependency string
+	// Message 问题描述
+	Message string
+}
+
+func (i WiringIssue) String() string {
+	return fmt.Sprintf("service [%s]: %s", i.Service, i.Message)
+}
+
+// VerifyWiring 检查已构建服务的装配情况
+// 报告类型与已记录依赖相符但仍为 nil 的指针字段，以及共享同一实例指针的服务
+func (s *Weave[T]) VerifyWiring() []WiringIssue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := s.entries.Keys()
+	sort.Strings(names)
+
+	dependencies, _ := s.edges.materialize(names)
+
+	issues := []WiringIssue{}
+	for _, name := range names {
+		e, _ := s.entries.Get(name)
+		if !e.built || e.released {
+			continue
+		}
+
+		if shared := s.sharedWith(name, e.instance); len(shared) > 0 {
+			issues = append(issues, WiringIssue{
+				Service:    name,
+				Dependency: shared[0],
+				Message:    fmt.Sprintf("instance is shared with [%s]", strings.Join(shared, ", ")),
+			})
+		}
+
+		v := reflect.ValueOf(e.instance)
+		if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+			// 接口服务可能保存非指针的值
+			continue
+		}
+		v = v.Elem()
+
+		reported := make(map[int]bool)
+		optional := make(map[int]bool)
+		for _, af := range autowireFields(v.Type()) {
+			optional[af.index] = af.optional
+		}
+
+		for _, dep := range uniqueStrings(dependencies[name]) {
+			depEntry, ok := s.entries.Get(dep)
+			if !ok {
+				if depEntry, ok = s.externals.Get(dep); !ok {
+					continue
+				}
+			}
+			depType := reflect.TypeOf(depEntry.instance)
+			for i := 0; i < v.NumField(); i++ {
+				f := v.Type().Field(i)
+				if f.Type != depType || f.Type.Kind() != reflect.Ptr || optional[i] || reported[i] || !v.Field(i).IsNil() {
+					continue
+				}
+				reported[i] = true
+				issues = append(issues, WiringIssue{
+					Service:    name,
+					Field:      f.Name,
+					Dependency: dep,
+					Message:    fmt.Sprintf("field %s (%s) is nil although [%s] was resolved", f.Name, f.Type, dep),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// uniqueStrings 去重并保持原有顺序
+func uniqueStrings(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// SuspiciousRoots 返回疑似绕过容器自行构造依赖的服务名称
+// 详见 SuspiciousRootsReport
+func (s *Weave[T]) SuspiciousRoots() []string {
+	names := []string{}
+	for _, issue := range s.SuspiciousRootsReport() {
+		names = append(names, issue.Service)
+	}
+	return uniqueStrings(names)
+}
+
+// SuspiciousRootsReport 检查没有记录任何依赖的已构建服务
+// 若其导出字段非 nil 且类型与其他已注册服务相同，说明 builder 可能直接构造了依赖而没有通过容器解析
+// 结果是启发式的，仅供审查装配时参考
+func (s *Weave[T]) SuspiciousRootsReport() []WiringIssue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := s.entries.Keys()
+	sort.Strings(names)
+
+	byType := make(map[reflect.Type][]string)
+	for _, name := range names {
+		e, _ := s.entries.Get(name)
+		if e.instance != nil {
+			t := reflect.TypeOf(e.instance)
+			byType[t] = append(byType[t], name)
+		}
+	}
+
+	issues := []WiringIssue{}
+	for _, name := range names {
+		e, _ := s.entries.Get(name)
+		if !e.built || e.released || len(s.edges.dependsOn(name)) > 0 {
+			continue
+		}
+		v := reflect.ValueOf(e.instance)
+		if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		v = v.Elem()
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.PkgPath != "" || f.Type.Kind() != reflect.Ptr || v.Field(i).IsNil() {
+				continue
+			}
+			for _, candidate := range byType[f.Type] {
+				if candidate == name {
+					continue
+				}
+				issues = append(issues, WiringIssue{
+					Service:    name,
+					Field:      f.Name,
+					Dependency: candidate,
+					Message:    fmt.Sprintf("field %s (%s) is set but no service was resolved, expected [%s]", f.Name, f.Type, candidate),
+				})
+				break
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,95 @@
+package weave
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDI_VerifyWiring(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	// 解析了serviceA却没有赋值给字段
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		MustMake[TestContext, ServiceA](di, "serviceA")
+		return &ServiceB{Name: "ServiceB"}
+	})
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+		return &ServiceC{
+			Name:     "ServiceC",
+			ServiceA: MustMake[TestContext, ServiceA](di, "serviceA"),
+			ServiceB: MustMake[TestContext, ServiceB](di, "serviceB"),
+		}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	issues := di.VerifyWiring()
+	if len(issues) != 1 {
+		t.Fatalf("期望发现1个装配问题，实际为: %v", issues)
+	}
+	issue := issues[0]
+	if issue.Service != "serviceB" || issue.Field != "ServiceA" || issue.Dependency != "serviceA" {
+		t.Errorf("装配问题内容错误: %+v", issue)
+	}
+	if !strings.Contains(issue.String(), "serviceB") {
+		t.Errorf("问题描述应该包含服务名称: %s", issue)
+	}
+}
+
+func TestDI_VerifyWiringSharedInstance(t *testing.T) {
+	di := New[TestContext](WithAliasPolicy(AliasRecord))
+
+	x := &ServiceA{Name: "shared"}
+	ProvideValue(di, "a", x)
+	ProvideValue(di, "b", x)
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	issues := di.VerifyWiring()
+	if len(issues) != 2 {
+		t.Fatalf("期望两个服务都报告共享实例，实际为: %v", issues)
+	}
+	if issues[0].Service != "a" || issues[0].Dependency != "b" {
+		t.Errorf("共享实例问题内容错误: %+v", issues[0])
+	}
+}
+
+func TestDI_SuspiciousRoots(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	// 直接构造依赖，没有通过容器解析
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB", ServiceA: &ServiceA{Name: "local"}}
+	})
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+		return &ServiceC{
+			Name:     "ServiceC",
+			ServiceA: MustMake[TestContext, ServiceA](di, "serviceA"),
+		}
+	})
+
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	roots := di.SuspiciousRoots()
+	if len(roots) != 1 || roots[0] != "serviceB" {
+		t.Fatalf("期望 serviceB 被标记为可疑根服务，实际为: %v", roots)
+	}
+	report := di.SuspiciousRootsReport()
+	if report[0].Field != "ServiceA" || report[0].Dependency != "serviceA" {
+		t.Errorf("报告内容错误: %+v", report[0])
+	}
+}
@@ -0,0 +1,125 @@
+package weave
+
+import "context"
+
+// WaitBuilt 阻塞直到 Build 成功完成
+// 最近一次构建失败时返回该错误，ctx 取消时返回 ctx.Err()
+func (s *Weave[T]) WaitBuilt(ctx context.Context) error {
+	for {
+		s.waitMu.Lock()
+		built, failed, err := s.builtCh, s.failedCh, s.buildErr
+		s.waitMu.Unlock()
+
+		select {
+		case <-built:
+			return nil
+		default:
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-built:
+			return nil
+		case <-failed:
+			// 构建失败，重新读取错误
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// BuiltChan 返回构建成功后关闭的通道
+// 构建后重新注册服务会使容器需要重新构建，此时应重新获取通道
+func (s *Weave[T]) BuiltChan() <-chan struct{} {
+	s.waitMu.Lock()
+	defer s.waitMu.Unlock()
+	return s.builtCh
+}
+
+// startAttempt 开始一次构建，清除上次的失败
+func (s *Weave[T]) startAttempt() {
+	s.waitMu.Lock()
+	defer s.waitMu.Unlock()
+	s.buildErr = nil
+	s.failedChain = nil
+}
+
+// finishAttempt 通知等待方构建结果
+func (s *Weave[T]) finishAttempt(err error) {
+	s.waitMu.Lock()
+	defer s.waitMu.Unlock()
+
+	if err != nil {
+		s.buildErr = err
+		close(s.failedCh)
+		s.failedCh = make(chan struct{})
+		return
+	}
+	select {
+	case <-s.builtCh:
+	default:
+		close(s.builtCh)
+	}
+}
+
+// resetBuilt 容器需要重新构建时替换已关闭的通道
+func (s *Weave[T]) resetBuilt() {
+	s.waitMu.Lock()
+	defer s.waitMu.Unlock()
+
+	select {
+	case <-s.builtCh:
+		s.builtCh = make(chan struct{})
+	default:
+	}
+}
+
+// WaitFor 阻塞直到指定服务构建完成，ctx 取消时返回 ctx.Err()
+// 服务由 Build、BuildIncremental 或其他服务的 builder 构建均可，通过 ProvideValue 注册的服务立即返回
+func (s *Weave[T]) WaitFor(ctx context.Context, name string) error {
+	s.waitMu.Lock()
+	if s.builtNames[name] {
+		s.waitMu.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	s.waiters[name] = append(s.waiters[name], ch)
+	s.waitMu.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		s.waitMu.Lock()
+		defer s.waitMu.Unlock()
+		waiters := s.waiters[name]
+		for i, w := range waiters {
+			if w == ch {
+				s.waiters[name] = append(waiters[:i:i], waiters[i+1:]...)
+				break
+			}
+		}
+		if len(s.waiters[name]) == 0 {
+			delete(s.waiters, name)
+		}
+		return ctx.Err()
+	}
+}
+
+// setBuilt 记录服务的构建状态，服务构建完成时唤醒等待方
+func (s *Weave[T]) setBuilt(name string, built bool) {
+	s.waitMu.Lock()
+	defer s.waitMu.Unlock()
+
+	if !built {
+		delete(s.builtNames, name)
+		return
+	}
+	s.builtNames[name] = true
+	for _, ch := range s.waiters[name] {
+		close(ch)
+	}
+	delete(s.waiters, name)
+}
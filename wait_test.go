@@ -0,0 +1,169 @@
+package weave
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDI_WaitBuilt(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+
+	select {
+	case <-di.BuiltChan():
+		t.Fatal("构建前通道不应该关闭")
+	default:
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- di.WaitBuilt(context.Background()) }()
+
+	time.Sleep(10 * time.Millisecond)
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("构建成功后应该返回 nil: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("构建完成后 WaitBuilt 应该返回")
+	}
+	select {
+	case <-di.BuiltChan():
+	default:
+		t.Error("构建成功后通道应该关闭")
+	}
+}
+
+func TestDI_WaitBuiltRetry(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	fail := true
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		if fail {
+			return nil
+		}
+		return &ServiceA{Name: "ServiceA"}
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- di.WaitBuilt(context.Background()) }()
+	time.Sleep(10 * time.Millisecond)
+
+	if err := di.Build(); err == nil {
+		t.Fatal("构建应该失败")
+	}
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("构建失败时应该返回错误")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("构建失败后 WaitBuilt 应该返回")
+	}
+
+	fail = false
+	if err := di.Build(); err != nil {
+		t.Fatalf("重试构建失败: %v", err)
+	}
+	if err := di.WaitBuilt(context.Background()); err != nil {
+		t.Errorf("重试成功后应该返回 nil: %v", err)
+	}
+
+	// 重新注册服务后需要重新构建
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB"}
+	})
+	select {
+	case <-di.BuiltChan():
+		t.Error("重新注册服务后通道不应该处于关闭状态")
+	default:
+	}
+}
+
+func TestDI_WaitBuiltCancel(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := di.WaitBuilt(ctx); err != context.DeadlineExceeded {
+		t.Errorf("ctx 超时应该返回 DeadlineExceeded，实际为 %v", err)
+	}
+}
+
+func TestDI_WaitFor(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	release := make(chan struct{})
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		<-release
+		return &ServiceB{Name: "ServiceB", ServiceA: MustMake[TestContext, ServiceA](di, "serviceA")}
+	})
+	ProvideValue(di, "config", &ServiceA{Name: "config"})
+
+	if err := di.WaitFor(context.Background(), "config"); err != nil {
+		t.Errorf("ProvideValue 注册的服务应该立即返回: %v", err)
+	}
+	if got := strings.Join(di.UnbuiltServices(), ","); got != "serviceA,serviceB" {
+		t.Errorf("未构建的服务错误: %s", got)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- di.WaitFor(context.Background(), "serviceB") }()
+
+	// serviceB 的 builder 阻塞期间分批构建
+	built := make(chan struct{})
+	go func() {
+		defer close(built)
+		for {
+			if ok, err := di.BuildIncremental(time.Millisecond); ok || err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("服务构建完成前 WaitFor 不应该返回")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("服务构建完成后应该返回 nil: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("服务构建完成后 WaitFor 应该返回")
+	}
+	<-built
+
+	if got := strings.Join(di.BuiltServices(), ","); got != "config,serviceA,serviceB" {
+		t.Errorf("已构建的服务错误: %s", got)
+	}
+	if got := di.UnbuiltServices(); len(got) != 0 {
+		t.Errorf("不应该有未构建的服务: %v", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := di.WaitFor(ctx, "missing"); err != context.DeadlineExceeded {
+		t.Errorf("ctx 超时应该返回 DeadlineExceeded，实际为 %v", err)
+	}
+}
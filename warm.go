@@ -0,0 +1,109 @@
+package weave
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Warmer 构建完成后需要后台预热的服务（如缓存预加载）可实现此接口
+type Warmer interface {
+	Warm(ctx context.Context) error
+}
+
+// WarmState 服务的预热状态
+type WarmState struct {
+	// Warmed 是否已成功预热
+	Warmed bool
+	// Duration 预热耗时
+	Duration time.Duration
+	// Err 预热失败时的错误
+	Err error
+}
+
+// WarmError 预热失败的服务及其错误
+type WarmError struct {
+	Errors map[string]error
+}
+
+func (e *WarmError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("service [%s] warm failed: %v", name, e.Errors[name]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Warm 并发预热所有实现了 Warmer 的服务，parallelism 为最大并发数（<=0 表示不限制）
+// 单个服务的预热时间受 WithWarmTimeout 限制，所有失败会汇总到 *WarmError 中返回
+func (s *Weave[T]) Warm(ctx context.Context, parallelism int) error {
+	s.mu.RLock()
+	if !s.built {
+		s.mu.RUnlock()
+		return fmt.Errorf("cannot warm services before Build() is called")
+	}
+	warmers := make(map[string]Warmer)
+	s.entries.Range(func(name string, entry *entry[*T]) bool {
+		if w, ok := entry.instance.(Warmer); ok && entry.built && !entry.released {
+			warmers[name] = w
+		}
+		return true
+	})
+	s.mu.RUnlock()
+
+	if parallelism <= 0 {
+		parallelism = len(warmers)
+	}
+	sem := make(chan struct{}, parallelism)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(map[string]error)
+
+	for name, w := range warmers {
+		wg.Add(1)
+		go func(name string, w Warmer) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			wctx := ctx
+			if s.opts.warmTimeout > 0 {
+				var cancel context.CancelFunc
+				wctx, cancel = context.WithTimeout(ctx, s.opts.warmTimeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+			err := w.Warm(wctx)
+			state := WarmState{Warmed: err == nil, Duration: time.Since(start), Err: err}
+			s.warmStates.Set(name, state)
+
+			if err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name, w)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return &WarmError{Errors: errs}
+	}
+	return nil
+}
+
+// WarmState 返回服务的预热状态，服务未预热时返回 false
+func (s *Weave[T]) WarmState(name string) (WarmState, bool) {
+	return s.warmStates.Get(name)
+}
@@ -0,0 +1,92 @@
+package weave
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type warmCache struct {
+	Name    string
+	delay   time.Duration
+	err     error
+	running *int32
+	peak    *int32
+}
+
+func (c *warmCache) Warm(ctx context.Context) error {
+	if c.running != nil {
+		n := atomic.AddInt32(c.running, 1)
+		defer atomic.AddInt32(c.running, -1)
+		for {
+			p := atomic.LoadInt32(c.peak)
+			if n <= p || atomic.CompareAndSwapInt32(c.peak, p, n) {
+				break
+			}
+		}
+	}
+	select {
+	case <-time.After(c.delay):
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestDI_Warm(t *testing.T) {
+	di := New[TestContext](WithWarmTimeout(50 * time.Millisecond))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	var running, peak int32
+	for _, name := range []string{"cacheA", "cacheB", "cacheC"} {
+		name := name
+		Provide(di, name, func(ctx *TestContext) *warmCache {
+			return &warmCache{Name: name, delay: 5 * time.Millisecond, running: &running, peak: &peak}
+		})
+	}
+	Provide(di, "broken", func(ctx *TestContext) *warmCache {
+		return &warmCache{Name: "broken", err: errors.New("preload failed")}
+	})
+	Provide(di, "slow", func(ctx *TestContext) *warmCache {
+		return &warmCache{Name: "slow", delay: time.Second}
+	})
+	Provide(di, "plain", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "plain"}
+	})
+
+	if err := di.Warm(context.Background(), 1); err == nil {
+		t.Error("Build之前预热应该返回错误")
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	err := di.Warm(context.Background(), 1)
+	var warmErr *WarmError
+	if !errors.As(err, &warmErr) {
+		t.Fatalf("期望返回*WarmError，实际为: %v", err)
+	}
+	if len(warmErr.Errors) != 2 {
+		t.Errorf("期望2个服务预热失败，实际为: %v", warmErr.Errors)
+	}
+	if !errors.Is(warmErr.Errors["slow"], context.DeadlineExceeded) {
+		t.Errorf("慢服务应该因超时失败，实际为: %v", warmErr.Errors["slow"])
+	}
+	if atomic.LoadInt32(&peak) != 1 {
+		t.Errorf("并发数应该被限制为1，实际峰值为 %d", peak)
+	}
+
+	state, ok := di.WarmState("cacheA")
+	if !ok || !state.Warmed || state.Duration <= 0 {
+		t.Errorf("cacheA应该已预热并记录耗时: %+v", state)
+	}
+	if _, ok := di.WarmState("plain"); ok {
+		t.Error("未实现Warmer的服务不应该有预热状态")
+	}
+	info, _ := di.Info("broken")
+	if info.Warm == nil || info.Warm.Warmed || info.Warm.Err == nil {
+		t.Errorf("服务信息应该包含预热失败状态: %+v", info.Warm)
+	}
+}
@@ -1,164 +1,774 @@
 package weave
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/rand"
 	"reflect"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 // 服务容器状态
 type entry[T any] struct {
-	instance  any
-	builder   func(T) any
-	dependsOn []string // 依赖的服务名称
-	built     bool     // 是否已构建
+	instance any
+	builder  func(T) any
+	built    bool // 是否已构建
+	optional bool // 构建失败时是否跳过而不中断构建
+
+	tags     []string // 所属分组标签
+	priority int      // 分组内的优先级，越大越靠前
+	seq      uint64   // 注册顺序
+
+	providedBy string // 注册该服务的包路径
+
+	owner       string // 负责人，见 Describe
+	description string // 描述，见 Describe
+
+	equal func(old, new any) bool // 替换时判断新旧实例是否相等，见 EqualFunc
+
+	bridge *bridge // 从其他容器读取实例的桥接服务，见 UseExternal
+
+	cached func() (any, bool) // 构建前查询可复用的实例，见 ProvideCached
+
+	direct bool // 直接保存 builder 返回的指针，见 ProvideDirect
+	last   bool // 在其余服务之后构建，见 BuildLast
+
+	iface reflect.Type // 接口服务的接口类型，实例直接保存接口中的值，见 ProvideInterface
+
+	ctxGen uint64 // 构建时的上下文代数，0 表示未通过 builder 构建
+
+	guard *sync.RWMutex // 由容器管理的读写锁，见 Guard
+
+	importedEdges bool // 依赖关系从其他容器导入，构建时以新记录的为准，见 Import
+
+	extracted bool // 实例已通过 Extract 交给使用方，见 WithAllowLiveMutation
+
+	buildOnly bool // 仅在构建期间使用，见 ProvideBuildOnly
+	released  bool // 仅构建期服务的实例已被释放
+
+	building bool // builder 是否正在执行
+
+	seeded bool // 实例由 Seed 提供，见 Seed
 }
 
 type Weave[T any] struct {
 	ctx *T
+	// 上下文代数，SetCtx 传入不同的上下文时递增，见 CtxGeneration
+	ctxGen uint64
 
 	// 服务容器
 	entries *Map[string, *entry[*T]]
 
 	// 准备好后执行的函数
-	ready []func()
+	ready []readyHook
+
+	// 构建后检查的断言
+	assertions []func(*Weave[T]) error
 
 	// 是否已构建
 	built bool
+	// 服务实例的代数，见 Generation
+	generation uint64
+	// 缓存的无环判断结果，见 IsAcyclic
+	acyclicMu      sync.Mutex
+	acyclic        bool
+	acyclicVersion uint64
+	acyclicValid   bool
+	// Build 调用次数及最近一次是否为空操作，原子访问
+	buildCount    uint32
+	lastBuildNoop uint32
+
+	// 构建完成通知，见 WaitBuilt
+	waitMu   sync.Mutex
+	builtCh  chan struct{}
+	failedCh chan struct{}
+	buildErr error
+	// 已构建的服务及等待服务构建完成的通道，见 WaitFor
+	builtNames map[string]bool
+	waiters    map[string][]chan struct{}
+
+	// 本次构建中最先失败的服务及其解析链，见 MustBuild
+	failedChain []string
+	// 本次构建达到的最大解析深度，见 MaxResolutionDepth
+	maxDepth int
+
+	// 正在构建的服务栈，最内层在末尾
+	resolving []string
+
+	// 当前阶段，见 Phase
+	phase uint32
+	// Ready 阶段依赖边的发起方
+	readyRequester string
+
+	// 构建过程记录，见 WithBuildTrace
+	traceEvents  []TraceEvent
+	traceDropped int
+	traceMu      sync.Mutex
+
+	// 分批构建的进度，未开始分批构建时为 nil
+	incremental *incrementalBuild
 
 	// 服务获取函数（用于依赖注入）
 	getServiceFunc func(name string) (any, error)
 
+	// 自定义解析函数，设置后替代默认解析（用于测试）
+	resolver func(name string) (any, error)
+
+	// 依赖关系边表
+	edges *edgeTable
+
+	// 注册序号
+	seq uint64
+
+	// 已被压缩释放的数据
+	compacted uint8
+
+	// 服务完成构建的顺序，依赖总是先于依赖方
+	buildOrder []string
+
+	// 重命名转发表：旧名称 -> 新名称
+	renames *Map[string, string]
+
+	// 前缀委托：前缀 -> 目标容器
+	mounts *Map[string, Resolver]
+
+	// 通过委托解析的外部服务缓存
+	externals *Map[string, *entry[*T]]
+
+	// 未在当前 profile 下启用的服务 -> 其启用的 profile 列表
+	inactive *Map[string, []string]
+
+	// 注入的构建失败（用于混沌测试）
+	failures *Map[string, buildFailure]
+	chaos    *Map[string, *rand.Rand]
+
+	// 构建失败被跳过的可选服务
+	skipped *Map[string, error]
+
+	// 服务 -> 构建时以未完成的占位符形式拿到的依赖，见 PlaceholderResolutions
+	placeholderUses *Map[string, []string]
+
+	// 服务 -> 构建检查发现的问题，见 WithBuildChecks
+	checkFailures *Map[string, []error]
+
+	// 服务 -> 通过 TryMake 解析的可选依赖 -> 是否解析成功
+	optionalDeps *Map[string, map[string]bool]
+	// 构建期间记录可选依赖的解析结果，非构建期间为 nil
+	optionalHook func(name string, present bool)
+
+	// 服务预热状态
+	warmStates *Map[string, WarmState]
+
+	// ProvideValue 注册的实例指针 -> 服务名称列表
+	values *Map[uintptr, []string]
+
+	// 被禁用的服务
+	disabled *Map[string, bool]
+
+	// 严格模式下允许的循环依赖指纹
+	allowedCycles *Map[string, bool]
+
+	// 被其他容器通过 UseExternal 引用的服务 -> 引用方的服务名称
+	exports *Map[string, []string]
+
+	// 分组标签 -> 组内服务必须实现的接口，见 RequireGroupInterface
+	groupInterfaces *Map[string, []reflect.Type]
+
+	// 作用域服务，见 ProvideScoped
+	scoped *Map[string, func(*T, *Scope[T]) any]
+
+	opts options
+
+	// 构建过程中的日志输出，为 nil 时不输出
+	logf func(format string, args ...any)
+
+	// 容器编号，见 ID
+	id uint64
+
+	// 有序分组，见 OrderGroups
+	groupOrder []string
+	// 保护服务条目的分组标签和优先级；Group 可在 builder 中调用，无法使用容器锁
+	groupMu sync.RWMutex
+
 	mu sync.RWMutex
 }
 
-func New[T any]() *Weave[T] {
+func New[T any](opts ...Option) *Weave[T] {
 	s := new(Weave[T])
+	s.id = atomic.AddUint64(&containerSeq, 1)
 	s.entries = NewMap[string, *entry[*T]]()
+	s.edges = newEdgeTable()
+	s.renames = NewMap[string, string]()
+	s.values = NewMap[uintptr, []string]()
+	s.mounts = NewMap[string, Resolver]()
+	s.inactive = NewMap[string, []string]()
+	s.failures = NewMap[string, buildFailure]()
+	s.chaos = NewMap[string, *rand.Rand]()
+	s.warmStates = NewMap[string, WarmState]()
+	s.skipped = NewMap[string, error]()
+	s.placeholderUses = NewMap[string, []string]()
+	s.optionalDeps = NewMap[string, map[string]bool]()
+	s.checkFailures = NewMap[string, []error]()
+	s.externals = NewMap[string, *entry[*T]]()
+	s.allowedCycles = NewMap[string, bool]()
+	s.exports = NewMap[string, []string]()
+	s.groupInterfaces = NewMap[string, []reflect.Type]()
+	s.scoped = NewMap[string, func(*T, *Scope[T]) any]()
+	s.disabled = NewMap[string, bool]()
+	s.builtCh = make(chan struct{})
+	s.failedCh = make(chan struct{})
+	s.builtNames = make(map[string]bool)
+	s.waiters = make(map[string][]chan struct{})
+	s.opts = defaultOptions()
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
 
 	// 初始化服务获取函数
 	s.getServiceFunc = func(name string) (any, error) {
-		if entry, ok := s.entries.Get(name); ok {
-			return entry.instance, nil
+		ready := s.Phase() == PhaseReady
+		if s.resolver != nil {
+			if ready {
+				s.edges.addPhase(s.readyRequester, name, PhaseReady)
+			}
+			return s.resolver(name)
+		}
+		name, entry, err := s.lookup(name)
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("service [%s] not found", name)
+		if ready {
+			s.edges.addPhase(s.readyRequester, name, PhaseReady)
+			s.trace(TraceResolve, s.readyRequester, name, nil)
+		}
+		if err, ok := s.skipped.Get(name); ok {
+			return nil, err
+		}
+		if s.disabled.Contains(name) {
+			return nil, &ErrServiceDisabled{Name: name}
+		}
+		if entry.released {
+			return nil, &ErrBuildOnly{Name: name}
+		}
+		return s.placeholder(entry), nil
 	}
 
 	return s
 }
 
+// lookup 根据名称查找服务，处理重命名转发，返回服务的当前名称
+func (s *Weave[T]) lookup(name string) (string, *entry[*T], error) {
+	if e, ok := s.entries.Get(name); ok {
+		return name, e, nil
+	}
+	if e, ok, err := s.resolveMounted(name); ok {
+		return name, e, err
+	}
+	newName, ok := s.renames.Get(name)
+	if !ok {
+		return name, nil, s.notFound(name)
+	}
+	if s.opts.strictRenames {
+		return name, nil, fmt.Errorf("service [%s] has been renamed to [%s]", name, newName)
+	}
+	e, ok := s.entries.Get(newName)
+	if !ok {
+		return name, nil, s.notFound(name)
+	}
+	if s.opts.onDeprecated != nil {
+		s.opts.onDeprecated(DeprecationEvent{
+			OldName: name,
+			NewName: newName,
+			Caller:  callerLocation(),
+		})
+	}
+	return newName, e, nil
+}
+
+// notFound 返回服务不存在的错误
+func (s *Weave[T]) notFound(name string) error {
+	if profiles, ok := s.inactive.Get(name); ok {
+		return fmt.Errorf("service [%s] is disabled for profile [%s] (enabled for: %s)", name, s.opts.activeProfile, strings.Join(profiles, ", "))
+	}
+	if s.scoped.Contains(name) {
+		return fmt.Errorf("service [%s] is scoped, resolve it through a Scope", name)
+	}
+	if s.opts.onNotFound != nil {
+		available := s.entries.Keys()
+		sort.Strings(available)
+		return s.opts.onNotFound(name, available)
+	}
+	return &ErrServiceNotFound{Name: name, Suggestions: suggestNames(name, s.entries.Keys())}
+}
+
 func (s *Weave[T]) SetCtx(ctx *T) {
+	if ctx != s.ctx {
+		s.ctxGen++
+	}
 	s.ctx = ctx
 }
 
 // GetServiceFunc 获取服务函数供builder使用
 func (s *Weave[T]) GetService(name string) (any, error) {
+	if err := s.checkForeignResolve(name); err != nil {
+		return nil, err
+	}
 	return s.getServiceFunc(name)
 }
 
 func (s *Weave[T]) Ready(fn func()) {
-	s.ready = append(s.ready, fn)
+	s.ready = append(s.ready, readyHook{fn: fn})
 }
 
 // Auto 注册服务
 func (s *Weave[T]) assign(name string, placeholder any, builder func(*T) any) {
+	s.assignEntry(name, &entry[*T]{
+		builder:  builder,
+		instance: placeholder,
+		built:    false,
+	})
+}
+
+// assignEntry 注册已设置好选项的服务条目，条目在持有容器锁时才对其他调用方可见
+func (s *Weave[T]) assignEntry(name string, entry *entry[*T]) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	entry := &entry[*T]{
-		builder:   builder,
-		instance:  placeholder,
-		dependsOn: []string{},
-		built:     false,
+	if s.opts.lazyPlaceholders {
+		// 只保留类型，占位符在被引用时才分配
+		entry.instance = reflect.Zero(reflect.TypeOf(entry.instance)).Interface()
 	}
 
-	s.entries.Set(name, entry)
+	s.register(name, entry)
 	s.built = false // 标记需要重新构建
+	s.resetBuilt()
+}
+
+// register 写入服务条目，覆盖同名服务时保留其标签和优先级
+func (s *Weave[T]) register(name string, entry *entry[*T]) {
+	s.registerName(name)
+	if old, ok := s.entries.Get(name); ok {
+		s.forgetValue(name, old.instance)
+		s.edges.removeFrom(name)
+		entry.tags = old.tags
+		entry.priority = old.priority
+		entry.owner, entry.description = old.owner, old.description
+	}
+	s.seq++
+	entry.seq = s.seq
+	entry.providedBy = callerPackage()
+	s.placeholderUses.Delete(name)
+	s.optionalDeps.Delete(name)
+	s.entries.Set(name, entry)
+	s.setBuilt(name, entry.built)
+	s.edges.touch()
+}
+
+// registerName 普通服务与作用域服务共用的注册步骤
+func (s *Weave[T]) registerName(name string) {
+	if s.opts.onRegister != nil {
+		// 先于写入调用，回调 panic 时容器保持不变
+		s.opts.onRegister(name)
+	}
+	s.inactive.Delete(name)
+	// 重新注册重命名前的旧名称时，该名称属于新服务，不再转发
+	s.renames.Delete(name)
 }
 
 // Build 进行全量分析和构造所有服务
 func (s *Weave[T]) Build() error {
+	atomic.AddUint32(&s.buildCount, 1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buildLocked()
+}
+
+// BuildWith 设置上下文并构建容器
+// 已有服务基于另一个上下文构建时返回错误，而不是混用两个上下文
+func (s *Weave[T]) BuildWith(ctx *T) error {
+	atomic.AddUint32(&s.buildCount, 1)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if ctx == nil {
+		return errors.New("build context is nil")
+	}
+	if s.ctx != nil && s.ctx != ctx && s.builtWithCtx() {
+		return errors.New("weave is already built with a different context")
+	}
+	s.SetCtx(ctx)
+	return s.buildLocked()
+}
+
+// builtWithCtx 判断是否有服务已基于当前上下文构建
+func (s *Weave[T]) builtWithCtx() bool {
+	found := false
+	s.entries.Range(func(name string, e *entry[*T]) bool {
+		found = e.built && e.ctxGen > 0
+		return !found
+	})
+	return found
+}
+
+// buildLocked 执行 Build，调用方需持有锁
+func (s *Weave[T]) buildLocked() error {
+	noop := uint32(0)
+	if s.built {
+		noop = 1
+	}
+	atomic.StoreUint32(&s.lastBuildNoop, noop)
+
+	s.startAttempt()
+	err := s.runBuild()
+	s.finishAttempt(err)
+	return err
+}
+
+// BuildCount 返回 Build 被调用的次数，包括已构建时的空操作调用
+func (s *Weave[T]) BuildCount() int {
+	return int(atomic.LoadUint32(&s.buildCount))
+}
+
+// LastBuildNoop 返回最近一次 Build 是否因容器已构建而未做任何事
+func (s *Weave[T]) LastBuildNoop() bool {
+	return atomic.LoadUint32(&s.lastBuildNoop) == 1
+}
+
+// runBuild 执行一次构建
+func (s *Weave[T]) runBuild() error {
 	if s.built {
 		return nil // 已经构建过了
 	}
-	var err error
-	s.entries.Range(func(name string, entry *entry[*T]) bool {
-		err = s.build(name, entry)
-		return err == nil
-	})
+	if s.compacted&compactedCtx != 0 {
+		return &CompactError{Op: "build", Released: "context"}
+	}
+	if err := s.prepareBuild(); err != nil {
+		return err
+	}
+	names := s.buildSequence()
+	errs := make(map[string]error)
+	// 分两轮构建，BuildLast 标记的服务在其余服务之后构建
+	for _, last := range []bool{false, true} {
+		for _, name := range names {
+			entry, ok := s.entries.Get(name)
+			if !ok || entry.last != last {
+				continue
+			}
+			err := s.build(name, entry)
+			if err == nil || entry.optional {
+				continue
+			}
+			if s.opts.collectErrors {
+				errs[name] = err
+				continue
+			}
+			return s.shuffleFailure(err)
+		}
+	}
+	return s.shuffleFailure(s.finishBuild(errs))
+}
+
+// prepareBuild 构建前的校验与状态重置
+func (s *Weave[T]) prepareBuild() error {
+	if s.entries.IsEmpty() && s.scoped.IsEmpty() && !s.opts.allowEmpty {
+		return &ErrNoServices{}
+	}
+	if err := s.validateMounts(); err != nil {
+		return err
+	}
+	if err := s.validateAutowire(); err != nil {
+		return err
+	}
+	if err := s.validateGroupInterfaces(); err != nil {
+		return err
+	}
+	s.setPhase(PhaseBuilding)
+	s.skipped.Clear()
+	s.traceEvents, s.traceDropped = nil, 0
+	s.maxDepth = 0
+	return nil
+}
+
+// finishBuild 所有服务处理完毕后汇总错误并执行 Ready 回调
+func (s *Weave[T]) finishBuild(errs map[string]error) error {
+	s.incremental = nil
+	if len(errs) > 0 {
+		return &BuildError{Errors: errs}
+	}
+	if s.opts.strictCycles {
+		if err := s.checkCycles(); err != nil {
+			return err
+		}
+	}
+	if err := s.checkGroupOrder(); err != nil {
+		return err
+	}
+	if err := s.checkLint(); err != nil {
+		return err
+	}
+	if err := s.checkCtxGenerations(); err != nil {
+		return err
+	}
+	order, err := s.readyOrder()
 	if err != nil {
 		return err
 	}
 	s.built = true
-	for _, fn := range s.ready {
-		fn()
+	if err := s.runAssertions(); err != nil {
+		s.built = false
+		return err
+	}
+	s.setPhase(PhaseReady)
+	defer s.setPhase(PhaseRuntime)
+	if err := s.runReady(order); err != nil {
+		// 与断言失败一致，保持未构建状态以便再次 Build 重试失败的回调
+		s.built = false
+		return err
 	}
+	s.resetReady()
+	s.releaseBuildOnly()
+	s.isAcyclic()
+	s.generation++
 	return nil
 }
 
 func (s *Weave[T]) build(name string, entry *entry[*T]) error {
-	if entry.built {
+	if entry.built || s.disabled.Contains(name) {
+		return nil
+	}
+	if err, ok := s.skipped.Get(name); ok {
+		return err
+	}
+	s.trace(TraceBuildStart, name, "", nil)
+	err := s.buildEntry(name, entry)
+	if err != nil {
+		s.trace(TraceBuildFailed, name, "", err)
+		if s.failedChain == nil && !entry.optional {
+			s.failedChain = append(append([]string{}, s.resolving...), name)
+		}
+	} else {
+		s.trace(TraceBuildEnd, name, "", nil)
+	}
+	if err != nil && entry.optional {
+		s.skipped.Set(name, err)
+		if s.logf != nil {
+			s.logf("weave: optional service [%s] skipped: %v", name, err)
+		}
+	}
+	return err
+}
+
+func (s *Weave[T]) buildEntry(name string, entry *entry[*T]) error {
+	if entry.bridge != nil {
+		return s.buildBridge(name, entry)
+	}
+	if entry.importedEdges {
+		s.edges.removeFrom(name)
+		entry.importedEdges = false
+	}
+	if entry.cached != nil && s.buildCached(name, entry) {
 		return nil
 	}
+	if entry.builder == nil {
+		return &CompactError{Op: fmt.Sprintf("build service [%s]", name), Released: "builder"}
+	}
+	if err := s.injectedFailure(name); err != nil {
+		return fmt.Errorf("service [%s] build failed: %w", name, err)
+	}
 
-	originalFunc := s.getServiceFunc
+	originalFunc, originalHook := s.getServiceFunc, s.optionalHook
+	defer func() { s.getServiceFunc, s.optionalHook = originalFunc, originalHook }()
+	service := name
 
-	s.getServiceFunc = func(name string) (any, error) {
-		e, ok := s.entries.Get(name)
+	s.optionalHook = func(name string, present bool) {
+		deps, ok := s.optionalDeps.Get(service)
 		if !ok {
-			return nil, fmt.Errorf("service [%s] not found", name)
+			deps = make(map[string]bool)
+			s.optionalDeps.Set(service, deps)
 		}
-		entry.dependsOn = append(entry.dependsOn, name)
+		deps[name] = present
+	}
+	// 最近一次依赖解析失败的原因，builder 因此返回 nil 时附加到错误中
+	var resolveErr error
+
+	frame, leave := s.enterBuilder(name)
+	defer leave()
+
+	s.getServiceFunc = func(name string) (obj any, err error) {
+		frame.delegate(1)
+		defer func() {
+			frame.delegate(-1)
+			if err != nil {
+				resolveErr = err
+			}
+		}()
+		if s.resolver != nil {
+			s.edges.add(service, name)
+			s.trace(TraceResolve, service, name, nil)
+			return s.resolver(name)
+		}
+		name, e, err := s.lookup(name)
+		if err != nil {
+			s.trace(TraceResolveFailed, service, name, err)
+			return nil, err
+		}
+		s.edges.add(service, name)
+		if e.last && !entry.last {
+			err := &ErrBuildLastDependency{Name: service, Dependency: name}
+			s.trace(TraceResolveFailed, service, name, err)
+			return nil, err
+		}
+		if s.disabled.Contains(name) {
+			s.trace(TraceResolveFailed, service, name, &ErrServiceDisabled{Name: name})
+			return nil, &ErrServiceDisabled{Name: name}
+		}
+		if e.released {
+			s.trace(TraceResolveFailed, service, name, &ErrBuildOnly{Name: name})
+			return nil, &ErrBuildOnly{Name: name}
+		}
+		if e.building {
+			s.trace(TracePlaceholder, service, name, nil)
+			if s.opts.cycleErrors || e.iface != nil {
+				// 接口服务没有可以提前交出的占位符
+				return nil, s.inProgress(name)
+			}
+			if uses, _ := s.placeholderUses.Get(service); !containsString(uses, name) {
+				s.placeholderUses.Set(service, append(uses, name))
+			}
+			return s.placeholder(e), nil
+		}
+		s.trace(TraceResolve, service, name, nil)
 		if !e.built {
 			if err := s.build(name, e); err != nil {
 				return nil, err
 			}
 		}
-		return e.instance, nil
+		return s.placeholder(e), nil
 	}
 
+	s.placeholderUses.Delete(name)
+	s.optionalDeps.Delete(name)
+	s.checkFailures.Delete(name)
+	s.resolving = append(s.resolving, name)
+	if len(s.resolving) > s.maxDepth {
+		s.maxDepth = len(s.resolving)
+	}
+	entry.building = true
+	defer func() {
+		s.resolving = s.resolving[:len(s.resolving)-1]
+		entry.building = false
+	}()
+
 	entry.built = true
-	instance := entry.builder(s.ctx)
+	entry.ctxGen = s.ctxGen
+	posts := s.startChecks(name)
+	instance, err := s.callBuilder(name, entry)
+	s.finishChecks(name, posts)
+	if err != nil {
+		entry.built = false
+		return err
+	}
 	if instance == nil {
 		entry.built = false
+		if resolveErr != nil {
+			return fmt.Errorf("service [%s] build failed: %w", name, resolveErr)
+		}
 		return fmt.Errorf("service [%s] build failed", name)
 	}
+	if err := s.autowire(name, instance); err != nil {
+		entry.built = false
+		return err
+	}
 
-	// 通过反射设置实例
-	vo := reflect.ValueOf(instance)
-	reflect.ValueOf(entry.instance).Elem().Set(vo.Elem())
-
-	s.getServiceFunc = originalFunc
+	if isNilPointer(entry.instance) {
+		// 延迟分配模式下没有被循环引用，直接保存构建结果
+		entry.instance = instance
+	} else {
+		// 通过反射设置实例
+		vo := reflect.ValueOf(instance)
+		reflect.ValueOf(entry.instance).Elem().Set(vo.Elem())
+	}
+	s.buildOrder = append(s.buildOrder, name)
+	s.setBuilt(name, true)
 	return nil
 }
 
 func Provide[T any, R any](di *Weave[T], name string, builder func(*T) *R) {
-	di.assign(name, new(R), func(ctx *T) any {
-		return builder(ctx)
+	di.assign(name, new(R), pointerBuilder(builder))
+}
+
+// pointerBuilder 将返回 *R 的 builder 转换为容器内部使用的 builder
+func pointerBuilder[T any, R any](builder func(*T) *R) func(*T) any {
+	return func(ctx *T) any {
+		// 避免 nil 指针被包装成非 nil 的 any
+		if instance := builder(ctx); instance != nil {
+			return instance
+		}
+		return nil
+	}
+}
+
+// ProvideNamedBuilder 注册服务，builder 接收服务注册的名称，便于日志和指标标签使用
+func ProvideNamedBuilder[T any, R any](di *Weave[T], name string, builder func(ctx *T, name string) *R) {
+	Provide(di, name, func(ctx *T) *R {
+		return builder(ctx, name)
 	})
 }
 
+// ProvideN 使用同一个 builder 批量注册服务，builder 通过参数获取各自的名称
+func ProvideN[T any, R any](di *Weave[T], names []string, builder func(ctx *T, name string) *R) {
+	for _, name := range names {
+		ProvideNamedBuilder(di, name, builder)
+	}
+}
+
 // 工具函数
 func MustMake[T any, R any](di *Weave[T], name string) *R {
-	obj, err := di.GetService(name)
-	if err != nil {
-		panic(err)
-	}
-	return obj.(*R)
+	return MustResolve[R](di, name)
 }
 
+// TryMake 获取服务，服务不存在或构建失败时返回 false
+// 在 builder 中调用时，依赖作为可选依赖记录到依赖图谱中，并记录本次构建中是否存在
 func TryMake[T any, R any](di *Weave[T], name string) (*R, bool) {
+	return TryResolve[R](di, name)
+}
+
+// MakeOr 获取服务，服务不存在或构建失败时返回 fallback，可选依赖的记录同 TryMake
+func MakeOr[T any, R any](di *Weave[T], name string, fallback *R) *R {
+	return ResolveOr(di, name, fallback)
+}
+
+// recordOptional 在 builder 中解析可选依赖时记录其是否存在，非构建期间不做任何事
+func (s *Weave[T]) recordOptional(name string, present bool) {
+	if hook := s.optionalHook; hook != nil {
+		hook(name, present)
+	}
+}
+
+// MakeOrZero 获取服务，服务未注册时返回新分配的零值 *R，适合以零值为合理默认值的配置结构体
+// 零值不会注册到容器中，每次调用都返回新的实例；服务已注册但构建失败或类型不符时与 MustMake 一样 panic；
+// 在 builder 中调用时按可选依赖记录
+func MakeOrZero[T any, R any](di *Weave[T], name string) *R {
 	obj, err := di.GetService(name)
+	var notFound *ErrServiceNotFound
+	if errors.As(err, &notFound) && notFound.Name == name {
+		di.recordOptional(name, false)
+		return new(R)
+	}
 	if err != nil {
-		return nil, false
+		panic(err)
 	}
 	result, ok := obj.(*R)
-	return result, ok
+	if !ok {
+		panic(&ErrTypeMismatch{Name: name, Want: fmt.Sprintf("%T", result), Got: fmt.Sprintf("%T", obj)})
+	}
+	di.recordOptional(name, true)
+	return result
 }
 
 // DependencyGraph 依赖图谱结构
@@ -167,6 +777,94 @@ type DependencyGraph struct {
 	Dependencies map[string][]string
 	// Dependents 每个服务的被依赖列表
 	Dependents map[string][]string
+	// Renamed 已重命名服务的旧名称列表
+	Renamed map[string][]string
+	// External 通过 Mount 委托给其他容器解析的外部服务
+	External map[string]bool
+	// Bridged 通过 UseExternal 从其他容器读取的服务 -> 在来源容器中的名称
+	Bridged map[string]string
+	// Exported 被其他容器通过 UseExternal 引用的服务 -> 引用方的服务名称
+	Exported map[string][]string
+	// BuildOnly 仅在构建期间使用的服务，见 ProvideBuildOnly
+	BuildOnly map[string]bool
+	// Optional 服务通过 TryMake/MakeOr 解析的可选依赖 -> 本次构建中是否存在
+	// 不存在的可选依赖不出现在 Dependencies 中
+	Optional map[string]map[string]bool
+	// ProvidedBy 注册每个服务的包路径
+	ProvidedBy map[string]string
+}
+
+// Category 服务在依赖图谱中的分类
+type Category string
+
+const (
+	// CategoryRoot 根服务：无依赖，但被其他服务依赖
+	CategoryRoot Category = "root"
+	// CategoryLeaf 叶服务：有依赖，但不被其他服务依赖
+	CategoryLeaf Category = "leaf"
+	// CategoryMiddle 中间服务：既有依赖，也被其他服务依赖
+	CategoryMiddle Category = "middle"
+	// CategoryIsolated 孤立服务：既无依赖，也不被其他服务依赖
+	CategoryIsolated Category = "isolated"
+)
+
+// Category 返回服务在图谱中的分类
+func (g *DependencyGraph) Category(service string) Category {
+	deps := len(g.Dependencies[service])
+	dependents := len(g.Dependents[service])
+
+	switch {
+	case deps == 0 && dependents == 0:
+		return CategoryIsolated
+	case deps == 0:
+		return CategoryRoot
+	case dependents == 0:
+		return CategoryLeaf
+	default:
+		return CategoryMiddle
+	}
+}
+
+// label 返回服务的显示名称，已重命名的服务附带旧名称
+func (g *DependencyGraph) label(service string) string {
+	if olds := g.Renamed[service]; len(olds) > 0 {
+		return fmt.Sprintf("%s (原 %s)", service, strings.Join(olds, ", "))
+	}
+	return service
+}
+
+// String 返回图谱的紧凑文本表示，每行一个服务，按名称排序
+// 格式为 "服务: -> 依赖; <- 被依赖"，外部服务附带 (external) 标记
+func (g *DependencyGraph) String() string {
+	services := make([]string, 0, len(g.Dependencies)+len(g.External))
+	for service := range g.Dependencies {
+		services = append(services, service)
+	}
+	for service := range g.External {
+		if _, ok := g.Dependencies[service]; !ok {
+			services = append(services, service)
+		}
+	}
+	sort.Strings(services)
+
+	list := func(names []string) string {
+		if len(names) == 0 {
+			return "(无)"
+		}
+		sorted := append([]string(nil), names...)
+		sort.Strings(sorted)
+		return strings.Join(sorted, ", ")
+	}
+
+	var builder strings.Builder
+	for _, service := range services {
+		builder.WriteString(service)
+		if g.External[service] {
+			builder.WriteString(" (external)")
+		}
+		builder.WriteString(fmt.Sprintf(": -> %s; <- %s\n", list(g.Dependencies[service]), list(g.Dependents[service])))
+	}
+	return builder.String()
 }
 
 // GetDependencyGraph 获取完整的依赖图谱
@@ -174,40 +872,112 @@ func (s *Weave[T]) GetDependencyGraph() *DependencyGraph {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	dependencies := make(map[string][]string)
-	dependents := make(map[string][]string)
-
-	// 初始化所有服务
-	s.entries.Range(func(name string, entry *entry[*T]) bool {
-		dependencies[name] = make([]string, len(entry.dependsOn))
-		copy(dependencies[name], entry.dependsOn)
+	return s.dependencyGraph()
+}
 
-		if dependents[name] == nil {
-			dependents[name] = []string{}
+// dependencyGraph 生成依赖图谱，调用方需持有锁
+func (s *Weave[T]) dependencyGraph() *DependencyGraph {
+	dependencies, dependents := s.edges.materialize(s.entries.Keys())
+
+	providedBy := make(map[string]string, len(dependencies))
+	bridged := make(map[string]string)
+	buildOnly := make(map[string]bool)
+	s.entries.Range(func(name string, e *entry[*T]) bool {
+		providedBy[name] = e.providedBy
+		if e.bridge != nil {
+			bridged[name] = e.bridge.source
+		}
+		if e.buildOnly {
+			buildOnly[name] = true
 		}
 		return true
 	})
 
-	// 构建被依赖关系
-	for serviceName, deps := range dependencies {
-		for _, dep := range deps {
-			if dependents[dep] == nil {
-				dependents[dep] = []string{}
-			}
-			dependents[dep] = append(dependents[dep], serviceName)
+	exported := make(map[string][]string)
+	s.exports.Range(func(name string, consumers []string) bool {
+		exported[name] = append([]string(nil), consumers...)
+		return true
+	})
+
+	external := make(map[string]bool)
+	for name := range dependents {
+		if _, ok := dependencies[name]; !ok {
+			external[name] = true
 		}
 	}
 
-	// 排序以确保输出一致性
-	for name := range dependencies {
-		sort.Strings(dependencies[name])
-		sort.Strings(dependents[name])
+	renamed := make(map[string][]string)
+	s.renames.Range(func(from, to string) bool {
+		renamed[to] = append(renamed[to], from)
+		return true
+	})
+	for _, olds := range renamed {
+		sort.Strings(olds)
 	}
 
+	optional := make(map[string]map[string]bool)
+	s.optionalDeps.Range(func(name string, deps map[string]bool) bool {
+		copied := make(map[string]bool, len(deps))
+		for dep, present := range deps {
+			copied[dep] = present
+		}
+		optional[name] = copied
+		return true
+	})
+
 	return &DependencyGraph{
 		Dependencies: dependencies,
 		Dependents:   dependents,
+		Renamed:      renamed,
+		External:     external,
+		Bridged:      bridged,
+		Exported:     exported,
+		BuildOnly:    buildOnly,
+		Optional:     optional,
+		ProvidedBy:   providedBy,
+	}
+}
+
+// absentOptional 返回服务本次构建中不存在的可选依赖，已排序
+func (g *DependencyGraph) absentOptional(service string) []string {
+	absent := []string{}
+	for dep, present := range g.Optional[service] {
+		if !present {
+			absent = append(absent, dep)
+		}
+	}
+	sort.Strings(absent)
+	return absent
+}
+
+// Category 返回服务在依赖图谱中的分类，服务不存在时返回 false
+func (s *Weave[T]) Category(name string) (Category, bool) {
+	graph := s.GetDependencyGraph()
+	if _, ok := graph.Dependencies[name]; !ok {
+		return "", false
+	}
+	return graph.Category(name), true
+}
+
+// IsAcyclic 判断依赖图谱是否无环
+// 结果在 Build 完成时计算并缓存，依赖关系或服务注册变化后下一次调用时重新计算，适合频繁调用的健康检查
+func (s *Weave[T]) IsAcyclic() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.isAcyclic()
+}
+
+// isAcyclic 返回缓存的无环判断结果，过期时重新计算，调用方需持有锁
+func (s *Weave[T]) isAcyclic() bool {
+	version := s.edges.currentVersion()
+	s.acyclicMu.Lock()
+	defer s.acyclicMu.Unlock()
+	if s.acyclicValid && s.acyclicVersion == version {
+		return s.acyclic
 	}
+	hasCycle, _ := s.detectCircularDependency(s.dependencyGraph().Dependencies)
+	s.acyclic, s.acyclicVersion, s.acyclicValid = !hasCycle, version, true
+	return s.acyclic
 }
 
 // HasCircularDependency 检测是否存在循环依赖
@@ -218,24 +988,49 @@ func (s *Weave[T]) HasCircularDependency() (bool, []string) {
 
 // GetAllCircularDependencies 获取所有循环依赖路径
 func (s *Weave[T]) GetAllCircularDependencies() [][]string {
-	graph := s.GetDependencyGraph()
+	return s.allCycles(s.GetDependencyGraph())
+}
+
+// CyclesCtx 与 GetAllCircularDependencies 相同，ctx 取消或超时时停止查找，
+// 返回已找到的循环及 *ErrTruncated，适合在调试接口中处理规模不可控的图谱
+func (s *Weave[T]) CyclesCtx(ctx context.Context) ([][]string, error) {
+	return s.allCyclesCtx(ctx, s.GetDependencyGraph())
+}
+
+// allCycles 查找图谱中的所有循环依赖路径
+func (s *Weave[T]) allCycles(graph *DependencyGraph) [][]string {
+	cycles, _ := s.allCyclesCtx(context.Background(), graph)
+	return cycles
+}
+
+// allCyclesCtx 查找图谱中的所有循环依赖路径，ctx 结束时返回已找到的循环及 *ErrTruncated
+func (s *Weave[T]) allCyclesCtx(ctx context.Context, graph *DependencyGraph) ([][]string, error) {
 	allCycles := [][]string{}
 	visited := make(map[string]bool)
 
 	for node := range graph.Dependencies {
+		if err := ctx.Err(); err != nil {
+			return s.deduplicateCycles(allCycles), &ErrTruncated{Cause: err}
+		}
 		if !visited[node] {
-			cycles := s.findAllCyclesFromNode(node, graph.Dependencies, make(map[string]bool), make(map[string]bool), []string{})
+			cycles := s.findAllCyclesFromNode(ctx, node, graph.Dependencies, make(map[string]bool), make(map[string]bool), []string{})
 			allCycles = append(allCycles, cycles...)
 			visited[node] = true
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		return s.deduplicateCycles(allCycles), &ErrTruncated{Cause: err}
+	}
 
-	return s.deduplicateCycles(allCycles)
+	return s.deduplicateCycles(allCycles), nil
 }
 
-// findAllCyclesFromNode 从指定节点查找所有循环
-func (s *Weave[T]) findAllCyclesFromNode(node string, dependencies map[string][]string, visited, visiting map[string]bool, path []string) [][]string {
+// findAllCyclesFromNode 从指定节点查找所有循环，ctx 结束时停止搜索
+func (s *Weave[T]) findAllCyclesFromNode(ctx context.Context, node string, dependencies map[string][]string, visited, visiting map[string]bool, path []string) [][]string {
 	cycles := [][]string{}
+	if ctx.Err() != nil {
+		return cycles
+	}
 
 	if visiting[node] {
 		// 找到循环，构建循环路径
@@ -263,7 +1058,7 @@ func (s *Weave[T]) findAllCyclesFromNode(node string, dependencies map[string][]
 	path = append(path, node)
 
 	for _, dep := range dependencies[node] {
-		subCycles := s.findAllCyclesFromNode(dep, dependencies, visited, visiting, path)
+		subCycles := s.findAllCyclesFromNode(ctx, dep, dependencies, visited, visiting, path)
 		cycles = append(cycles, subCycles...)
 	}
 
@@ -370,9 +1165,13 @@ func (s *Weave[T]) detectCircularDependency(dependencies map[string][]string) (b
 	return false, nil
 }
 
-// GenerateDOTGraph 生成DOT格式的依赖图，可用于Graphviz可视化
-func (s *Weave[T]) GenerateDOTGraph() string {
+// GenerateDOTGraph 生成DOT格式的依赖图，可用于Graphviz可视化，DOTStable 选项用于 golden 文件比较
+func (s *Weave[T]) GenerateDOTGraph(opts ...DOTOption) string {
 	graph := s.GetDependencyGraph()
+	o := dotOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
 
 	var builder strings.Builder
 	builder.WriteString("digraph DependencyGraph {\n")
@@ -383,7 +1182,11 @@ func (s *Weave[T]) GenerateDOTGraph() string {
 	hasCycle, _ := s.detectCircularDependency(graph.Dependencies)
 	allCycles := [][]string{}
 	if hasCycle {
-		allCycles = s.GetAllCircularDependencies()
+		if o.stable {
+			allCycles = s.stableCycles(graph)
+		} else {
+			allCycles = s.GetAllCircularDependencies()
+		}
 	}
 
 	// 创建循环节点集合
@@ -409,52 +1212,196 @@ func (s *Weave[T]) GenerateDOTGraph() string {
 	}
 	sort.Strings(services)
 
-	builder.WriteString("\n  // 节点定义\n")
-	for _, service := range services {
+	// 节点属性
+	nodeAttrs := func(service string) string {
+		if source, ok := graph.Bridged[service]; ok {
+			// 从其他容器读取的服务按外部服务显示
+			return fmt.Sprintf("[fillcolor=white, style=\"filled,dashed\", label=\"🌐 %s <- %s\"]", graph.label(service), source)
+		}
+		if graph.BuildOnly[service] && !cycleNodes[service] {
+			// 仅构建期服务（灰色）
+			return fmt.Sprintf("[fillcolor=gray90, fontcolor=gray40, style=\"filled,dotted\", label=\"🔧 %s\"]", graph.label(service))
+		}
 		if cycleNodes[service] {
 			// 循环依赖中的节点用红色突出显示
-			builder.WriteString(fmt.Sprintf("  \"%s\" [fillcolor=lightcoral, label=\"⚠️ %s\"];\n", service, service))
-		} else {
-			// 普通节点
-			deps := len(graph.Dependencies[service])
-			dependents := len(graph.Dependents[service])
-
-			if deps == 0 && dependents > 0 {
-				// 根节点（绿色）
-				builder.WriteString(fmt.Sprintf("  \"%s\" [fillcolor=lightgreen, label=\"🌱 %s\"];\n", service, service))
-			} else if deps > 0 && dependents == 0 {
-				// 叶节点（黄色）
-				builder.WriteString(fmt.Sprintf("  \"%s\" [fillcolor=lightyellow, label=\"🍃 %s\"];\n", service, service))
-			} else {
-				// 中间节点（蓝色）
-				builder.WriteString(fmt.Sprintf("  \"%s\" [fillcolor=lightblue];\n", service))
+			return fmt.Sprintf("[fillcolor=lightcoral, label=\"⚠️ %s\"]", graph.label(service))
+		}
+		// 普通节点
+		switch graph.Category(service) {
+		case CategoryRoot:
+			// 根节点（绿色）
+			return fmt.Sprintf("[fillcolor=lightgreen, label=\"🌱 %s\"]", graph.label(service))
+		case CategoryLeaf:
+			// 叶节点（黄色）
+			return fmt.Sprintf("[fillcolor=lightyellow, label=\"🍃 %s\"]", graph.label(service))
+		case CategoryIsolated:
+			// 孤立节点（灰色）
+			return fmt.Sprintf("[fillcolor=lightgray, label=\"⚪ %s\"]", graph.label(service))
+		default:
+			// 中间节点（蓝色）
+			return fmt.Sprintf("[fillcolor=lightblue, label=\"%s\"]", graph.label(service))
+		}
+	}
+
+	if o.instanceIDs {
+		attrs := nodeAttrs
+		nodeAttrs = func(service string) string {
+			a := attrs(service)
+			if id, ok := s.InstanceID(service); ok {
+				a = strings.TrimSuffix(a, "]") + fmt.Sprintf(", tooltip=\"instance %s\"]", id)
+			}
+			return a
+		}
+	}
+
+	builder.WriteString("\n  // 节点定义\n")
+	if o.clusterByPackage {
+		// 按注册服务的包分簇
+		packages := []string{}
+		members := make(map[string][]string)
+		for _, service := range services {
+			pkg := graph.ProvidedBy[service]
+			if _, ok := members[pkg]; !ok {
+				packages = append(packages, pkg)
+			}
+			members[pkg] = append(members[pkg], service)
+		}
+		sort.Strings(packages)
+
+		for i, pkg := range packages {
+			builder.WriteString(fmt.Sprintf("  subgraph \"cluster_%d\" {\n", i))
+			builder.WriteString(fmt.Sprintf("    label=\"%s\";\n", pkg))
+			builder.WriteString("    style=dashed;\n")
+			for _, service := range members[pkg] {
+				builder.WriteString(fmt.Sprintf("    \"%s\" %s;\n", service, nodeAttrs(service)))
+			}
+			builder.WriteString("  }\n")
+		}
+	} else {
+		for _, service := range services {
+			builder.WriteString(fmt.Sprintf("  \"%s\" %s;\n", service, nodeAttrs(service)))
+		}
+	}
+
+	// 同一拓扑层级的节点排在同一行
+	if o.rankByLevel {
+		builder.WriteString("\n  // 拓扑层级\n")
+		for _, names := range dependencyLevels(graph.Dependencies) {
+			if len(names) == 0 {
+				continue
+			}
+			builder.WriteString("  { rank=same;")
+			for _, service := range names {
+				builder.WriteString(fmt.Sprintf(" \"%s\";", service))
 			}
+			builder.WriteString(" }\n")
+		}
+	}
+
+	// 外部服务节点（虚线框）
+	if len(graph.External) > 0 {
+		externals := make([]string, 0, len(graph.External))
+		for service := range graph.External {
+			externals = append(externals, service)
+		}
+		sort.Strings(externals)
+
+		builder.WriteString("\n  // 外部服务节点\n")
+		for _, service := range externals {
+			builder.WriteString(fmt.Sprintf("  \"%s\" [fillcolor=white, style=\"filled,dashed\", label=\"🌐 %s\"];\n", service, service))
 		}
 	}
 
 	builder.WriteString("\n  // 依赖关系边\n")
 
 	// 添加依赖关系边
+	edges := []string{}
 	for _, service := range services {
 		for _, dep := range graph.Dependencies[service] {
-			edge := fmt.Sprintf("%s->%s", dep, service)
+			// 循环路径按依赖方向记录：service 依赖 dep
+			edge := fmt.Sprintf("%s->%s", service, dep)
+			present, optional := graph.Optional[service][dep]
 			if cycleEdges[edge] {
 				// 循环依赖边用红色粗线显示
-				builder.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\" [color=red, penwidth=2.0, label=\"⚠️\"];\n", dep, service))
+				edges = append(edges, fmt.Sprintf("  \"%s\" -> \"%s\" [color=red, penwidth=2.0, label=\"⚠️\"];\n", dep, service))
+			} else if optional && present {
+				// 存在的可选依赖
+				edges = append(edges, fmt.Sprintf("  \"%s\" -> \"%s\" [style=dashed, label=\"optional\"];\n", dep, service))
+			} else if optional {
+				// 已注册但构建失败的可选依赖
+				edges = append(edges, fmt.Sprintf("  \"%s\" -> \"%s\" [style=dotted, color=gray, label=\"optional (absent)\"];\n", dep, service))
 			} else {
 				// 普通依赖边
-				builder.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\";\n", dep, service))
+				edges = append(edges, fmt.Sprintf("  \"%s\" -> \"%s\";\n", dep, service))
 			}
 		}
 	}
+	if o.stable {
+		sort.Strings(edges)
+	}
+	for _, edge := range edges {
+		builder.WriteString(edge)
+	}
 
-	// 如果有循环依赖，添加说明
-	if len(allCycles) > 0 {
+	// 分组顺序约束（蓝色点线）
+	if o.groupOrder && len(s.groupOrder) > 0 {
+		builder.WriteString("\n  // 分组顺序\n")
+		waves := s.groupWaves()
+		members := make([][]string, len(s.groupOrder))
+		for _, service := range services {
+			if w, ok := waves[service]; ok {
+				members[w] = append(members[w], service)
+			}
+		}
+		for w := 1; w < len(members); w++ {
+			for _, before := range members[w-1] {
+				for _, after := range members[w] {
+					builder.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\" [style=dotted, color=blue, label=\"order\"];\n", before, after))
+				}
+			}
+		}
+	}
+
+	// 未注册的可选依赖（灰色虚线）
+	absentNodes := make(map[string]bool)
+	for _, service := range services {
+		for _, dep := range graph.absentOptional(service) {
+			if _, registered := graph.Dependencies[dep]; registered {
+				continue
+			}
+			if !absentNodes[dep] {
+				absentNodes[dep] = true
+				builder.WriteString(fmt.Sprintf("  \"%s\" [fillcolor=white, fontcolor=gray40, style=\"filled,dotted\", label=\"❔ %s\"];\n", dep, dep))
+			}
+			builder.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\" [style=dotted, color=gray, label=\"optional (absent)\"];\n", dep, service))
+		}
+	}
+
+	// 被其他容器引用的服务，引用方按外部节点显示
+	if len(graph.Exported) > 0 {
+		exported := make([]string, 0, len(graph.Exported))
+		for service := range graph.Exported {
+			exported = append(exported, service)
+		}
+		sort.Strings(exported)
+
+		builder.WriteString("\n  // 跨容器引用\n")
+		for _, service := range exported {
+			for _, consumer := range graph.Exported[service] {
+				builder.WriteString(fmt.Sprintf("  \"external:%s\" [fillcolor=white, style=\"filled,dashed\", label=\"🌐 %s\"];\n", consumer, consumer))
+				builder.WriteString(fmt.Sprintf("  \"%s\" -> \"external:%s\" [style=dashed];\n", service, consumer))
+			}
+		}
+	}
+
+	// 如果有循环依赖，添加说明；稳定输出模式下始终输出
+	if len(allCycles) > 0 || o.stable {
 		builder.WriteString("\n  // 循环依赖说明\n")
 		builder.WriteString("  legend [shape=box, style=filled, fillcolor=lightyellow, label=\"")
 		builder.WriteString("图例:\\n")
 		builder.WriteString("🌱 = 根服务 (无依赖)\\n")
 		builder.WriteString("🍃 = 叶服务 (无被依赖)\\n")
+		builder.WriteString("⚪ = 孤立服务 (无依赖且无被依赖)\\n")
 		builder.WriteString("⚠️  = 循环依赖节点\\n")
 		builder.WriteString("红色边 = 循环依赖关系")
 		builder.WriteString("\"];\n")
@@ -464,29 +1411,68 @@ func (s *Weave[T]) GenerateDOTGraph() string {
 	return builder.String()
 }
 
-// PrintDependencyGraph 打印依赖图谱的文本表示
-func (s *Weave[T]) PrintDependencyGraph() string {
-	graph := s.GetDependencyGraph()
+// PrintDependencyGraph 打印依赖图谱的文本表示，PrintStable 选项用于 golden 文件比较
+func (s *Weave[T]) PrintDependencyGraph(opts ...PrintOption) string {
+	out, _ := s.printDependencyGraph(context.Background(), opts...)
+	return out
+}
 
+// PrintDependencyGraphCtx 与 PrintDependencyGraph 相同，ctx 取消或超时时停止查找循环依赖，
+// 返回已生成的内容（循环依赖列表标记为已截断）及 *ErrTruncated
+func (s *Weave[T]) PrintDependencyGraphCtx(ctx context.Context, opts ...PrintOption) (string, error) {
+	return s.printDependencyGraph(ctx, opts...)
+}
+
+func (s *Weave[T]) printDependencyGraph(ctx context.Context, opts ...PrintOption) (string, error) {
+	graph := s.GetDependencyGraph()
+	o := printOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
 	var builder strings.Builder
+	// 没有内容的部分在稳定输出模式下仍然输出
+	show := func(n int) bool { return n > 0 || o.stable }
+	none := func(n int) {
+		if n == 0 {
+			builder.WriteString("  (无)\n")
+		}
+	}
+	list := func(names []string) string {
+		if o.stable {
+			names = sortedCopy(names)
+		}
+		return strings.Join(names, ", ")
+	}
+
 	builder.WriteString("依赖图谱:\n")
 	builder.WriteString("================\n\n")
 
 	// 检测循环依赖
+	var truncated error
 	hasCycle, firstCycle := s.detectCircularDependency(graph.Dependencies)
 	if hasCycle {
+		// 获取所有循环依赖
+		var allCycles [][]string
+		if o.stable {
+			allCycles = s.stableCycles(graph)
+			firstCycle = allCycles[0]
+		} else {
+			allCycles, truncated = s.allCyclesCtx(ctx, graph)
+		}
+
 		builder.WriteString("⚠️  检测到循环依赖!\n")
 		builder.WriteString("第一个循环: ")
 		builder.WriteString(strings.Join(firstCycle, " -> "))
 		builder.WriteString("\n\n")
 
-		// 获取所有循环依赖
-		allCycles := s.GetAllCircularDependencies()
-		if len(allCycles) > 1 {
+		if len(allCycles) > 1 || o.stable || truncated != nil {
 			builder.WriteString("所有循环依赖:\n")
 			for i, cycle := range allCycles {
 				builder.WriteString(fmt.Sprintf("  循环 %d: %s\n", i+1, strings.Join(cycle, " -> ")))
 			}
+			if truncated != nil {
+				builder.WriteString(fmt.Sprintf("  (已截断: %v)\n", truncated))
+			}
 			builder.WriteString("\n")
 		}
 	} else {
@@ -503,55 +1489,136 @@ func (s *Weave[T]) PrintDependencyGraph() string {
 	rootServices := []string{}
 	leafServices := []string{}
 	middleServices := []string{}
+	isolatedServices := []string{}
 
 	for _, service := range services {
-		deps := graph.Dependencies[service]
-		dependents := graph.Dependents[service]
-
-		if len(deps) == 0 && len(dependents) > 0 {
+		switch graph.Category(service) {
+		case CategoryRoot:
 			rootServices = append(rootServices, service)
-		} else if len(deps) > 0 && len(dependents) == 0 {
+		case CategoryLeaf:
 			leafServices = append(leafServices, service)
-		} else {
+		case CategoryIsolated:
+			isolatedServices = append(isolatedServices, service)
+		default:
 			middleServices = append(middleServices, service)
 		}
 	}
 
 	// 显示根服务（无依赖）
-	if len(rootServices) > 0 {
+	if show(len(rootServices)) {
 		builder.WriteString("🌱 根服务 (无依赖):\n")
+		none(len(rootServices))
 		for _, service := range rootServices {
 			builder.WriteString(fmt.Sprintf("  📦 %s -> 被依赖于: %s\n",
-				service, strings.Join(graph.Dependents[service], ", ")))
+				service, list(graph.Dependents[service])))
 		}
 		builder.WriteString("\n")
 	}
 
 	// 显示叶服务（无被依赖）
-	if len(leafServices) > 0 {
+	if show(len(leafServices)) {
 		builder.WriteString("🍃 叶服务 (无被依赖):\n")
+		none(len(leafServices))
 		for _, service := range leafServices {
 			builder.WriteString(fmt.Sprintf("  📦 %s <- 依赖于: %s\n",
-				service, strings.Join(graph.Dependencies[service], ", ")))
+				service, list(graph.Dependencies[service])))
+		}
+		builder.WriteString("\n")
+	}
+
+	// 显示孤立服务（无依赖且无被依赖）
+	if show(len(isolatedServices)) {
+		builder.WriteString("⚪ 孤立服务 (无依赖且无被依赖):\n")
+		none(len(isolatedServices))
+		for _, service := range isolatedServices {
+			builder.WriteString(fmt.Sprintf("  📦 %s\n", service))
+		}
+		builder.WriteString("\n")
+	}
+
+	// 显示外部服务（通过 Mount 委托解析）
+	if show(len(graph.External)) {
+		externals := make([]string, 0, len(graph.External))
+		for service := range graph.External {
+			externals = append(externals, service)
+		}
+		sort.Strings(externals)
+
+		builder.WriteString("🌐 外部服务:\n")
+		none(len(graph.External))
+		for _, service := range externals {
+			builder.WriteString(fmt.Sprintf("  📦 %s -> 被依赖于: %s\n",
+				service, list(graph.Dependents[service])))
+		}
+		builder.WriteString("\n")
+	}
+
+	// 显示仅构建期服务
+	if show(len(graph.BuildOnly)) {
+		builder.WriteString("🔧 仅构建期服务 (构建后释放):\n")
+		none(len(graph.BuildOnly))
+		for _, service := range services {
+			if graph.BuildOnly[service] {
+				builder.WriteString(fmt.Sprintf("  📦 %s -> 被依赖于: %s\n", service, list(graph.Dependents[service])))
+			}
+		}
+		builder.WriteString("\n")
+	}
+
+	// 显示跨容器服务（通过 UseExternal 桥接）
+	if show(len(graph.Bridged) + len(graph.Exported)) {
+		builder.WriteString("🌉 跨容器服务:\n")
+		none(len(graph.Bridged) + len(graph.Exported))
+		for _, service := range services {
+			if source, ok := graph.Bridged[service]; ok {
+				builder.WriteString(fmt.Sprintf("  📦 %s <- 外部服务: %s\n", service, source))
+			}
+			if consumers, ok := graph.Exported[service]; ok {
+				builder.WriteString(fmt.Sprintf("  📦 %s -> 被外部引用为: %s\n", service, list(consumers)))
+			}
+		}
+		builder.WriteString("\n")
+	}
+
+	// 显示可选依赖及其在本次构建中是否存在
+	if show(len(graph.Optional)) {
+		builder.WriteString("🔌 可选依赖:\n")
+		none(len(graph.Optional))
+		for _, service := range services {
+			deps := make([]string, 0, len(graph.Optional[service]))
+			for dep, present := range graph.Optional[service] {
+				if present {
+					deps = append(deps, dep+" (present)")
+				} else {
+					deps = append(deps, dep+" (absent)")
+				}
+			}
+			if len(deps) > 0 {
+				sort.Strings(deps)
+				builder.WriteString(fmt.Sprintf("  📦 %s <- %s\n", service, strings.Join(deps, ", ")))
+			}
 		}
 		builder.WriteString("\n")
 	}
 
 	// 显示中间服务
-	if len(middleServices) > 0 {
+	if show(len(middleServices)) {
 		builder.WriteString("🔗 中间服务:\n")
+		if len(middleServices) == 0 {
+			builder.WriteString("  (无)\n\n")
+		}
 		for _, service := range middleServices {
 			builder.WriteString(fmt.Sprintf("  📦 %s\n", service))
 
 			if len(graph.Dependencies[service]) > 0 {
 				builder.WriteString("    ⬅️  依赖于: ")
-				builder.WriteString(strings.Join(graph.Dependencies[service], ", "))
+				builder.WriteString(list(graph.Dependencies[service]))
 				builder.WriteString("\n")
 			}
 
 			if len(graph.Dependents[service]) > 0 {
 				builder.WriteString("    ➡️  被依赖于: ")
-				builder.WriteString(strings.Join(graph.Dependents[service], ", "))
+				builder.WriteString(list(graph.Dependents[service]))
 				builder.WriteString("\n")
 			}
 			builder.WriteString("\n")
@@ -562,11 +1629,11 @@ func (s *Weave[T]) PrintDependencyGraph() string {
 	builder.WriteString("详细信息:\n")
 	builder.WriteString("================\n")
 	for _, service := range services {
-		builder.WriteString(fmt.Sprintf("服务: %s\n", service))
+		builder.WriteString(fmt.Sprintf("服务: %s\n", graph.label(service)))
 
 		if len(graph.Dependencies[service]) > 0 {
 			builder.WriteString("  依赖于: ")
-			builder.WriteString(strings.Join(graph.Dependencies[service], ", "))
+			builder.WriteString(list(graph.Dependencies[service]))
 			builder.WriteString("\n")
 		} else {
 			builder.WriteString("  依赖于: (无)\n")
@@ -574,7 +1641,7 @@ func (s *Weave[T]) PrintDependencyGraph() string {
 
 		if len(graph.Dependents[service]) > 0 {
 			builder.WriteString("  被依赖于: ")
-			builder.WriteString(strings.Join(graph.Dependents[service], ", "))
+			builder.WriteString(list(graph.Dependents[service]))
 			builder.WriteString("\n")
 		} else {
 			builder.WriteString("  被依赖于: (无)\n")
@@ -583,40 +1650,40 @@ func (s *Weave[T]) PrintDependencyGraph() string {
 		builder.WriteString("\n")
 	}
 
-	return builder.String()
+	return builder.String(), truncated
 }
 
 // Compact 压缩容器，释放构建时数据，节约内存
-func (s *Weave[T]) Compact() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	if !s.built {
-		panic("cannot compact weave before Build() is called")
+// 等同于 CompactWith，未传入选项时等同于 CompactAll，但在 Build 之前调用会 panic
+func (s *Weave[T]) Compact(opts ...CompactOptions) {
+	var o CompactOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if err := s.CompactWith(o); err != nil {
+		panic(err)
 	}
-	s.ctx = nil
-	s.ready = nil
-	s.entries.Range(func(name string, entry *entry[*T]) bool {
-		entry.builder = nil
-		entry.dependsOn = nil
-		return true
-	})
 }
 
 // Extract 提取所有已构建的服务实例，返回轻量级服务注册表
 // 使用此方法后，可以安全地释放DI容器实例
+// 提取的服务之后不能再通过 Swap、ReplaceBuilder 原地修改，除非开启 WithAllowLiveMutation；
+// 注册表记录提取时容器的代数，见 Generation
 func (s *Weave[T]) Extract() *Map[string, any] {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	if !s.built {
 		panic("cannot extract services before Build() is called")
 	}
 
 	registry := NewMap[string, any]()
+	registry.generation = s.generation
 
 	s.entries.Range(func(name string, entry *entry[*T]) bool {
-		if entry.built {
+		if entry.built && !entry.released {
 			registry.Set(name, entry.instance)
+			entry.extracted = true
 		}
 		return true
 	})
@@ -624,6 +1691,33 @@ func (s *Weave[T]) Extract() *Map[string, any] {
 	return registry
 }
 
+// ExtractNamespaced 提取所有已构建的服务实例，注册表中的名称均加上 prefix
+// 前缀规则与 Mount 一致：在父容器中以 Mount(prefix, di) 挂载时，解析的名称与这里的键相同，
+// 多个模块的注册表可以用不同的前缀通过 Merge 合并而不会相互覆盖
+func (s *Weave[T]) ExtractNamespaced(prefix string) *Map[string, any] {
+	extracted := s.Extract()
+	registry := NewMap[string, any]()
+	registry.generation = extracted.generation
+	extracted.Range(func(name string, instance any) bool {
+		registry.data[prefix+name] = instance
+		return true
+	})
+	return registry
+}
+
+// ExtractSorted 按名称排序提取所有已构建的服务实例，返回一一对应的名称与实例
+// 与 Extract 包含的服务相同，顺序稳定，适合序列化或跨运行比较
+func (s *Weave[T]) ExtractSorted() ([]string, []any) {
+	registry := s.Extract()
+	names := registry.Keys()
+	sort.Strings(names)
+	instances := make([]any, len(names))
+	for i, name := range names {
+		instances[i], _ = registry.Get(name)
+	}
+	return names, instances
+}
+
 // MustGetFromRegistry 从注册表中获取服务
 func MustGetFromRegistry[T any](registry *Map[string, any], name string) *T {
 	obj, ok := registry.Get(name)
@@ -633,6 +1727,15 @@ func MustGetFromRegistry[T any](registry *Map[string, any], name string) *T {
 	return obj.(*T)
 }
 
+// Getter 从注册表中查找服务并返回直接返回其指针的函数，查找和类型断言只执行一次
+// 服务不存在或类型不符时立即 panic；适合在热路径中反复获取同一个服务，注册表通常应先 Freeze
+func Getter[T any](registry *Map[string, any], name string) func() *T {
+	instance := MustGetFromRegistry[T](registry, name)
+	return func() *T {
+		return instance
+	}
+}
+
 // TryGetFromRegistry 从注册表中获取服务
 func TryGetFromRegistry[T any](registry *Map[string, any], name string) (*T, bool) {
 	obj, ok := registry.Get(name)
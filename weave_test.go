@@ -1,8 +1,11 @@
 package weave
 
 import (
+	"errors"
 	"fmt"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -358,6 +361,30 @@ func TestDI_NormalizeCycle(t *testing.T) {
 	}
 }
 
+// 辅助函数：为每个 spec 注册一个 ServiceA 服务，spec 形如 "app: database cache"
+// builder 依次解析冒号后列出的依赖，实例以服务名称及各依赖的实例名称命名，如 "app(database)(cache)"；
+// 返回每个服务被构建的次数
+func provideGraph(di *Weave[TestContext], specs ...string) map[string]int {
+	builds := make(map[string]int)
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, ":", 2)
+		name := strings.TrimSpace(parts[0])
+		var deps []string
+		if len(parts) == 2 {
+			deps = strings.Fields(parts[1])
+		}
+		Provide(di, name, func(ctx *TestContext) *ServiceA {
+			builds[name]++
+			label := name
+			for _, dep := range deps {
+				label += "(" + MustMake[TestContext, ServiceA](di, dep).Name + ")"
+			}
+			return &ServiceA{Name: label}
+		})
+	}
+	return builds
+}
+
 // 辅助函数：比较两个字符串切片是否相等
 func equalSlices(a, b []string) bool {
 	if len(a) != len(b) {
@@ -564,6 +591,34 @@ func TestDI_PrintDependencyGraph(t *testing.T) {
 	}
 }
 
+func TestDependencyGraph_String(t *testing.T) {
+	graph := &DependencyGraph{
+		Dependencies: map[string][]string{
+			"app":      {"cache", "database"},
+			"cache":    {"config"},
+			"database": {},
+		},
+		Dependents: map[string][]string{
+			"cache":    {"app"},
+			"database": {"app"},
+			"config":   {"cache"},
+		},
+		External: map[string]bool{"config": true},
+	}
+
+	want := `app: -> cache, database; <- (无)
+cache: -> config; <- app
+config (external): -> (无); <- cache
+database: -> (无); <- app
+`
+	if got := graph.String(); got != want {
+		t.Errorf("图谱文本错误:\n%s\n期望:\n%s", got, want)
+	}
+	if got := fmt.Sprint(graph); got != want {
+		t.Errorf("应该实现 fmt.Stringer:\n%s", got)
+	}
+}
+
 func TestDI_TryMake(t *testing.T) {
 	di := New[TestContext]()
 	ctx := &TestContext{Config: "test"}
@@ -621,17 +676,38 @@ func TestDI_BuildTwice(t *testing.T) {
 		return &ServiceA{Name: "ServiceA"}
 	})
 
+	if di.BuildCount() != 0 || di.LastBuildNoop() {
+		t.Error("构建前不应该有构建记录")
+	}
+
 	// 第一次构建
 	err := di.Build()
 	if err != nil {
 		t.Fatalf("第一次构建失败: %v", err)
 	}
+	if di.BuildCount() != 1 || di.LastBuildNoop() {
+		t.Errorf("第一次构建不是空操作，构建次数为 %d", di.BuildCount())
+	}
 
 	// 第二次构建应该成功（不重复构建）
 	err = di.Build()
 	if err != nil {
 		t.Fatalf("第二次构建失败: %v", err)
 	}
+	if di.BuildCount() != 2 || !di.LastBuildNoop() {
+		t.Errorf("第二次构建应该是空操作，构建次数为 %d", di.BuildCount())
+	}
+
+	// 注册新服务后需要重新构建
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		return &ServiceB{Name: "ServiceB"}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("第三次构建失败: %v", err)
+	}
+	if di.BuildCount() != 3 || di.LastBuildNoop() {
+		t.Errorf("注册新服务后的构建不是空操作，构建次数为 %d", di.BuildCount())
+	}
 
 	// 验证服务仍然可用
 	serviceA := MustMake[TestContext, ServiceA](di, "serviceA")
@@ -648,17 +724,42 @@ func BenchmarkDI_Build(b *testing.B) {
 		di.SetCtx(ctx)
 
 		// 注册多个服务
-		for j := 0; j < 10; j++ {
-			serviceName := "service" + string(rune('A'+j))
-			Provide(di, serviceName, func(ctx *TestContext) *ServiceA {
-				return &ServiceA{Name: serviceName}
-			})
+		names := make([]string, 10)
+		for j := range names {
+			names[j] = "service" + string(rune('A'+j))
 		}
+		ProvideN(di, names, func(ctx *TestContext, name string) *ServiceA {
+			return &ServiceA{Name: name}
+		})
 
 		di.Build()
 	}
 }
 
+func TestDI_ProvideN(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+
+	ProvideN(di, []string{"serviceA", "serviceB"}, func(ctx *TestContext, name string) *ServiceA {
+		return &ServiceA{Name: name}
+	})
+	ProvideNamedBuilder(di, "serviceC", func(ctx *TestContext, name string) *ServiceC {
+		return &ServiceC{Name: name, ServiceA: MustMake[TestContext, ServiceA](di, "serviceB")}
+	})
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	for _, name := range []string{"serviceA", "serviceB"} {
+		if got := MustMake[TestContext, ServiceA](di, name).Name; got != name {
+			t.Errorf("builder 应该收到注册的名称 %s，实际为 %s", name, got)
+		}
+	}
+	if c := MustMake[TestContext, ServiceC](di, "serviceC"); c.Name != "serviceC" || c.ServiceA.Name != "serviceB" {
+		t.Errorf("具名 builder 注册错误: %+v", c)
+	}
+}
+
 func BenchmarkDI_GetDependencyGraph(b *testing.B) {
 	di := New[TestContext]()
 	ctx := &TestContext{Config: "test"}
@@ -811,3 +912,388 @@ func TestDI_Extract(t *testing.T) {
 
 	t.Log("✅ Extract功能测试通过")
 }
+
+func TestDI_ExtractSorted(t *testing.T) {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "test"})
+	for _, name := range []string{"zeta", "alpha", "mid", "beta"} {
+		name := name
+		Provide(di, name, func(ctx *TestContext) *ServiceA {
+			return &ServiceA{Name: name}
+		})
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	names, instances := di.ExtractSorted()
+	if got := strings.Join(names, ","); got != "alpha,beta,mid,zeta" {
+		t.Errorf("名称应该按字母顺序排列: %s", got)
+	}
+	for i, name := range names {
+		if instances[i].(*ServiceA).Name != name {
+			t.Errorf("实例应该与名称一一对应: %s", name)
+		}
+	}
+}
+
+func TestDI_CategoryIsolated(t *testing.T) {
+	di := New[TestContext]()
+	ctx := &TestContext{Config: "test"}
+	di.SetCtx(ctx)
+
+	Provide(di, "rootService", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "Root"}
+	})
+	Provide(di, "middleService", func(ctx *TestContext) *ServiceB {
+		root := MustMake[TestContext, ServiceA](di, "rootService")
+		return &ServiceB{Name: "Middle", ServiceA: root}
+	})
+	Provide(di, "leafService", func(ctx *TestContext) *ServiceC {
+		middle := MustMake[TestContext, ServiceB](di, "middleService")
+		return &ServiceC{Name: "Leaf", ServiceB: middle}
+	})
+	Provide(di, "standalone", func(ctx *TestContext) *ServiceD {
+		return &ServiceD{Name: "Standalone"}
+	})
+
+	err := di.Build()
+	if err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	// 分类互斥且完备
+	expected := map[string]Category{
+		"rootService":   CategoryRoot,
+		"middleService": CategoryMiddle,
+		"leafService":   CategoryLeaf,
+		"standalone":    CategoryIsolated,
+	}
+	for name, want := range expected {
+		got, ok := di.Category(name)
+		if !ok || got != want {
+			t.Errorf("服务 %s 期望分类为 %s，实际为 %s", name, want, got)
+		}
+	}
+	if _, ok := di.Category("nonexistent"); ok {
+		t.Error("不存在的服务不应该有分类")
+	}
+
+	output := di.PrintDependencyGraph()
+	if !strings.Contains(output, "孤立服务") {
+		t.Error("输出应该包含孤立服务分类")
+	}
+	middle := output[strings.Index(output, "中间服务"):strings.Index(output, "详细信息")]
+	if strings.Contains(middle, "standalone") {
+		t.Error("孤立服务不应该出现在中间服务分类中")
+	}
+
+	dot := di.GenerateDOTGraph()
+	if !strings.Contains(dot, "\"standalone\" [fillcolor=lightgray") {
+		t.Error("孤立节点应该用灰色(lightgray)标记")
+	}
+}
+
+func TestDI_StronglyConnectedComponents(t *testing.T) {
+	di := New[TestContext]()
+	ctx := &TestContext{Config: "test"}
+	di.SetCtx(ctx)
+
+	// A -> B -> C -> A, B -> D -> B, E 独立依赖 A
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceB](di, "serviceB")
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		MustMake[TestContext, ServiceC](di, "serviceC")
+		MustMake[TestContext, ServiceD](di, "serviceD")
+		return &ServiceB{Name: "ServiceB"}
+	})
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+		MustMake[TestContext, ServiceA](di, "serviceA")
+		return &ServiceC{Name: "ServiceC"}
+	})
+	Provide(di, "serviceD", func(ctx *TestContext) *ServiceD {
+		MustMake[TestContext, ServiceB](di, "serviceB")
+		return &ServiceD{Name: "ServiceD"}
+	})
+	Provide(di, "serviceE", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceA](di, "serviceA")
+		return &ServiceA{Name: "ServiceE"}
+	})
+
+	err := di.Build()
+	if err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	components := di.StronglyConnectedComponents()
+	if len(components) != 2 {
+		t.Fatalf("期望2个强连通分量，实际为: %v", components)
+	}
+	if !equalSlices(components[0], []string{"serviceA", "serviceB", "serviceC", "serviceD"}) {
+		t.Errorf("循环簇错误: %v", components[0])
+	}
+	if !equalSlices(components[1], []string{"serviceE"}) {
+		t.Errorf("无循环的服务应该是单节点分量: %v", components[1])
+	}
+}
+
+func TestDI_GenerateCondensedDOT(t *testing.T) {
+	di := New[TestContext]()
+	ctx := &TestContext{Config: "test"}
+	di.SetCtx(ctx)
+
+	// A <-> B 构成循环，C 依赖 A
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		MustMake[TestContext, ServiceB](di, "serviceB")
+		return &ServiceA{Name: "ServiceA"}
+	})
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB {
+		MustMake[TestContext, ServiceA](di, "serviceA")
+		return &ServiceB{Name: "ServiceB"}
+	})
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+		MustMake[TestContext, ServiceA](di, "serviceA")
+		MustMake[TestContext, ServiceB](di, "serviceB")
+		return &ServiceC{Name: "ServiceC"}
+	})
+
+	err := di.Build()
+	if err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+
+	dot := di.GenerateCondensedDOT()
+	t.Logf("压缩DOT图:\n%s", dot)
+
+	if !strings.Contains(dot, "digraph CondensedDependencyGraph") {
+		t.Error("DOT图应该包含图名称")
+	}
+	if !strings.Contains(dot, "\"scc0\" [fillcolor=lightcoral, label=\"🔁 serviceA\\nserviceB\"]") {
+		t.Error("循环簇应该折叠为一个节点并列出成员")
+	}
+	if !strings.Contains(dot, "\"scc1\" [fillcolor=lightblue, label=\"serviceC\"]") {
+		t.Error("无循环的服务应该是普通节点")
+	}
+	if strings.Count(dot, "->") != 1 || !strings.Contains(dot, "\"scc0\" -> \"scc1\"") {
+		t.Error("分量之间的边应该去重，且不包含分量内部的边")
+	}
+}
+
+// newLargeWeave 创建包含 n 个服务、每个服务依赖之前最多 fanout 个服务的容器
+func newLargeWeave(n, fanout int) *Weave[TestContext] {
+	di := New[TestContext]()
+	di.SetCtx(&TestContext{Config: "bench"})
+	for i := 0; i < n; i++ {
+		i := i
+		Provide(di, fmt.Sprintf("service%05d", i), func(ctx *TestContext) *ServiceA {
+			for j := 1; j <= fanout && i-j >= 0; j++ {
+				MustMake[TestContext, ServiceA](di, fmt.Sprintf("service%05d", i-j))
+			}
+			return &ServiceA{Name: "bench"}
+		})
+	}
+	return di
+}
+
+func BenchmarkDI_BuildLarge(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		di := newLargeWeave(20000, 8)
+		di.Build()
+		di.GetDependencyGraph()
+	}
+}
+
+func BenchmarkDI_GetDependencyGraphLarge(b *testing.B) {
+	di := newLargeWeave(20000, 8)
+	di.Build()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		di.GetDependencyGraph()
+	}
+}
+
+// BenchmarkDI_RetainedLarge 统计构建完成后容器常驻的堆内存
+// graph-B 为与相同数量、没有依赖的服务的容器之差，即依赖图谱数据占用的内存
+func BenchmarkDI_RetainedLarge(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		retained := retainedAfterBuild(20000, 8)
+		b.ReportMetric(float64(retained), "retained-B")
+		b.ReportMetric(float64(retained-retainedAfterBuild(20000, 0)), "graph-B")
+	}
+}
+
+// retainedAfterBuild 返回构建完成后容器常驻的堆内存
+func retainedAfterBuild(n, fanout int) int64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	di := newLargeWeave(n, fanout)
+	di.Build()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(di)
+	return int64(after.HeapAlloc) - int64(before.HeapAlloc)
+}
+
+func ExampleBuildAll() {
+	ctx := &TestContext{Config: "production"}
+
+	// 注册、构建、提取一步完成
+	registry, err := BuildAll(ctx, func(di *Weave[TestContext]) {
+		Provide(di, "database", func(ctx *TestContext) *ServiceA {
+			return &ServiceA{Name: "PostgreSQL"}
+		})
+
+		Provide(di, "userService", func(ctx *TestContext) *ServiceB {
+			db := MustMake[TestContext, ServiceA](di, "database")
+			return &ServiceB{
+				Name:     "UserService",
+				ServiceA: db,
+			}
+		})
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	userService := MustGetFromRegistry[ServiceB](registry, "userService")
+	fmt.Printf("服务名称: %s\n", userService.Name)
+	fmt.Printf("数据库: %s\n", userService.ServiceA.Name)
+
+	// Output:
+	// 服务名称: UserService
+	// 数据库: PostgreSQL
+}
+
+func TestDI_BuildAllError(t *testing.T) {
+	chaos := fmt.Errorf("chaos")
+	_, err := BuildAll(&TestContext{Config: "test"}, func(di *Weave[TestContext]) {
+		Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+			return &ServiceA{Name: "ServiceA"}
+		})
+		di.FailBuild("serviceA", chaos)
+	})
+	if !errors.Is(err, chaos) {
+		t.Errorf("BuildAll应该保留完整的错误链，实际为: %v", err)
+	}
+}
+
+func TestDI_BuildWith(t *testing.T) {
+	di := New[TestContext]()
+	Provide(di, "serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: ctx.Config}
+	})
+
+	ctx := &TestContext{Config: "test"}
+	if err := di.BuildWith(ctx); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	if MustMake[TestContext, ServiceA](di, "serviceA").Name != "test" {
+		t.Error("builder 应该使用 BuildWith 传入的上下文")
+	}
+
+	// 相同的上下文可以重复构建
+	Provide(di, "serviceB", func(ctx *TestContext) *ServiceB { return &ServiceB{Name: ctx.Config} })
+	if err := di.BuildWith(ctx); err != nil {
+		t.Fatalf("使用相同上下文构建失败: %v", err)
+	}
+
+	Provide(di, "serviceC", func(ctx *TestContext) *ServiceC { return &ServiceC{Name: ctx.Config} })
+	if err := di.BuildWith(&TestContext{Config: "other"}); err == nil || !strings.Contains(err.Error(), "different context") {
+		t.Errorf("已基于其他上下文构建时应该报错，实际为 %v", err)
+	}
+	if di.CtxGeneration() != 1 {
+		t.Error("报错时不应该替换上下文")
+	}
+	if err := New[TestContext]().BuildWith(nil); err == nil {
+		t.Error("上下文为 nil 时应该报错")
+	}
+}
+
+func TestDI_WithRegisterHook(t *testing.T) {
+	var registered []string
+	di := New[TestContext](WithRegisterHook(func(name string) {
+		if !strings.Contains(name, ".") {
+			panic("service name must be namespaced: " + name)
+		}
+		registered = append(registered, name)
+	}))
+	di.SetCtx(&TestContext{Config: "test"})
+
+	Provide(di, "app.serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "A"}
+	})
+	ProvideInstance(di, "app.serviceB", &ServiceB{Name: "B"})
+	Provide(di, "app.serviceA", func(ctx *TestContext) *ServiceA {
+		return &ServiceA{Name: "A2"}
+	})
+	if !equalSlices(registered, []string{"app.serviceA", "app.serviceB", "app.serviceA"}) {
+		t.Errorf("注册事件错误: %v", registered)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("回调 panic 应该传递给调用方")
+			}
+		}()
+		Provide(di, "serviceC", func(ctx *TestContext) *ServiceC {
+			return &ServiceC{}
+		})
+	}()
+	if containsString(di.ServiceNames(), "serviceC") {
+		t.Error("被回调拒绝的服务不应该被注册")
+	}
+	if err := di.Build(); err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+}
+
+func TestDI_ProvideVariantsConcurrentBuild(t *testing.T) {
+	// 注册时设置的选项应该在条目对 Build 可见之前写好，配合 -race 运行
+	variants := map[string]func(di *Weave[TestContext], name string){
+		"interface": func(di *Weave[TestContext], name string) {
+			ProvideInterface(di, name, func(ctx *TestContext) greeter { return englishGreeter{} })
+		},
+		"buildOnly": func(di *Weave[TestContext], name string) {
+			ProvideBuildOnly(di, name, func(ctx *TestContext) *ServiceA { return &ServiceA{} })
+		},
+		"cached": func(di *Weave[TestContext], name string) {
+			ProvideCached(di, name, func() (*ServiceA, bool) { return nil, false }, func(ctx *TestContext) *ServiceA { return &ServiceA{} })
+		},
+		"optional": func(di *Weave[TestContext], name string) {
+			ProvideOptional(di, name, func(ctx *TestContext) *ServiceA { return &ServiceA{} })
+		},
+		"direct": func(di *Weave[TestContext], name string) {
+			ProvideDirect(di, name, func(ctx *TestContext) *ServiceA { return &ServiceA{} })
+		},
+	}
+	for kind, provide := range variants {
+		di := New[TestContext]()
+		di.SetCtx(&TestContext{Config: "test"})
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				provide(di, fmt.Sprintf("%s%d", kind, i))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				_ = di.Build()
+			}
+		}()
+		wg.Wait()
+		if err := di.Build(); err != nil {
+			t.Fatalf("%s: 构建失败: %v", kind, err)
+		}
+	}
+}